@@ -1,26 +1,27 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
 
 // Branch represents a git branch
 type Branch struct {
-	Name      string
-	IsCurrent bool
-	IsRemote  bool
-	Upstream  string // tracking branch
-	Ahead     int
-	Behind    int
+	Name       string
+	IsCurrent  bool
+	IsRemote   bool
+	Upstream   string // tracking branch
+	Ahead      int
+	Behind     int
 	LastCommit string // short commit message
 }
 
 // GetBranches returns all local branches with their status
-func GetBranches() ([]Branch, error) {
+func (r *Repo) GetBranches(ctx context.Context) ([]Branch, error) {
 	// Get branch list with upstream tracking info
 	// Format: %(refname:short)|%(upstream:short)|%(upstream:track)|%(HEAD)|%(subject)
-	output, err := Run("for-each-ref", "--format=%(refname:short)|%(upstream:short)|%(upstream:track)|%(HEAD)|%(subject)", "refs/heads/")
+	output, err := r.Run(ctx, "for-each-ref", "--format=%(refname:short)|%(upstream:short)|%(upstream:track)|%(HEAD)|%(subject)", "refs/heads/")
 	if err != nil {
 		return nil, err
 	}
@@ -70,25 +71,25 @@ func GetBranches() ([]Branch, error) {
 }
 
 // CheckoutBranch switches to the specified branch
-func CheckoutBranch(name string) error {
-	_, err := Run("checkout", name)
+func (r *Repo) CheckoutBranch(ctx context.Context, name string) error {
+	_, err := r.Run(ctx, "checkout", name)
 	return err
 }
 
 // CreateBranch creates a new branch from HEAD
-func CreateBranch(name string) error {
-	_, err := Run("checkout", "-b", name)
+func (r *Repo) CreateBranch(ctx context.Context, name string) error {
+	_, err := r.Run(ctx, "checkout", "-b", name)
 	return err
 }
 
 // DeleteBranch deletes a local branch
-func DeleteBranch(name string) error {
-	_, err := Run("branch", "-d", name)
+func (r *Repo) DeleteBranch(ctx context.Context, name string) error {
+	_, err := r.Run(ctx, "branch", "-d", name)
 	return err
 }
 
 // ForceDeleteBranch deletes a local branch even if not fully merged
-func ForceDeleteBranch(name string) error {
-	_, err := Run("branch", "-D", name)
+func (r *Repo) ForceDeleteBranch(ctx context.Context, name string) error {
+	_, err := r.Run(ctx, "branch", "-D", name)
 	return err
 }
@@ -11,7 +11,7 @@ func TestGetBranches_SingleBranch(t *testing.T) {
 
 	repo.InitialCommit()
 
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -39,7 +39,7 @@ func TestGetBranches_MultipleBranches(t *testing.T) {
 	repo.Git("branch", "feature-1")
 	repo.Git("branch", "feature-2")
 
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -70,7 +70,7 @@ func TestGetBranches_CurrentBranch(t *testing.T) {
 	repo.Git("branch", "other-branch")
 	repo.Git("checkout", "other-branch")
 
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -99,7 +99,7 @@ func TestGetBranches_LastCommit(t *testing.T) {
 
 	repo.CommitFile("test.txt", "content", "Test commit message")
 
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -123,7 +123,7 @@ func TestGetBranches_WithUpstream(t *testing.T) {
 
 	repo.PushToRemote()
 
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -152,7 +152,7 @@ func TestGetBranches_AheadBehind(t *testing.T) {
 	repo.CommitFile("local1.txt", "content1", "Local commit 1")
 	repo.CommitFile("local2.txt", "content2", "Local commit 2")
 
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -173,19 +173,19 @@ func TestCreateBranch(t *testing.T) {
 
 	repo.InitialCommit()
 
-	err := CreateBranch("new-feature")
+	err := repo.Repo.CreateBranch(t.Context(), "new-feature")
 	if err != nil {
 		t.Fatalf("CreateBranch failed: %v", err)
 	}
 
 	// Verify branch was created and we're on it
-	currentBranch := GetBranch()
+	currentBranch := repo.Repo.GetBranch(t.Context())
 	if currentBranch != "new-feature" {
 		t.Errorf("expected to be on 'new-feature', got %q", currentBranch)
 	}
 
 	// Verify it shows up in branch list
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -213,7 +213,7 @@ func TestCreateBranch_InvalidName(t *testing.T) {
 	repo.InitialCommit()
 
 	// Branch names with spaces are invalid
-	err := CreateBranch("invalid branch name")
+	err := repo.Repo.CreateBranch(t.Context(), "invalid branch name")
 	if err == nil {
 		t.Error("expected error for invalid branch name")
 	}
@@ -226,12 +226,12 @@ func TestCheckoutBranch(t *testing.T) {
 	repo.InitialCommit()
 	repo.Git("branch", "other-branch")
 
-	err := CheckoutBranch("other-branch")
+	err := repo.Repo.CheckoutBranch(t.Context(), "other-branch")
 	if err != nil {
 		t.Fatalf("CheckoutBranch failed: %v", err)
 	}
 
-	currentBranch := GetBranch()
+	currentBranch := repo.Repo.GetBranch(t.Context())
 	if currentBranch != "other-branch" {
 		t.Errorf("expected to be on 'other-branch', got %q", currentBranch)
 	}
@@ -243,7 +243,7 @@ func TestCheckoutBranch_NonExistent(t *testing.T) {
 
 	repo.InitialCommit()
 
-	err := CheckoutBranch("nonexistent-branch")
+	err := repo.Repo.CheckoutBranch(t.Context(), "nonexistent-branch")
 	if err == nil {
 		t.Error("expected error for non-existent branch")
 	}
@@ -261,7 +261,7 @@ func TestCheckoutBranch_WithChanges(t *testing.T) {
 	repo.Git("add", "README.md")
 
 	// Checkout should still work for non-conflicting changes
-	err := CheckoutBranch("other-branch")
+	err := repo.Repo.CheckoutBranch(t.Context(), "other-branch")
 	// This may or may not fail depending on if there are conflicts
 	_ = err // Result depends on git behavior with staged changes
 }
@@ -274,7 +274,7 @@ func TestDeleteBranch(t *testing.T) {
 	repo.Git("branch", "to-delete")
 
 	// Verify branch exists
-	branches, _ := GetBranches()
+	branches, _ := repo.Repo.GetBranches(t.Context())
 	found := false
 	for _, b := range branches {
 		if b.Name == "to-delete" {
@@ -286,13 +286,13 @@ func TestDeleteBranch(t *testing.T) {
 		t.Fatal("expected to-delete branch to exist")
 	}
 
-	err := DeleteBranch("to-delete")
+	err := repo.Repo.DeleteBranch(t.Context(), "to-delete")
 	if err != nil {
 		t.Fatalf("DeleteBranch failed: %v", err)
 	}
 
 	// Verify branch was deleted
-	branches, _ = GetBranches()
+	branches, _ = repo.Repo.GetBranches(t.Context())
 	for _, b := range branches {
 		if b.Name == "to-delete" {
 			t.Error("expected to-delete branch to be gone")
@@ -306,8 +306,8 @@ func TestDeleteBranch_CurrentBranch(t *testing.T) {
 
 	repo.InitialCommit()
 
-	currentBranch := GetBranch()
-	err := DeleteBranch(currentBranch)
+	currentBranch := repo.Repo.GetBranch(t.Context())
+	err := repo.Repo.DeleteBranch(t.Context(), currentBranch)
 	if err == nil {
 		t.Error("expected error when deleting current branch")
 	}
@@ -323,7 +323,7 @@ func TestDeleteBranch_UnmergedBranch(t *testing.T) {
 	repo.Git("checkout", "-")
 
 	// Regular delete should fail for unmerged branch
-	err := DeleteBranch("unmerged")
+	err := repo.Repo.DeleteBranch(t.Context(), "unmerged")
 	if err == nil {
 		t.Error("expected error when deleting unmerged branch with -d")
 	}
@@ -339,13 +339,13 @@ func TestForceDeleteBranch(t *testing.T) {
 	repo.Git("checkout", "-")
 
 	// Force delete should succeed
-	err := ForceDeleteBranch("unmerged")
+	err := repo.Repo.ForceDeleteBranch(t.Context(), "unmerged")
 	if err != nil {
 		t.Fatalf("ForceDeleteBranch failed: %v", err)
 	}
 
 	// Verify branch was deleted
-	branches, _ := GetBranches()
+	branches, _ := repo.Repo.GetBranches(t.Context())
 	for _, b := range branches {
 		if b.Name == "unmerged" {
 			t.Error("expected unmerged branch to be gone after force delete")
@@ -359,7 +359,7 @@ func TestForceDeleteBranch_NonExistent(t *testing.T) {
 
 	repo.InitialCommit()
 
-	err := ForceDeleteBranch("nonexistent")
+	err := repo.Repo.ForceDeleteBranch(t.Context(), "nonexistent")
 	if err == nil {
 		t.Error("expected error for non-existent branch")
 	}
@@ -375,7 +375,7 @@ func TestGetBranches_BranchOnDifferentCommit(t *testing.T) {
 	// Make new commits on master
 	repo.CommitFile("new.txt", "new content", "New commit on master")
 
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -400,7 +400,7 @@ func TestBranch_IsRemote(t *testing.T) {
 
 	repo.InitialCommit()
 
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
@@ -417,13 +417,13 @@ func TestCheckoutBranch_SwitchBack(t *testing.T) {
 	defer repo.Cleanup()
 
 	repo.InitialCommit()
-	originalBranch := GetBranch()
+	originalBranch := repo.Repo.GetBranch(t.Context())
 
 	repo.Git("branch", "feature")
-	CheckoutBranch("feature")
-	CheckoutBranch(originalBranch)
+	repo.Repo.CheckoutBranch(t.Context(), "feature")
+	repo.Repo.CheckoutBranch(t.Context(), originalBranch)
 
-	currentBranch := GetBranch()
+	currentBranch := repo.Repo.GetBranch(t.Context())
 	if currentBranch != originalBranch {
 		t.Errorf("expected to be back on %q, got %q", originalBranch, currentBranch)
 	}
@@ -437,13 +437,13 @@ func TestCreateBranch_FromSpecificCommit(t *testing.T) {
 	repo.CommitFile("file2.txt", "content2", "Second commit")
 
 	// CreateBranch creates from HEAD, so the new branch should have both commits
-	err := CreateBranch("from-head")
+	err := repo.Repo.CreateBranch(t.Context(), "from-head")
 	if err != nil {
 		t.Fatalf("CreateBranch failed: %v", err)
 	}
 
 	// Verify the new branch has the second commit as HEAD
-	branches, _ := GetBranches()
+	branches, _ := repo.Repo.GetBranches(t.Context())
 	for _, b := range branches {
 		if b.Name == "from-head" {
 			if b.LastCommit != "Second commit" {
@@ -459,7 +459,7 @@ func TestGetBranches_EmptyRepo(t *testing.T) {
 	defer repo.Cleanup()
 
 	// Don't create initial commit - repo has no branches yet
-	branches, err := GetBranches()
+	branches, err := repo.Repo.GetBranches(t.Context())
 	if err != nil {
 		t.Fatalf("GetBranches failed: %v", err)
 	}
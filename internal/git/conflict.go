@@ -0,0 +1,422 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Index stages of an unmerged path
+const (
+	StageBase   = 1 // common ancestor
+	StageOurs   = 2 // HEAD side
+	StageTheirs = 3 // side being merged in
+)
+
+// MarkResolved records the working tree version of a conflicted file as
+// its resolution, removing it from the index if it was deleted
+func (r *Repo) MarkResolved(ctx context.Context, path string) error {
+	_, err := r.Run(ctx, "add", "-A", "--", path)
+	return err
+}
+
+// TakeOurs resolves a conflicted file with our version, or deletes it if
+// our side deleted it
+func (r *Repo) TakeOurs(ctx context.Context, path string) error {
+	return r.takeSide(ctx, path, StageOurs, "--ours")
+}
+
+// TakeTheirs resolves a conflicted file with their version, or deletes it
+// if their side deleted it
+func (r *Repo) TakeTheirs(ctx context.Context, path string) error {
+	return r.takeSide(ctx, path, StageTheirs, "--theirs")
+}
+
+func (r *Repo) takeSide(ctx context.Context, path string, stage int, flag string) error {
+	stages, err := r.conflictStages(ctx, path)
+	if err != nil {
+		return err
+	}
+	if len(stages) == 0 {
+		return fmt.Errorf("%s is not in conflict", path)
+	}
+	if !stages[stage] {
+		// That side deleted the file
+		_, err := r.Run(ctx, "rm", "--quiet", "--force", "--", path)
+		return err
+	}
+	if _, err := r.Run(ctx, "checkout", flag, "--", path); err != nil {
+		return err
+	}
+	_, err = r.Run(ctx, "add", "--", path)
+	return err
+}
+
+// conflictStages returns which index stages exist for an unmerged path
+func (r *Repo) conflictStages(ctx context.Context, path string) (map[int]bool, error) {
+	// Format: <mode> <object> <stage>\t<path>
+	output, err := r.Run(ctx, "ls-files", "-z", "--unmerged", "--", path)
+	if err != nil {
+		return nil, err
+	}
+	stages := make(map[int]bool)
+	for _, record := range strings.Split(output, "\x00") {
+		info, _, ok := strings.Cut(record, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(info)
+		if len(fields) != 3 {
+			continue
+		}
+		var stage int
+		fmt.Sscanf(fields[2], "%d", &stage)
+		stages[stage] = true
+	}
+	return stages, nil
+}
+
+// Resolution is the choice made for a single conflict hunk
+type Resolution int
+
+const (
+	Unresolved Resolution = iota
+	ResolveOurs
+	ResolveTheirs
+	ResolveBoth // ours followed by theirs
+)
+
+// ConflictHunk is one conflicted region of a file
+type ConflictHunk struct {
+	Ours       []string
+	Base       []string
+	Theirs     []string
+	Resolution Resolution
+	markers    conflictMarkers
+}
+
+// conflictMarkers are a hunk's marker lines as found in the file, so an
+// unresolved hunk is written back the way git wrote it. base is empty if
+// the file had no base section (conflictstyle=merge).
+type conflictMarkers struct {
+	ours, base, separator, theirs string
+}
+
+// defaultMarkers are used for hunks that weren't parsed from a file
+var defaultMarkers = conflictMarkers{"<<<<<<< ours", "||||||| base", "=======", ">>>>>>> theirs"}
+
+// Lines returns the hunk's resolved lines, or nil if it is unresolved
+func (h *ConflictHunk) Lines() []string {
+	switch h.Resolution {
+	case ResolveOurs:
+		return h.Ours
+	case ResolveTheirs:
+		return h.Theirs
+	case ResolveBoth:
+		return append(append([]string{}, h.Ours...), h.Theirs...)
+	default:
+		return nil
+	}
+}
+
+// ConflictSection is a run of lines both sides agree on, or a conflict hunk
+type ConflictSection struct {
+	Lines    []string
+	Conflict *ConflictHunk // non-nil for conflicted regions
+}
+
+// ConflictFile is a conflicted file split into agreed text and conflict hunks
+type ConflictFile struct {
+	Path            string // Path relative to repo root
+	DisplayPath     string // Path relative to cwd
+	Sections        []ConflictSection
+	TrailingNewline bool
+	Original        string // working tree content as last read or written
+}
+
+// Clone returns a copy of the file that shares no hunks with f
+func (f *ConflictFile) Clone() *ConflictFile {
+	clone := *f
+	clone.Sections = slices.Clone(f.Sections)
+	for i, s := range clone.Sections {
+		if s.Conflict != nil {
+			hunk := *s.Conflict
+			clone.Sections[i].Conflict = &hunk
+		}
+	}
+	return &clone
+}
+
+// Hunks returns the file's conflict hunks in order
+func (f *ConflictFile) Hunks() []*ConflictHunk {
+	var hunks []*ConflictHunk
+	for _, s := range f.Sections {
+		if s.Conflict != nil {
+			hunks = append(hunks, s.Conflict)
+		}
+	}
+	return hunks
+}
+
+// IsResolved returns true once every hunk has a resolution
+func (f *ConflictFile) IsResolved() bool {
+	for _, h := range f.Hunks() {
+		if h.Resolution == Unresolved {
+			return false
+		}
+	}
+	return true
+}
+
+// Content renders the file, writing unresolved hunks back as diff3 markers
+func (f *ConflictFile) Content() string {
+	var lines []string
+	for _, s := range f.Sections {
+		h := s.Conflict
+		switch {
+		case h == nil:
+			lines = append(lines, s.Lines...)
+		case h.Resolution != Unresolved:
+			lines = append(lines, h.Lines()...)
+		default:
+			markers := h.markers
+			if markers == (conflictMarkers{}) {
+				markers = defaultMarkers
+			}
+			lines = append(lines, markers.ours)
+			lines = append(lines, h.Ours...)
+			if markers.base != "" {
+				lines = append(lines, markers.base)
+				lines = append(lines, h.Base...)
+			}
+			lines = append(lines, markers.separator)
+			lines = append(lines, h.Theirs...)
+			lines = append(lines, markers.theirs)
+		}
+	}
+	content := strings.Join(lines, "\n")
+	if f.TrailingNewline && len(lines) > 0 {
+		content += "\n"
+	}
+	return content
+}
+
+// GetConflict parses the conflict markers in the working tree file, so
+// blocks resolved earlier and edits made by hand are kept. Blocks without
+// a base section (conflictstyle=merge) get their base from the index
+// stages.
+func (r *Repo) GetConflict(ctx context.Context, path string) (*ConflictFile, error) {
+	stages, err := r.conflictStages(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("%s is not in conflict", path)
+	}
+	if !stages[StageOurs] || !stages[StageTheirs] {
+		return nil, fmt.Errorf("%s was deleted on one side; resolve it with ours or theirs", path)
+	}
+	content, err := os.ReadFile(filepath.Join(r.root, path))
+	if err != nil {
+		return nil, err
+	}
+
+	file := ParseConflictMarkers(string(content))
+	file.Path = path
+	file.DisplayPath = r.ToDisplayPath(path)
+	file.Original = string(content)
+	if slices.ContainsFunc(file.Hunks(), func(h *ConflictHunk) bool { return h.markers.base == "" }) {
+		if err := r.fillBases(ctx, file); err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// fillBases sets the base of hunks that have none by merging the index
+// stages again with diff3 markers. git joins nearby conflicts and trims
+// lines both sides agree on, so a hunk may span several diff3 hunks and
+// the text between them.
+func (r *Repo) fillBases(ctx context.Context, file *ConflictFile) error {
+	merged, err := r.mergeStages(ctx, file.Path)
+	if err != nil {
+		return err
+	}
+	sections := ParseConflictMarkers(merged).Sections
+	next := 0
+	for _, h := range file.Hunks() {
+		if h.markers.base != "" {
+			continue
+		}
+	search:
+		for start := next; start < len(sections); start++ {
+			if sections[start].Conflict == nil {
+				continue
+			}
+			var ours, base, theirs []string
+			for end := start; end < len(sections); end++ {
+				if c := sections[end].Conflict; c != nil {
+					ours = append(ours, c.Ours...)
+					base = append(base, c.Base...)
+					theirs = append(theirs, c.Theirs...)
+				} else {
+					ours = append(ours, sections[end].Lines...)
+					base = append(base, sections[end].Lines...)
+					theirs = append(theirs, sections[end].Lines...)
+					continue
+				}
+				if sameChange(ours, theirs, h) {
+					h.Base = base
+					next = end + 1
+					break search
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sameChange reports whether ours and theirs are the hunk's sides with
+// the same lines added before and after both
+func sameChange(ours, theirs []string, h *ConflictHunk) bool {
+	extra := len(ours) - len(h.Ours)
+	if extra < 0 || len(theirs)-len(h.Theirs) != extra {
+		return false
+	}
+	for before := 0; before <= extra; before++ {
+		after := extra - before
+		if slices.Equal(ours[:before], theirs[:before]) &&
+			slices.Equal(ours[before:len(ours)-after], h.Ours) &&
+			slices.Equal(theirs[before:len(theirs)-after], h.Theirs) &&
+			slices.Equal(ours[len(ours)-after:], theirs[len(theirs)-after:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeStages merges the index stages of a conflicted file with diff3
+// markers, against an empty base if both sides added it
+func (r *Repo) mergeStages(ctx context.Context, path string) (string, error) {
+	// Format: <base> <ours> <theirs>\t<path>, with "." for a missing stage
+	output, err := r.Run(ctx, "checkout-index", "--stage=all", "--temp", "--", path)
+	if err != nil {
+		return "", err
+	}
+	temps, _, _ := strings.Cut(strings.TrimSpace(output), "\t")
+	stages := strings.Fields(temps)
+	defer func() {
+		for _, temp := range stages {
+			if temp != "." {
+				os.Remove(filepath.Join(r.root, temp))
+			}
+		}
+	}()
+	if len(stages) != 3 || stages[StageOurs-1] == "." || stages[StageTheirs-1] == "." {
+		return "", fmt.Errorf("%s is not in conflict", path)
+	}
+	if stages[StageBase-1] == "." {
+		// Added on both sides: merge against an empty base
+		empty, err := r.Run(ctx, "hash-object", "-w", "--stdin")
+		if err != nil {
+			return "", err
+		}
+		tempOut, err := r.Run(ctx, "unpack-file", strings.TrimSpace(empty))
+		if err != nil {
+			return "", err
+		}
+		stages[StageBase-1] = strings.TrimSpace(tempOut)
+	}
+
+	// merge-file exits with the number of conflicts, so only fail on a real error
+	merged, err := r.RunAllowFailure(ctx, "merge-file", "-p", "--diff3",
+		"-L", "ours", "-L", "base", "-L", "theirs",
+		stages[StageOurs-1], stages[StageBase-1], stages[StageTheirs-1])
+	var exitErr *ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.Code >= 128) {
+		return "", err
+	}
+	return merged, nil
+}
+
+// ParseConflictMarkers splits diff3-style merge output into sections
+func ParseConflictMarkers(content string) *ConflictFile {
+	file := &ConflictFile{TrailingNewline: strings.HasSuffix(content, "\n")}
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return file
+	}
+
+	var text []string
+	var hunk *ConflictHunk
+	part := &text // where the next line goes
+	flushText := func() {
+		if len(text) > 0 {
+			file.Sections = append(file.Sections, ConflictSection{Lines: text})
+			text = nil
+		}
+	}
+
+	// Each marker counts once per hunk and in order: a "=======" line in
+	// the theirs section, say a Markdown underline, is content
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<") && hunk == nil:
+			flushText()
+			hunk = &ConflictHunk{markers: conflictMarkers{ours: line}}
+			part = &hunk.Ours
+		case strings.HasPrefix(line, "|||||||") && hunk != nil && hunk.markers.base == "" && hunk.markers.separator == "":
+			hunk.markers.base = line
+			part = &hunk.Base
+		case strings.HasPrefix(line, "=======") && hunk != nil && hunk.markers.separator == "":
+			hunk.markers.separator = line
+			part = &hunk.Theirs
+		case strings.HasPrefix(line, ">>>>>>>") && hunk != nil:
+			hunk.markers.theirs = line
+			file.Sections = append(file.Sections, ConflictSection{Conflict: hunk})
+			hunk = nil
+			part = &text
+		default:
+			*part = append(*part, line)
+		}
+	}
+	if hunk != nil {
+		// Unterminated conflict: keep what was parsed so nothing is lost
+		file.Sections = append(file.Sections, ConflictSection{Conflict: hunk})
+	}
+	flushText()
+	return file
+}
+
+// WriteConflict writes the file's current resolution to the working tree,
+// and marks it resolved once every hunk has been decided. The working
+// tree file must still hold file.Original: if it was edited elsewhere
+// since it was read, WriteConflict returns ErrFileChanged rather than
+// overwrite the edits.
+func (r *Repo) WriteConflict(ctx context.Context, file *ConflictFile) error {
+	fullPath := filepath.Join(r.root, file.Path)
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(fullPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	current, err := os.ReadFile(fullPath)
+	if err != nil {
+		return err
+	}
+	if string(current) != file.Original {
+		return fmt.Errorf("%s: %w", file.Path, ErrFileChanged)
+	}
+	content := file.Content()
+	if err := os.WriteFile(fullPath, []byte(content), mode); err != nil {
+		return err
+	}
+	file.Original = content
+	if !file.IsResolved() {
+		return nil
+	}
+	return r.MarkResolved(ctx, file.Path)
+}
@@ -0,0 +1,392 @@
+package git
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetStatus_Conflicted(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", "base\n", "ours\n", "theirs\n")
+
+	status, err := repo.Repo.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	if len(status.Conflicted) != 1 {
+		t.Fatalf("expected 1 conflicted file, got %d", len(status.Conflicted))
+	}
+	f := status.Conflicted[0]
+	if f.Path != "file.txt" || !f.IsConflicted() {
+		t.Errorf("unexpected conflicted entry %+v", f)
+	}
+	if f.ConflictDescription() != "both modified" {
+		t.Errorf("expected 'both modified', got %q", f.ConflictDescription())
+	}
+	if len(status.Staged) != 0 || len(status.Unstaged) != 0 {
+		t.Errorf("conflicted file should not appear in staged/unstaged: %+v %+v", status.Staged, status.Unstaged)
+	}
+	if status.IsEmpty() {
+		t.Error("status with conflicts should not be empty")
+	}
+
+	base := strings.TrimSpace(repo.Git("merge-base", "HEAD", "MERGE_HEAD"))
+	for i, rev := range []string{base + ":file.txt", "HEAD:file.txt", "MERGE_HEAD:file.txt"} {
+		want := strings.TrimSpace(repo.Git("rev-parse", rev))
+		if f.Stages[i].OID != want || f.Stages[i].Mode != "100644" {
+			t.Errorf("stage %d = %+v, want %s", i+1, f.Stages[i], want)
+		}
+	}
+	if f.HeadOID != f.Stages[1].OID {
+		t.Errorf("HeadOID = %s, want ours %s", f.HeadOID, f.Stages[1].OID)
+	}
+}
+
+func TestTakeOurs(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", "base\n", "ours\n", "theirs\n")
+
+	if err := repo.Repo.TakeOurs(t.Context(), "file.txt"); err != nil {
+		t.Fatalf("TakeOurs failed: %v", err)
+	}
+
+	if content := repo.ReadFile("file.txt"); content != "ours\n" {
+		t.Errorf("expected our version, got %q", content)
+	}
+	assertResolved(t, repo, "file.txt")
+}
+
+func TestTakeTheirs(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", "base\n", "ours\n", "theirs\n")
+
+	if err := repo.Repo.TakeTheirs(t.Context(), "file.txt"); err != nil {
+		t.Fatalf("TakeTheirs failed: %v", err)
+	}
+
+	if content := repo.ReadFile("file.txt"); content != "theirs\n" {
+		t.Errorf("expected their version, got %q", content)
+	}
+	assertResolved(t, repo, "file.txt")
+}
+
+func TestTakeTheirs_DeletedByThem(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CommitFile("file.txt", "base\n", "base")
+	repo.Git("checkout", "-b", "theirs")
+	repo.Git("rm", "file.txt")
+	repo.Git("commit", "-m", "delete")
+	repo.Git("checkout", "-")
+	repo.CommitFile("file.txt", "ours\n", "ours")
+	repo.GitAllowFailure("merge", "theirs")
+
+	status, _ := repo.Repo.GetStatus(t.Context())
+	if len(status.Conflicted) != 1 || status.Conflicted[0].ConflictDescription() != "deleted by them" {
+		t.Fatalf("expected a 'deleted by them' conflict, got %+v", status.Conflicted)
+	}
+
+	if err := repo.Repo.TakeTheirs(t.Context(), "file.txt"); err != nil {
+		t.Fatalf("TakeTheirs failed: %v", err)
+	}
+	if repo.FileExists("file.txt") {
+		t.Error("taking their deletion should remove the file")
+	}
+	assertResolved(t, repo, "file.txt")
+}
+
+func TestMarkResolved(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", "base\n", "ours\n", "theirs\n")
+
+	repo.WriteFile("file.txt", "merged\n")
+	if err := repo.Repo.MarkResolved(t.Context(), "file.txt"); err != nil {
+		t.Fatalf("MarkResolved failed: %v", err)
+	}
+
+	assertResolved(t, repo, "file.txt")
+	if staged := repo.Git("show", ":file.txt"); staged != "merged\n" {
+		t.Errorf("expected working tree version to be staged, got %q", staged)
+	}
+}
+
+func TestTakeOurs_NotConflicted(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("file.txt", "content\n", "initial")
+
+	if err := repo.Repo.TakeOurs(t.Context(), "file.txt"); err == nil {
+		t.Error("expected an error for a file that is not in conflict")
+	}
+}
+
+func assertResolved(t *testing.T, repo *TestRepo, path string) {
+	t.Helper()
+	if unmerged := repo.Git("ls-files", "--unmerged", "--", path); unmerged != "" {
+		t.Errorf("expected %s to be resolved, still unmerged:\n%s", path, unmerged)
+	}
+}
+
+func TestParseConflictMarkers(t *testing.T) {
+	content := "a\n<<<<<<< ours\nO\n||||||| base\nb\n=======\nT1\nT2\n>>>>>>> theirs\nc\n"
+
+	file := ParseConflictMarkers(content)
+
+	if len(file.Sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(file.Sections))
+	}
+	hunks := file.Hunks()
+	if len(hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(hunks))
+	}
+	h := hunks[0]
+	if strings.Join(h.Ours, ",") != "O" || strings.Join(h.Base, ",") != "b" || strings.Join(h.Theirs, ",") != "T1,T2" {
+		t.Errorf("unexpected hunk %+v", h)
+	}
+	if file.IsResolved() {
+		t.Error("file with an unresolved hunk should not be resolved")
+	}
+	if got := file.Content(); got != content {
+		t.Errorf("unresolved content should round-trip, got %q", got)
+	}
+}
+
+func TestParseConflictMarkers_MarkerLikeContent(t *testing.T) {
+	content := "<<<<<<< ours\nTitle\n=======\nHeading\n=======\n||||||| not a base\n>>>>>>> theirs\n"
+
+	file := ParseConflictMarkers(content)
+	hunks := file.Hunks()
+	if len(hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(hunks))
+	}
+	h := hunks[0]
+	if strings.Join(h.Ours, ",") != "Title" || len(h.Base) != 0 || strings.Join(h.Theirs, ",") != "Heading,=======,||||||| not a base" {
+		t.Errorf("marker-like lines in theirs should be content, got %+v", h)
+	}
+	if got := file.Content(); got != content {
+		t.Errorf("unresolved content should round-trip, got %q", got)
+	}
+	h.Resolution = ResolveTheirs
+	if got := file.Content(); got != "Heading\n=======\n||||||| not a base\n" {
+		t.Errorf("taking theirs should keep its underline, got %q", got)
+	}
+}
+
+func TestConflictFile_Content(t *testing.T) {
+	tests := []struct {
+		resolution Resolution
+		want       string
+	}{
+		{ResolveOurs, "a\nO\nc\n"},
+		{ResolveTheirs, "a\nT\nc\n"},
+		{ResolveBoth, "a\nO\nT\nc\n"},
+	}
+
+	for _, tt := range tests {
+		file := ParseConflictMarkers("a\n<<<<<<< ours\nO\n||||||| base\nb\n=======\nT\n>>>>>>> theirs\nc\n")
+		file.Hunks()[0].Resolution = tt.resolution
+		if !file.IsResolved() {
+			t.Errorf("resolution %d: expected file to be resolved", tt.resolution)
+		}
+		if got := file.Content(); got != tt.want {
+			t.Errorf("resolution %d: content = %q, want %q", tt.resolution, got, tt.want)
+		}
+	}
+}
+
+// twoConflicts has its two conflicts far enough apart that git keeps them
+// as separate hunks
+var twoConflicts = [3]string{
+	"1\nbase\n3\n4\n5\n6\n7\nbase\n9\n",
+	"1\nours\n3\n4\n5\n6\n7\nours2\n9\n",
+	"1\ntheirs\n3\n4\n5\n6\n7\ntheirs2\n9\n",
+}
+
+func TestConflictFile_Clone(t *testing.T) {
+	file := ParseConflictMarkers("a\n<<<<<<< ours\nO\n=======\nT\n>>>>>>> theirs\n")
+	clone := file.Clone()
+	file.Hunks()[0].Resolution = ResolveOurs
+	if clone.IsResolved() || clone.Content() == file.Content() {
+		t.Error("resolving the original should not change the clone")
+	}
+}
+
+func TestGetConflict(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", twoConflicts[0], twoConflicts[1], twoConflicts[2])
+
+	file, err := repo.Repo.GetConflict(t.Context(), "file.txt")
+	if err != nil {
+		t.Fatalf("GetConflict failed: %v", err)
+	}
+
+	hunks := file.Hunks()
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(hunks))
+	}
+	if strings.Join(hunks[0].Base, "") != "base" || strings.Join(hunks[0].Ours, "") != "ours" || strings.Join(hunks[0].Theirs, "") != "theirs" {
+		t.Errorf("unexpected first hunk %+v", hunks[0])
+	}
+	// The file's own markers are kept for unresolved hunks
+	if got := file.Content(); got != repo.ReadFile("file.txt") || !strings.Contains(got, "<<<<<<< HEAD") {
+		t.Errorf("unresolved content should match the working tree, got %q", got)
+	}
+
+	// Temp files from checkout-index must not be left behind
+	status, _ := repo.Repo.GetStatus(t.Context())
+	if len(status.Untracked) != 0 {
+		t.Errorf("expected no stray temp files, got %+v", status.Untracked)
+	}
+}
+
+func TestGetConflict_JoinedHunks(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	// git joins conflicts this close into one hunk
+	repo.CreateConflict("file.txt", "1\nbase\n3\n4\nbase\n6\n", "1\nours\n3\n4\nours2\n6\n", "1\ntheirs\n3\n4\ntheirs2\n6\n")
+
+	file, err := repo.Repo.GetConflict(t.Context(), "file.txt")
+	if err != nil {
+		t.Fatalf("GetConflict failed: %v", err)
+	}
+	hunks := file.Hunks()
+	if len(hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(hunks))
+	}
+	if got := strings.Join(hunks[0].Base, ","); got != "base,3,4,base" {
+		t.Errorf("Base = %q, want the base of both conflicts and the lines between", got)
+	}
+}
+
+func TestGetConflict_Diff3Style(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.Git("config", "merge.conflictStyle", "diff3")
+	repo.CreateConflict("file.txt", twoConflicts[0], twoConflicts[1], twoConflicts[2])
+	// A hand edit to the base section shows the base comes from the file
+	repo.WriteFile("file.txt", strings.Replace(repo.ReadFile("file.txt"), "base\n", "edited base\n", 1))
+
+	file, err := repo.Repo.GetConflict(t.Context(), "file.txt")
+	if err != nil {
+		t.Fatalf("GetConflict failed: %v", err)
+	}
+	if hunks := file.Hunks(); len(hunks) != 2 || strings.Join(hunks[0].Base, "") != "edited base" {
+		t.Errorf("expected the base from the working tree file, got %+v", hunks)
+	}
+}
+
+func TestGetConflict_BothAdded(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.Git("checkout", "-b", "theirs")
+	repo.CommitFile("new.txt", "theirs\n", "theirs")
+	repo.Git("checkout", "-")
+	repo.CommitFile("new.txt", "ours\n", "ours")
+	repo.GitAllowFailure("merge", "theirs")
+
+	file, err := repo.Repo.GetConflict(t.Context(), "new.txt")
+	if err != nil {
+		t.Fatalf("GetConflict failed: %v", err)
+	}
+	hunks := file.Hunks()
+	if len(hunks) != 1 || len(hunks[0].Base) != 0 {
+		t.Fatalf("expected one hunk with an empty base, got %+v", hunks)
+	}
+	status, _ := repo.Repo.GetStatus(t.Context())
+	if len(status.Untracked) != 0 {
+		t.Errorf("expected no stray temp files, got %+v", status.Untracked)
+	}
+}
+
+func TestWriteConflict(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", twoConflicts[0], twoConflicts[1], twoConflicts[2])
+
+	file, err := repo.Repo.GetConflict(t.Context(), "file.txt")
+	if err != nil {
+		t.Fatalf("GetConflict failed: %v", err)
+	}
+	hunks := file.Hunks()
+
+	// A partial resolution is written but the file stays unmerged
+	hunks[0].Resolution = ResolveTheirs
+	if err := repo.Repo.WriteConflict(t.Context(), file); err != nil {
+		t.Fatalf("WriteConflict failed: %v", err)
+	}
+	if !strings.Contains(repo.ReadFile("file.txt"), "<<<<<<< HEAD") {
+		t.Error("unresolved hunk should still have markers")
+	}
+	if repo.Git("ls-files", "--unmerged", "--", "file.txt") == "" {
+		t.Error("partially resolved file should remain unmerged")
+	}
+
+	// Coming back to the file shows only the block still unresolved
+	reread, err := repo.Repo.GetConflict(t.Context(), "file.txt")
+	if err != nil {
+		t.Fatalf("GetConflict failed: %v", err)
+	}
+	if got := reread.Hunks(); len(got) != 1 || strings.Join(got[0].Base, "") != "base" || strings.Join(got[0].Ours, "") != "ours2" {
+		t.Fatalf("expected only the second block, with its base, got %+v", got)
+	}
+
+	hunks[1].Resolution = ResolveBoth
+	if err := repo.Repo.WriteConflict(t.Context(), file); err != nil {
+		t.Fatalf("WriteConflict failed: %v", err)
+	}
+	if got := repo.ReadFile("file.txt"); got != "1\ntheirs\n3\n4\n5\n6\n7\nours2\ntheirs2\n9\n" {
+		t.Errorf("unexpected resolved content %q", got)
+	}
+	assertResolved(t, repo, "file.txt")
+}
+
+func TestWriteConflict_KeepsOutsideEdits(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", twoConflicts[0], twoConflicts[1], twoConflicts[2])
+
+	file, err := repo.Repo.GetConflict(t.Context(), "file.txt")
+	if err != nil {
+		t.Fatalf("GetConflict failed: %v", err)
+	}
+	edited := strings.Replace(file.Original, "1\n", "one\n", 1)
+	repo.WriteFile("file.txt", edited)
+
+	file.Hunks()[0].Resolution = ResolveOurs
+	if err := repo.Repo.WriteConflict(t.Context(), file); !errors.Is(err, ErrFileChanged) {
+		t.Fatalf("expected ErrFileChanged, got %v", err)
+	}
+	if got := repo.ReadFile("file.txt"); got != edited {
+		t.Errorf("the edit should be kept, got %q", got)
+	}
+}
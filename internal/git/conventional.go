@@ -0,0 +1,135 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultConventionalTypes are the commit types allowed when a repository
+// doesn't list its own
+var DefaultConventionalTypes = []string{
+	"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert",
+}
+
+// ConventionalRules are the Conventional Commits rules a repository
+// enforces. They are read from git config:
+//
+//	go-on-git.conventionalCommits   enables them (bool)
+//	go-on-git.conventionalTypes     allowed types, DefaultConventionalTypes if unset
+//	go-on-git.conventionalScopes    allowed scopes, any scope if unset
+//	go-on-git.requireScope          whether every header needs a scope (bool)
+//
+// Lists may be given as repeated keys or separated by commas.
+type ConventionalRules struct {
+	Enabled      bool
+	Types        []string
+	Scopes       []string
+	RequireScope bool
+}
+
+// ConventionalHeader is the parsed first line of a Conventional Commits
+// message: type(scope)!: description
+type ConventionalHeader struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// conventionalHeaderPattern matches a header, capturing its type, scope,
+// breaking-change marker and description
+var conventionalHeaderPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()\s][^()]*)\))?(!)?: (\S.*)$`)
+
+// ConventionalRules reads the repository's Conventional Commits rules
+func (r *Repo) ConventionalRules(ctx context.Context) (ConventionalRules, error) {
+	var rules ConventionalRules
+	var err error
+	if rules.Enabled, err = r.configBool(ctx, "go-on-git.conventionalCommits"); err != nil {
+		return rules, err
+	}
+	if rules.Types, err = r.configList(ctx, "go-on-git.conventionalTypes"); err != nil {
+		return rules, err
+	}
+	if len(rules.Types) == 0 {
+		rules.Types = DefaultConventionalTypes
+	}
+	if rules.Scopes, err = r.configList(ctx, "go-on-git.conventionalScopes"); err != nil {
+		return rules, err
+	}
+	if rules.RequireScope, err = r.configBool(ctx, "go-on-git.requireScope"); err != nil {
+		return rules, err
+	}
+	return rules, nil
+}
+
+// ParseConventionalHeader parses a message's first line
+func ParseConventionalHeader(subject string) (ConventionalHeader, error) {
+	match := conventionalHeaderPattern.FindStringSubmatch(subject)
+	if match == nil {
+		return ConventionalHeader{}, fmt.Errorf("header %q is not of the form type(scope): description", subject)
+	}
+	return ConventionalHeader{
+		Type:        match[1],
+		Scope:       match[2],
+		Breaking:    match[3] == "!",
+		Description: match[4],
+	}, nil
+}
+
+// String formats the header as type(scope)!: description
+func (h ConventionalHeader) String() string {
+	var sb strings.Builder
+	sb.WriteString(h.Type)
+	if h.Scope != "" {
+		sb.WriteString("(" + h.Scope + ")")
+	}
+	if h.Breaking {
+		sb.WriteString("!")
+	}
+	sb.WriteString(": " + h.Description)
+	return sb.String()
+}
+
+// Check validates a commit message against the Conventional Commits spec
+// and the rules. Messages git writes itself for autosquash are let
+// through. Its signature makes it usable as a CommitMsgHook.
+func (rules ConventionalRules) Check(_ context.Context, message string) error {
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	for _, prefix := range []string{"fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(lines[0], prefix) {
+			return nil
+		}
+	}
+
+	header, err := ParseConventionalHeader(lines[0])
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(rules.Types, func(t string) bool { return strings.EqualFold(t, header.Type) }) {
+		return fmt.Errorf("type %q is not one of %s", header.Type, strings.Join(rules.Types, ", "))
+	}
+	if header.Scope == "" && rules.RequireScope {
+		return fmt.Errorf("a scope is required: %s(scope): ...", header.Type)
+	}
+	if header.Scope != "" && len(rules.Scopes) > 0 && !slices.Contains(rules.Scopes, header.Scope) {
+		return fmt.Errorf("scope %q is not one of %s", header.Scope, strings.Join(rules.Scopes, ", "))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		return fmt.Errorf("the body must start after a blank line")
+	}
+	for _, line := range lines[1:] {
+		token, _, found := strings.Cut(line, ":")
+		if found && !isBreakingToken(token) && isBreakingToken(strings.ToUpper(token)) {
+			return fmt.Errorf("write the %q footer as BREAKING CHANGE", token)
+		}
+	}
+	return nil
+}
+
+// isBreakingToken reports whether a footer token marks a breaking change
+func isBreakingToken(token string) bool {
+	return token == "BREAKING CHANGE" || token == "BREAKING-CHANGE"
+}
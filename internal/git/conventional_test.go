@@ -0,0 +1,115 @@
+package git
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConventionalRules(t *testing.T) {
+	repo := NewTestRepo(t)
+
+	rules, err := repo.Repo.ConventionalRules(t.Context())
+	if err != nil {
+		t.Fatalf("ConventionalRules failed: %v", err)
+	}
+	if rules.Enabled || rules.RequireScope || len(rules.Scopes) != 0 {
+		t.Errorf("rules should be off by default, got %+v", rules)
+	}
+	if !slices.Equal(rules.Types, DefaultConventionalTypes) {
+		t.Errorf("types = %v, want the defaults", rules.Types)
+	}
+
+	repo.Git("config", "go-on-git.conventionalCommits", "yes")
+	repo.Git("config", "go-on-git.conventionalTypes", "feat, fix")
+	repo.Git("config", "--add", "go-on-git.conventionalTypes", "chore")
+	repo.Git("config", "go-on-git.conventionalScopes", "ui,git")
+	repo.Git("config", "go-on-git.requireScope", "true")
+	rules, err = repo.Repo.ConventionalRules(t.Context())
+	if err != nil {
+		t.Fatalf("ConventionalRules failed: %v", err)
+	}
+	if !rules.Enabled || !rules.RequireScope {
+		t.Errorf("expected the rules enabled with scopes required, got %+v", rules)
+	}
+	if !slices.Equal(rules.Types, []string{"feat", "fix", "chore"}) || !slices.Equal(rules.Scopes, []string{"ui", "git"}) {
+		t.Errorf("types = %v, scopes = %v", rules.Types, rules.Scopes)
+	}
+
+	repo.Git("config", "go-on-git.requireScope", "maybe")
+	if _, err := repo.Repo.ConventionalRules(t.Context()); err == nil {
+		t.Error("expected an error for an invalid boolean")
+	}
+}
+
+func TestParseConventionalHeader(t *testing.T) {
+	header, err := ParseConventionalHeader("feat(ui)!: add a picker")
+	if err != nil {
+		t.Fatalf("ParseConventionalHeader failed: %v", err)
+	}
+	want := ConventionalHeader{Type: "feat", Scope: "ui", Breaking: true, Description: "add a picker"}
+	if header != want {
+		t.Errorf("header = %+v, want %+v", header, want)
+	}
+	if header.String() != "feat(ui)!: add a picker" {
+		t.Errorf("String() = %q", header.String())
+	}
+
+	for _, subject := range []string{"add a picker", "feat:add", "feat(): add", "feat(ui) : add", "feat: "} {
+		if _, err := ParseConventionalHeader(subject); err == nil {
+			t.Errorf("expected %q to be rejected", subject)
+		}
+	}
+}
+
+func TestConventionalRulesCheck(t *testing.T) {
+	rules := ConventionalRules{Enabled: true, Types: DefaultConventionalTypes}
+	scoped := ConventionalRules{Enabled: true, Types: []string{"feat"}, Scopes: []string{"ui"}, RequireScope: true}
+
+	tests := []struct {
+		name    string
+		rules   ConventionalRules
+		message string
+		wantErr string
+	}{
+		{"valid", rules, "fix: handle empty input\n\nBody.\n\nBREAKING CHANGE: input is required", ""},
+		{"type case", rules, "FIX: shout", ""},
+		{"fixup", rules, "fixup! anything goes", ""},
+		{"no header", rules, "Handle empty input", "is not of the form"},
+		{"unknown type", rules, "feature: add", `type "feature" is not one of`},
+		{"no blank line", rules, "fix: a\nbody", "blank line"},
+		{"breaking case", rules, "fix: a\n\nBreaking change: yes", `write the "Breaking change" footer as BREAKING CHANGE`},
+		{"scope required", scoped, "feat: add", "a scope is required"},
+		{"unknown scope", scoped, "feat(git): add", `scope "git" is not one of ui`},
+		{"known scope", scoped, "feat(ui): add", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Check(t.Context(), tt.message)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConventionalRulesAsHook(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.Repo.AddCommitMsgHook(ConventionalRules{Enabled: true, Types: DefaultConventionalTypes}.Check)
+
+	repo.WriteFile("file.txt", "content\n")
+	repo.Git("add", "file.txt")
+	if err := repo.Repo.Commit(t.Context(), "Add file"); err == nil {
+		t.Fatal("expected a non-conventional message to be rejected")
+	}
+	if err := repo.Repo.Commit(t.Context(), "feat: add file"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"context"
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -38,8 +40,8 @@ type Hunk struct {
 
 // FileDiff represents the diff for a single file
 type FileDiff struct {
-	Path        string   // Path relative to repo root
-	DisplayPath string   // Path relative to cwd (for display)
+	Path        string // Path relative to repo root
+	DisplayPath string // Path relative to cwd (for display)
 	Hunks       []Hunk
 	Header      []string // File header lines (diff --git, index, ---, +++)
 }
@@ -50,32 +52,32 @@ type DiffResult struct {
 }
 
 // GetDiff returns the unstaged diff
-func GetDiff() (*DiffResult, error) {
-	output, err := Run("diff")
+func (r *Repo) GetDiff(ctx context.Context) (*DiffResult, error) {
+	output, err := r.Run(ctx, "diff")
 	if err != nil {
 		return nil, err
 	}
-	return parseDiff(output), nil
+	return r.parseDiff(output), nil
 }
 
 // GetStagedDiff returns the staged diff
-func GetStagedDiff() (*DiffResult, error) {
-	output, err := Run("diff", "--cached")
+func (r *Repo) GetStagedDiff(ctx context.Context) (*DiffResult, error) {
+	output, err := r.Run(ctx, "diff", "--cached")
 	if err != nil {
 		return nil, err
 	}
-	return parseDiff(output), nil
+	return r.parseDiff(output), nil
 }
 
 var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(.*)$`)
 
-func parseDiff(output string) *DiffResult {
+func (r *Repo) parseDiff(output string) *DiffResult {
 	result := &DiffResult{}
 	if output == "" {
 		return result
 	}
 
-	lines := strings.Split(output, "\n")
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
 	var currentFile *FileDiff
 	var currentHunk *Hunk
 	fileIndex := -1
@@ -108,7 +110,7 @@ func parseDiff(output string) *DiffResult {
 					path = path[2:]
 				}
 				currentFile.Path = path
-				currentFile.DisplayPath = ToDisplayPath(path)
+				currentFile.DisplayPath = r.ToDisplayPath(path)
 			}
 			continue
 		}
@@ -232,12 +234,12 @@ type CombinedDiffResult struct {
 }
 
 // GetCombinedDiff returns both staged and unstaged diffs
-func GetCombinedDiff() (*CombinedDiffResult, error) {
-	staged, err := GetStagedDiff()
+func (r *Repo) GetCombinedDiff(ctx context.Context) (*CombinedDiffResult, error) {
+	staged, err := r.GetStagedDiff(ctx)
 	if err != nil {
 		return nil, err
 	}
-	unstaged, err := GetDiff()
+	unstaged, err := r.GetDiff(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -320,23 +322,225 @@ func (h *Hunk) GeneratePatch(fileDiff *FileDiff) string {
 	return sb.String()
 }
 
+// Split breaks the hunk into smaller hunks at each run of context lines
+// between changes, like the s command of git add -p. Context lines between
+// two changes belong to both neighbouring pieces, so each piece applies on
+// its own. Returns the hunk unchanged if it has a single block of changes.
+func (h Hunk) Split() []Hunk {
+	lines := h.Lines
+
+	// Find the blocks of changed lines; "\ No newline" markers belong to the
+	// line before them
+	type block struct{ start, end int }
+	var blocks []block
+	inChange := false
+	for i, line := range lines {
+		if strings.HasPrefix(line.Content, "\\") {
+			if inChange {
+				blocks[len(blocks)-1].end = i + 1
+			}
+			continue
+		}
+		inChange = line.Type == LineAdded || line.Type == LineRemoved
+		if !inChange {
+			continue
+		}
+		if len(blocks) > 0 && blocks[len(blocks)-1].end == i {
+			blocks[len(blocks)-1].end = i + 1
+		} else {
+			blocks = append(blocks, block{i, i + 1})
+		}
+	}
+	if len(blocks) < 2 {
+		return []Hunk{h}
+	}
+
+	// Line numbers in the old and new file at the start of each line
+	oldAt := make([]int, len(lines)+1)
+	newAt := make([]int, len(lines)+1)
+	oldLine, newLine := h.StartOld, h.StartNew
+	for i, line := range lines {
+		oldAt[i], newAt[i] = oldLine, newLine
+		switch {
+		case strings.HasPrefix(line.Content, "\\"):
+		case line.Type == LineAdded:
+			newLine++
+		case line.Type == LineRemoved:
+			oldLine++
+		default:
+			oldLine++
+			newLine++
+		}
+	}
+
+	suffix := ""
+	if matches := hunkHeaderRegex.FindStringSubmatch(h.Header); len(matches) == 6 {
+		suffix = matches[5]
+	}
+
+	pieces := make([]Hunk, 0, len(blocks))
+	for i := range blocks {
+		start, end := 0, len(lines)
+		if i > 0 {
+			start = blocks[i-1].end
+		}
+		if i < len(blocks)-1 {
+			end = blocks[i+1].start
+		}
+
+		piece := h
+		piece.Lines = append([]DiffLine(nil), lines[start:end]...)
+		piece.StartOld, piece.StartNew = oldAt[start], newAt[start]
+		piece.CountOld, piece.CountNew = 0, 0
+		for _, line := range piece.Lines {
+			switch {
+			case strings.HasPrefix(line.Content, "\\"):
+			case line.Type == LineAdded:
+				piece.CountNew++
+			case line.Type == LineRemoved:
+				piece.CountOld++
+			default:
+				piece.CountOld++
+				piece.CountNew++
+			}
+		}
+		piece.Header = fmt.Sprintf("@@ -%d,%d +%d,%d @@%s", piece.StartOld, piece.CountOld, piece.StartNew, piece.CountNew, suffix)
+		pieces = append(pieces, piece)
+	}
+	return pieces
+}
+
+// GeneratePartialPatch generates a patch that applies only the selected
+// lines of the hunk (indices into h.Lines), recomputing the @@ counts.
+//
+// A forward patch is applied as-is (staging), so unselected additions are
+// dropped and unselected removals become context. A reverse patch is applied
+// with --reverse (unstaging, discarding), where the new side is what exists on
+// disk: unselected additions become context and unselected removals are dropped.
+//
+// Returns "" if no added or removed line is selected.
+func (h *Hunk) GeneratePartialPatch(fileDiff *FileDiff, selected map[int]bool, reverse bool) string {
+	var lines []string
+	countOld, countNew := 0, 0
+	changed := false
+	kept := false // whether the previous line made it into the patch
+
+	for i, line := range h.Lines {
+		content := line.Content
+		if strings.HasPrefix(content, "\\") {
+			// "\ No newline at end of file" belongs to the line before it
+			if kept {
+				lines = append(lines, content)
+			}
+			continue
+		}
+
+		kept = true
+		switch {
+		case line.Type == LineContext:
+			countOld++
+			countNew++
+		case selected[i]:
+			changed = true
+			if line.Type == LineAdded {
+				countNew++
+			} else {
+				countOld++
+			}
+		case (line.Type == LineAdded) == reverse:
+			// Present on the side the patch is applied to: keep as context
+			content = " " + content[1:]
+			countOld++
+			countNew++
+		default:
+			kept = false
+			continue
+		}
+		lines = append(lines, content)
+	}
+
+	if !changed {
+		return ""
+	}
+
+	suffix := ""
+	if matches := hunkHeaderRegex.FindStringSubmatch(h.Header); len(matches) == 6 {
+		suffix = matches[5]
+	}
+
+	var sb strings.Builder
+	for _, headerLine := range fileDiff.Header {
+		sb.WriteString(headerLine)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@%s\n", h.StartOld, countOld, h.StartNew, countNew, suffix))
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// editHunkGuide is appended to a patch opened for manual editing
+const editHunkGuide = `# ---
+# To remove '-' lines, make them ' ' lines (context).
+# To remove '+' lines, delete them.
+# Lines starting with # will be removed.
+# If the patch applies cleanly, the edited hunk will be staged.
+# If it does not apply cleanly, you will be able to edit it again.
+# If all changed lines are removed, the edit is aborted and the hunk is left unchanged.
+`
+
+// GenerateEditablePatch returns the hunk's patch followed by instructions
+// for editing it by hand, like the e command of git add -p
+func (h *Hunk) GenerateEditablePatch(fileDiff *FileDiff) string {
+	return h.GeneratePatch(fileDiff) + editHunkGuide
+}
+
+// ParseEditedPatch strips comment lines from a patch edited by hand.
+// Returns "" if no added or removed lines are left, meaning the edit was aborted.
+func ParseEditedPatch(content string) string {
+	var sb strings.Builder
+	changed := false
+	inHunk := false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "@@") {
+			inHunk = true
+		} else if inHunk && (strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-")) {
+			changed = true
+		}
+		sb.WriteString(line)
+	}
+	if !changed {
+		return ""
+	}
+	patch := sb.String()
+	if !strings.HasSuffix(patch, "\n") {
+		patch += "\n"
+	}
+	return patch
+}
+
 // GetUntrackedFileDiff returns a diff for an untracked file (showing all content as additions)
-func GetUntrackedFileDiff(path string) *FileDiff {
+func (r *Repo) GetUntrackedFileDiff(ctx context.Context, path string) *FileDiff {
 	// Use git diff --no-index to compare /dev/null with the file
 	// This command exits with code 1 when there are differences, so we ignore the error
-	output, _ := RunAllowFailure("diff", "--no-index", "--", "/dev/null", path)
+	output, _ := r.RunAllowFailure(ctx, "diff", "--no-index", "--", "/dev/null", path)
 	if output == "" {
 		return nil
 	}
 
-	result := parseDiff(output)
+	result := r.parseDiff(output)
 	if len(result.Files) > 0 {
 		// Fix the file path (--no-index uses full paths)
 		result.Files[0].Path = path
-		result.Files[0].DisplayPath = ToDisplayPath(path)
+		result.Files[0].DisplayPath = r.ToDisplayPath(path)
 		for i := range result.Files[0].Hunks {
 			result.Files[0].Hunks[i].FilePath = path
-			result.Files[0].Hunks[i].DisplayFilePath = ToDisplayPath(path)
+			result.Files[0].Hunks[i].DisplayFilePath = r.ToDisplayPath(path)
 		}
 		return &result.Files[0]
 	}
@@ -11,7 +11,7 @@ func TestGetDiff_NoDiff(t *testing.T) {
 
 	repo.InitialCommit()
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -32,7 +32,7 @@ func TestGetDiff_ModifiedFile(t *testing.T) {
 	repo.CommitFile("test.txt", "line1\nline2\nline3\n", "initial")
 	repo.WriteFile("test.txt", "line1\nmodified\nline3\n")
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -93,7 +93,7 @@ func TestGetDiff_MultipleHunks(t *testing.T) {
 	modified := "changed1\nline2\nline3\nline4\nline5\nline6\nline7\nline8\nline9\nchanged10\n"
 	repo.WriteFile("test.txt", modified)
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -118,7 +118,7 @@ func TestGetDiff_MultipleFiles(t *testing.T) {
 	repo.WriteFile("file1.txt", "modified1")
 	repo.WriteFile("file2.txt", "modified2")
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -136,7 +136,7 @@ func TestGetStagedDiff(t *testing.T) {
 	repo.WriteFile("test.txt", "modified\n")
 	repo.Git("add", "test.txt")
 
-	diff, err := GetStagedDiff()
+	diff, err := repo.Repo.GetStagedDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetStagedDiff failed: %v", err)
 	}
@@ -150,7 +150,7 @@ func TestGetStagedDiff(t *testing.T) {
 	}
 
 	// Verify unstaged diff is empty
-	unstagedDiff, err := GetDiff()
+	unstagedDiff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -173,7 +173,7 @@ func TestGetCombinedDiff(t *testing.T) {
 	// Make additional unstaged change
 	repo.WriteFile("test.txt", "staged change\nunstaged addition\n")
 
-	combined, err := GetCombinedDiff()
+	combined, err := repo.Repo.GetCombinedDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetCombinedDiff failed: %v", err)
 	}
@@ -205,7 +205,7 @@ func TestGetCombinedDiff_AllHunksCombined(t *testing.T) {
 	// Make unstaged changes to file2
 	repo.WriteFile("file2.txt", "modified2\n")
 
-	combined, err := GetCombinedDiff()
+	combined, err := repo.Repo.GetCombinedDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetCombinedDiff failed: %v", err)
 	}
@@ -244,7 +244,7 @@ func TestDiffResult_GetAllHunks(t *testing.T) {
 	repo.WriteFile("file1.txt", "modified1\n")
 	repo.WriteFile("file2.txt", "modified2\n")
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -262,7 +262,7 @@ func TestHunk_GeneratePatch(t *testing.T) {
 	repo.CommitFile("test.txt", "line1\nline2\nline3\n", "initial")
 	repo.WriteFile("test.txt", "line1\nmodified\nline3\n")
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -295,7 +295,7 @@ func TestGetUntrackedFileDiff(t *testing.T) {
 	repo.InitialCommit()
 	repo.WriteFile("new-file.txt", "line1\nline2\nline3\n")
 
-	diff := GetUntrackedFileDiff("new-file.txt")
+	diff := repo.Repo.GetUntrackedFileDiff(t.Context(), "new-file.txt")
 	if diff == nil {
 		t.Fatal("expected non-nil diff for untracked file")
 	}
@@ -325,7 +325,7 @@ func TestParseDiff_HunkLineNumbers(t *testing.T) {
 	repo.CommitFile("test.txt", "line1\nline2\nline3\n", "initial")
 	repo.WriteFile("test.txt", "line1\nmodified\nline3\n")
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -352,7 +352,7 @@ func TestParseDiff_FileHeaders(t *testing.T) {
 	repo.CommitFile("test.txt", "original\n", "initial")
 	repo.WriteFile("test.txt", "modified\n")
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -401,7 +401,7 @@ func TestDiffResult_TotalHunks(t *testing.T) {
 	repo.WriteFile("file1.txt", "modified1\n")
 	repo.WriteFile("file2.txt", "modified2\n")
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -420,7 +420,7 @@ func TestGetDiff_DeletedFile(t *testing.T) {
 	repo.DeleteFile("test.txt")
 
 	// File deleted but not staged
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -439,7 +439,7 @@ func TestGetDiff_NewFileStaged(t *testing.T) {
 	repo.WriteFile("new.txt", "new content\n")
 	repo.Git("add", "new.txt")
 
-	diff, err := GetStagedDiff()
+	diff, err := repo.Repo.GetStagedDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetStagedDiff failed: %v", err)
 	}
@@ -475,7 +475,7 @@ func TestCombinedDiffResult_GetFileDiff(t *testing.T) {
 	repo.Git("add", "test.txt")
 	repo.WriteFile("test.txt", "staged\nunstaged\n")
 
-	combined, err := GetCombinedDiff()
+	combined, err := repo.Repo.GetCombinedDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetCombinedDiff failed: %v", err)
 	}
@@ -551,7 +551,7 @@ func TestParseDiff_ContextLines(t *testing.T) {
 	modified := "line1\nline2\nline3\nline4\nMODIFIED\nline6\nline7\nline8\nline9\nline10\n"
 	repo.WriteFile("test.txt", modified)
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -596,7 +596,7 @@ func TestHunk_Metadata(t *testing.T) {
 	repo.CommitFile("test.txt", "line1\nline2\nline3\n", "initial")
 	repo.WriteFile("test.txt", "line1\nmodified\nline3\n")
 
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -623,3 +623,330 @@ func TestHunk_Metadata(t *testing.T) {
 		t.Errorf("expected hunk header to start with '@@', got %q", hunk.Header)
 	}
 }
+
+// lineIndices returns the indices of hunk lines whose content matches one of want
+func lineIndices(h Hunk, want ...string) map[int]bool {
+	selected := make(map[int]bool)
+	for i, line := range h.Lines {
+		for _, w := range want {
+			if line.Content == w {
+				selected[i] = true
+			}
+		}
+	}
+	return selected
+}
+
+func TestHunk_GeneratePartialPatch(t *testing.T) {
+	fileDiff := &FileDiff{Header: []string{"diff --git a/f.txt b/f.txt", "--- a/f.txt", "+++ b/f.txt"}}
+	hunk := Hunk{
+		Header:   "@@ -1,4 +1,4 @@ func main()",
+		StartOld: 1, CountOld: 4, StartNew: 1, CountNew: 4,
+		Lines: []DiffLine{
+			{Type: LineContext, Content: " a"},
+			{Type: LineRemoved, Content: "-b"},
+			{Type: LineRemoved, Content: "-c"},
+			{Type: LineAdded, Content: "+B"},
+			{Type: LineAdded, Content: "+C"},
+			{Type: LineContext, Content: " d"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		selected []string
+		reverse  bool
+		want     string
+	}{
+		{
+			name:     "forward keeps unselected removals as context",
+			selected: []string{"-b", "+B"},
+			want:     "@@ -1,4 +1,4 @@ func main()\n a\n-b\n c\n+B\n d\n",
+		},
+		{
+			name:     "forward drops unselected additions",
+			selected: []string{"-b", "-c"},
+			want:     "@@ -1,4 +1,2 @@ func main()\n a\n-b\n-c\n d\n",
+		},
+		{
+			name:     "reverse keeps unselected additions as context",
+			selected: []string{"-b", "+B"},
+			reverse:  true,
+			want:     "@@ -1,4 +1,4 @@ func main()\n a\n-b\n+B\n C\n d\n",
+		},
+		{
+			name:     "reverse drops unselected removals",
+			selected: []string{"+C"},
+			reverse:  true,
+			want:     "@@ -1,3 +1,4 @@ func main()\n a\n B\n+C\n d\n",
+		},
+		{
+			name:     "nothing selected",
+			selected: []string{" a"},
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := hunk.GeneratePartialPatch(fileDiff, lineIndices(hunk, tt.selected...), tt.reverse)
+			if tt.want == "" {
+				if patch != "" {
+					t.Errorf("expected empty patch, got %q", patch)
+				}
+				return
+			}
+			want := "diff --git a/f.txt b/f.txt\n--- a/f.txt\n+++ b/f.txt\n" + tt.want
+			if patch != want {
+				t.Errorf("patch mismatch\ngot:\n%s\nwant:\n%s", patch, want)
+			}
+		})
+	}
+}
+
+func TestStagePartialHunk(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("test.txt", "one\ntwo\nthree\nfour\nfive\n", "initial")
+	repo.WriteFile("test.txt", "one\nTWO\nthree\nFOUR\nfive\n")
+
+	diff, err := repo.Repo.GetDiff(t.Context())
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	if len(diff.Files) != 1 || len(diff.Files[0].Hunks) != 1 {
+		t.Fatalf("expected a single hunk, got %+v", diff.Files)
+	}
+	hunk := diff.Files[0].Hunks[0]
+
+	patch := hunk.GeneratePartialPatch(&diff.Files[0], lineIndices(hunk, "-two", "+TWO"), false)
+	if err := repo.Repo.StageHunk(t.Context(), patch); err != nil {
+		t.Fatalf("StageHunk failed: %v\n%s", err, patch)
+	}
+
+	if got := repo.Git("show", ":test.txt"); got != "one\nTWO\nthree\nfour\nfive\n" {
+		t.Errorf("index content = %q", got)
+	}
+	if got := repo.ReadFile("test.txt"); got != "one\nTWO\nthree\nFOUR\nfive\n" {
+		t.Errorf("working tree should be untouched, got %q", got)
+	}
+}
+
+func TestUnstagePartialHunk(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("test.txt", "one\ntwo\nthree\nfour\nfive\n", "initial")
+	repo.WriteFile("test.txt", "one\nTWO\nthree\nFOUR\nfive\n")
+	repo.Git("add", "test.txt")
+
+	diff, err := repo.Repo.GetStagedDiff(t.Context())
+	if err != nil {
+		t.Fatalf("GetStagedDiff failed: %v", err)
+	}
+	hunk := diff.Files[0].Hunks[0]
+
+	patch := hunk.GeneratePartialPatch(&diff.Files[0], lineIndices(hunk, "-four", "+FOUR"), true)
+	if err := repo.Repo.UnstageHunk(t.Context(), patch); err != nil {
+		t.Fatalf("UnstageHunk failed: %v\n%s", err, patch)
+	}
+
+	if got := repo.Git("show", ":test.txt"); got != "one\nTWO\nthree\nfour\nfive\n" {
+		t.Errorf("index content = %q", got)
+	}
+}
+
+func TestDiscardPartialHunk(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("test.txt", "one\ntwo\nthree\n", "initial")
+	repo.WriteFile("test.txt", "one\nTWO\nthree\nadded")
+
+	diff, err := repo.Repo.GetDiff(t.Context())
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	hunk := diff.Files[0].Hunks[0]
+
+	// Discard the first change only; the added last line has no trailing newline
+	patch := hunk.GeneratePartialPatch(&diff.Files[0], lineIndices(hunk, "-two", "+TWO"), true)
+	if err := repo.Repo.DiscardHunk(t.Context(), patch); err != nil {
+		t.Fatalf("DiscardHunk failed: %v\n%s", err, patch)
+	}
+
+	if got := repo.ReadFile("test.txt"); got != "one\ntwo\nthree\nadded" {
+		t.Errorf("working tree content = %q", got)
+	}
+}
+
+func TestHunk_Split(t *testing.T) {
+	hunk := Hunk{
+		Header:   "@@ -10,7 +10,7 @@ func main()",
+		StartOld: 10, CountOld: 7, StartNew: 10, CountNew: 7,
+		Lines: []DiffLine{
+			{Type: LineContext, Content: " a"},
+			{Type: LineRemoved, Content: "-b"},
+			{Type: LineAdded, Content: "+B"},
+			{Type: LineAdded, Content: "+B2"},
+			{Type: LineContext, Content: " c"},
+			{Type: LineContext, Content: " d"},
+			{Type: LineRemoved, Content: "-e"},
+			{Type: LineContext, Content: " f"},
+		},
+	}
+
+	pieces := hunk.Split()
+	if len(pieces) != 2 {
+		t.Fatalf("expected 2 pieces, got %d", len(pieces))
+	}
+
+	first, second := pieces[0], pieces[1]
+	if first.Header != "@@ -10,4 +10,5 @@ func main()" {
+		t.Errorf("first header = %q", first.Header)
+	}
+	if first.StartOld != 10 || first.CountOld != 4 || first.StartNew != 10 || first.CountNew != 5 {
+		t.Errorf("first counts = -%d,%d +%d,%d", first.StartOld, first.CountOld, first.StartNew, first.CountNew)
+	}
+	if len(first.Lines) != 6 || first.Lines[5].Content != " d" {
+		t.Errorf("first piece should end with the shared context, got %+v", first.Lines)
+	}
+
+	if second.Header != "@@ -12,4 +13,3 @@ func main()" {
+		t.Errorf("second header = %q", second.Header)
+	}
+	if len(second.Lines) != 4 || second.Lines[0].Content != " c" {
+		t.Errorf("second piece should start with the shared context, got %+v", second.Lines)
+	}
+}
+
+func TestHunk_SplitSingleBlock(t *testing.T) {
+	hunk := Hunk{
+		Header: "@@ -1,3 +1,3 @@",
+		Lines: []DiffLine{
+			{Type: LineContext, Content: " a"},
+			{Type: LineRemoved, Content: "-b"},
+			{Type: LineAdded, Content: "+B"},
+			{Type: LineContext, Content: " c"},
+		},
+	}
+	pieces := hunk.Split()
+	if len(pieces) != 1 || pieces[0].Header != hunk.Header {
+		t.Errorf("expected hunk unchanged, got %+v", pieces)
+	}
+}
+
+func TestStageSplitHunk(t *testing.T) {
+	repo := NewTestRepo(t)
+	original := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	modified := "1\n2\nthree\n4\n5\n6\nseven\n8\n9\n10\n"
+	repo.CommitFile("test.txt", original, "initial")
+	repo.WriteFile("test.txt", modified)
+
+	diff, err := repo.Repo.GetDiff(t.Context())
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	if len(diff.Files[0].Hunks) != 1 {
+		t.Fatalf("expected a single hunk, got %d", len(diff.Files[0].Hunks))
+	}
+	pieces := diff.Files[0].Hunks[0].Split()
+	if len(pieces) != 2 {
+		t.Fatalf("expected 2 pieces, got %d", len(pieces))
+	}
+
+	// Stage the second piece first, then the first: both must apply on their own
+	if err := repo.Repo.StageHunk(t.Context(), pieces[1].GeneratePatch(&diff.Files[0])); err != nil {
+		t.Fatalf("staging second piece failed: %v", err)
+	}
+	if got := repo.Git("show", ":test.txt"); got != "1\n2\n3\n4\n5\n6\nseven\n8\n9\n10\n" {
+		t.Errorf("index after second piece = %q", got)
+	}
+	if err := repo.Repo.StageHunk(t.Context(), pieces[0].GeneratePatch(&diff.Files[0])); err != nil {
+		t.Fatalf("staging first piece failed: %v", err)
+	}
+	if got := repo.Git("show", ":test.txt"); got != modified {
+		t.Errorf("index after both pieces = %q", got)
+	}
+}
+
+func TestParseEditedPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "strips comments",
+			content: "diff --git a/f b/f\n@@ -1 +1,2 @@\n a\n+b\n# ---\n# guide\n",
+			want:    "diff --git a/f b/f\n@@ -1 +1,2 @@\n a\n+b\n",
+		},
+		{
+			name:    "adds missing final newline",
+			content: "@@ -1 +1,2 @@\n a\n+b",
+			want:    "@@ -1 +1,2 @@\n a\n+b\n",
+		},
+		{
+			name:    "header lines are not changes",
+			content: "--- a/f\n+++ b/f\n@@ -1 +1 @@\n a\n# guide\n",
+			want:    "",
+		},
+		{
+			name:    "everything removed",
+			content: "# guide\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseEditedPatch(tt.content); got != tt.want {
+				t.Errorf("ParseEditedPatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStageEditedHunk(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("test.txt", "one\ntwo\n", "initial")
+	repo.WriteFile("test.txt", "one\nadded\nalso added\ntwo\n")
+
+	diff, err := repo.Repo.GetDiff(t.Context())
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	hunk := diff.Files[0].Hunks[0]
+	editable := hunk.GenerateEditablePatch(&diff.Files[0])
+	if !strings.Contains(editable, "# To remove '+' lines, delete them.") {
+		t.Errorf("expected editing guide, got %q", editable)
+	}
+
+	// Drop one added line without fixing the @@ counts
+	edited := ParseEditedPatch(strings.Replace(editable, "+also added\n", "", 1))
+	if err := repo.Repo.CheckEditedHunk(t.Context(), edited); err != nil {
+		t.Fatalf("CheckEditedHunk failed: %v\n%s", err, edited)
+	}
+	if err := repo.Repo.StageEditedHunk(t.Context(), edited); err != nil {
+		t.Fatalf("StageEditedHunk failed: %v", err)
+	}
+	if got := repo.Git("show", ":test.txt"); got != "one\nadded\ntwo\n" {
+		t.Errorf("index content = %q", got)
+	}
+}
+
+func TestCheckEditedHunkInvalid(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("test.txt", "one\ntwo\n", "initial")
+	repo.WriteFile("test.txt", "one\nadded\ntwo\n")
+
+	diff, err := repo.Repo.GetDiff(t.Context())
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	hunk := diff.Files[0].Hunks[0]
+
+	// Context that doesn't match the index
+	edited := ParseEditedPatch(strings.Replace(hunk.GeneratePatch(&diff.Files[0]), " one\n", " uno\n", 1))
+	if err := repo.Repo.CheckEditedHunk(t.Context(), edited); err == nil {
+		t.Fatal("expected CheckEditedHunk to fail")
+	}
+	if got := repo.Git("diff", "--cached"); got != "" {
+		t.Errorf("index should be unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors for git failures the UI can react to. Match them with
+// errors.Is; the *Error returned by Run wraps the one that applies.
+var (
+	ErrIndexLocked     = sentinel("index is locked by another git process")
+	ErrMergeConflict   = sentinel("merge conflict")
+	ErrPushRejected    = sentinel("push rejected: remote contains commits not in your branch")
+	ErrAuthFailed      = sentinel("authentication failed")
+	ErrNotFullyMerged  = sentinel("branch is not fully merged")
+	ErrNothingToCommit = sentinel("nothing to commit")
+	ErrDetachedHead    = sentinel("HEAD is detached")
+	ErrSigningFailed   = sentinel("commit signing failed")
+	ErrNotFastForward  = sentinel("not possible to fast-forward")
+	ErrFileChanged     = sentinel("file changed on disk")
+)
+
+type sentinel string
+
+func (s sentinel) Error() string { return string(s) }
+
+// errorPatterns maps output fragments to the error they indicate, checked in order
+var errorPatterns = []struct {
+	kind      error
+	fragments []string
+}{
+	{ErrIndexLocked, []string{"index.lock': File exists", "index.lock: File exists"}},
+	{ErrAuthFailed, []string{
+		"Authentication failed",
+		"could not read Username",
+		"could not read Password",
+		"Permission denied (publickey",
+		"terminal prompts disabled",
+		"Invalid username or password",
+	}},
+	{ErrPushRejected, []string{"Updates were rejected because"}},
+	{ErrNotFullyMerged, []string{"is not fully merged"}},
+	{ErrMergeConflict, []string{
+		"CONFLICT (",
+		"Automatic merge failed",
+		"you need to resolve your current index first",
+		"needs merge",
+		"could not apply",
+		"you have unmerged files",
+	}},
+	{ErrNothingToCommit, []string{"nothing to commit", "no changes added to commit"}},
+	{ErrNotFastForward, []string{"Not possible to fast-forward"}},
+	{ErrSigningFailed, []string{
+		"failed to sign the data",
+		"Couldn't load public key",
+		"No private key found for",
+		"signing failed",
+		"Load key ",
+	}},
+	{ErrDetachedHead, []string{"You are not currently on a branch", "HEAD does not point to a branch"}},
+}
+
+// Error is returned when a git command fails
+type Error struct {
+	Args   []string
+	Stderr string
+	Kind   error // one of the Err sentinels, or nil if the failure wasn't recognized
+	Err    error // underlying cause: *ExitError or a context error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("git %s: %v: %s", strings.Join(e.Args, " "), e.Err, e.Stderr)
+}
+
+// Message returns git's first complaint, without its "error:" or "fatal:"
+// prefix, or the underlying cause if git printed nothing
+func (e *Error) Message() string {
+	for _, line := range strings.Split(e.Stderr, "\n") {
+		line = strings.TrimSpace(line)
+		for _, prefix := range []string{"error: ", "fatal: "} {
+			line = strings.TrimPrefix(line, prefix)
+		}
+		if line != "" {
+			return line
+		}
+	}
+	return e.Err.Error()
+}
+
+// Unwrap exposes both the classified kind and the underlying cause
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// newError builds an *Error for a failed command, classifying it from its output
+func newError(args []string, result Result, err error) *Error {
+	return &Error{
+		Args:   args,
+		Stderr: result.Stderr,
+		Kind:   classify(args, result.Stdout+result.Stderr),
+		Err:    err,
+	}
+}
+
+// classify returns the sentinel error matching the output of git args, or nil
+func classify(args []string, output string) error {
+	for _, p := range errorPatterns {
+		for _, fragment := range p.fragments {
+			if strings.Contains(output, fragment) {
+				return p.kind
+			}
+		}
+	}
+	// Fetch and tag also print "[rejected]", for refs they won't clobber;
+	// only a push rejection can be pulled or forced past
+	if len(args) > 0 && args[0] == "push" && strings.Contains(output, "[rejected]") {
+		return ErrPushRejected
+	}
+	return nil
+}
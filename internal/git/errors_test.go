@@ -0,0 +1,179 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   error
+	}{
+		{"index locked", "fatal: Unable to create '/repo/.git/index.lock': File exists.", ErrIndexLocked},
+		{"auth", "fatal: Authentication failed for 'https://example.com/repo.git/'", ErrAuthFailed},
+		{"ssh auth", "git@example.com: Permission denied (publickey).", ErrAuthFailed},
+		{"push rejected", "hint: Updates were rejected because the remote contains work that you do not", ErrPushRejected},
+		{"not fully merged", "error: the branch 'feature' is not fully merged.", ErrNotFullyMerged},
+		{"merge conflict", "CONFLICT (content): Merge conflict in file.txt", ErrMergeConflict},
+		{"unmerged files", "error: Committing is not possible because you have unmerged files.", ErrMergeConflict},
+		{"nothing to commit", "nothing to commit, working tree clean", ErrNothingToCommit},
+		{"detached", "fatal: You are not currently on a branch.", ErrDetachedHead},
+		{"not fast-forward", "fatal: Not possible to fast-forward, aborting.", ErrNotFastForward},
+		{"gpg signing", "error: gpg failed to sign the data\nfatal: failed to write commit object", ErrSigningFailed},
+		{"ssh signing", "error: Couldn't load public key /missing.pub: No such file or directory?", ErrSigningFailed},
+		{"unknown", "fatal: something else went wrong", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify([]string{"status"}, tt.output); got != tt.want {
+				t.Errorf("classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		output string
+		want   error
+	}{
+		{"push", []string{"push"}, " ! [rejected]        main -> main (stale info)", ErrPushRejected},
+		{"fetch tag", []string{"fetch"}, " ! [rejected]        v1 -> v1  (would clobber existing tag)", nil},
+		{"tag", []string{"tag", "v1"}, " ! [rejected] v1 -> v1 (already exists)", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.args, tt.output); got != tt.want {
+				t.Errorf("classify(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_Unwrap(t *testing.T) {
+	err := error(&Error{
+		Args:   []string{"push"},
+		Stderr: "rejected",
+		Kind:   ErrPushRejected,
+		Err:    &ExitError{Code: 1},
+	})
+
+	if !errors.Is(err, ErrPushRejected) {
+		t.Error("expected errors.Is to match the kind")
+	}
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 1 {
+		t.Error("expected errors.As to find the ExitError")
+	}
+	if err.Error() != "git push: exit status 1: rejected" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	cancelled := &Error{Args: []string{"push"}, Err: context.Canceled}
+	if !errors.Is(cancelled, context.Canceled) {
+		t.Error("expected errors.Is to match the context error")
+	}
+}
+
+func TestErrors_NotFullyMerged(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateBranch("feature", true)
+	repo.CommitFile("feature.txt", "feature", "feature commit")
+	repo.Git("checkout", "-")
+
+	err := repo.Repo.DeleteBranch(t.Context(), "feature")
+	if !errors.Is(err, ErrNotFullyMerged) {
+		t.Errorf("expected ErrNotFullyMerged, got %v", err)
+	}
+}
+
+func TestErrors_NothingToCommit(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+
+	err := repo.Repo.Commit(t.Context(), "empty")
+	if !errors.Is(err, ErrNothingToCommit) {
+		t.Errorf("expected ErrNothingToCommit, got %v", err)
+	}
+}
+
+func TestErrors_IndexLocked(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.WriteFile("file.txt", "content")
+	if err := os.WriteFile(filepath.Join(repo.Repo.GitDir(), "index.lock"), nil, 0644); err != nil {
+		t.Fatalf("failed to create index.lock: %v", err)
+	}
+
+	err := repo.Repo.StageFile(t.Context(), "file.txt")
+	if !errors.Is(err, ErrIndexLocked) {
+		t.Errorf("expected ErrIndexLocked, got %v", err)
+	}
+}
+
+func TestErrors_PushRejectedAndMergeConflict(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("file.txt", "base\n", "initial")
+	remoteDir := repo.SetupRemote()
+	defer os.RemoveAll(remoteDir)
+	repo.PushToRemote()
+
+	// Push a conflicting commit from a second clone
+	otherDir := t.TempDir()
+	for _, args := range [][]string{
+		{"clone", remoteDir, otherDir},
+		{"-C", otherDir, "config", "user.email", "other@example.com"},
+		{"-C", otherDir, "config", "user.name", "Other"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(otherDir, "file.txt"), []byte("theirs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"-C", otherDir, "commit", "-am", "theirs"},
+		{"-C", otherDir, "push"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	repo.CommitFile("file.txt", "ours\n", "ours")
+
+	if err := repo.Repo.Push(t.Context()); !errors.Is(err, ErrPushRejected) {
+		t.Fatalf("expected ErrPushRejected, got %v", err)
+	}
+	if err := repo.Repo.Pull(t.Context(), PullMerge); !errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("expected ErrMergeConflict, got %v", err)
+	}
+}
+
+func TestErrors_DetachedHead(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	if err := repo.Repo.PushSetUpstream(t.Context(), "origin", ""); !errors.Is(err, ErrDetachedHead) {
+		t.Errorf("expected ErrDetachedHead, got %v", err)
+	}
+}
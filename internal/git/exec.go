@@ -0,0 +1,101 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Command describes a single git invocation
+type Command struct {
+	Dir   string   // working directory
+	Args  []string // arguments after "git"
+	Stdin string   // data written to the command's stdin
+	Env   []string // extra environment variables (KEY=value)
+
+	// Stderr, if set, also receives the command's stderr as it is
+	// written, for progress output; Result.Stderr still holds all of it
+	Stderr io.Writer
+}
+
+// Result holds the outcome of a git invocation
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Executor runs git commands. Every git process started by a Repo goes
+// through its Executor, so it can be replaced with a fake in tests,
+// wrapped for tracing, or redirected into a container.
+//
+// Execute returns an error when the command could not be run at all or
+// was stopped because ctx was done; a command that ran and failed reports
+// a non-zero ExitCode instead.
+type Executor interface {
+	Execute(ctx context.Context, cmd Command) (Result, error)
+}
+
+// ExecutorFunc adapts a function to the Executor interface
+type ExecutorFunc func(ctx context.Context, cmd Command) (Result, error)
+
+// Execute calls f(ctx, cmd)
+func (f ExecutorFunc) Execute(ctx context.Context, cmd Command) (Result, error) {
+	return f(ctx, cmd)
+}
+
+// ProcessExecutor runs git as a local child process
+type ProcessExecutor struct{}
+
+// waitDelay bounds how long a killed git process may keep its output pipes
+// open (e.g. through an ssh or credential-helper grandchild)
+const waitDelay = 2 * time.Second
+
+// Execute runs the command with os/exec, killing it when ctx is done
+func (ProcessExecutor) Execute(ctx context.Context, c Command) (Result, error) {
+	cmd := exec.CommandContext(ctx, "git", c.Args...)
+	cmd.Dir = c.Dir
+	cmd.WaitDelay = waitDelay
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	if c.Stdin != "" {
+		cmd.Stdin = strings.NewReader(c.Stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if c.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, c.Stderr)
+	}
+
+	err := cmd.Run()
+	result := Result{Stdout: stdout.String(), Stderr: stderr.String()}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return result, ctxErr
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+			return result, nil
+		}
+		return result, err
+	}
+	return result, nil
+}
+
+// ExitError reports a git command that exited with a non-zero status
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
@@ -1,243 +1,373 @@
 package git
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
-var (
-	repoRoot     string
-	repoRootOnce sync.Once
-	gitMu        sync.Mutex // serializes all git operations
-)
+// Repo is a handle to a single git repository. It carries its own root,
+// git directory, environment and lock, so several repositories can be
+// driven from one process.
+type Repo struct {
+	root     string        // working tree root
+	gitDir   string        // absolute path to the git directory
+	workDir  string        // directory that display paths are relative to
+	env      []string      // extra environment variables for git commands
+	executor Executor      // runs the git processes
+	timeouts Timeouts      // per-operation time limits
+	lock     chan struct{} // serializes git operations; a channel so waiting honors ctx
+
+	// settings guards the fields below. It is separate from lock, which a
+	// push can hold for minutes, so reading them never waits on git.
+	settings       sync.Mutex
+	commitMsgHooks []CommitMsgHook // run on messages before committing
+	signing        Signing         // whether commits are signed
+}
 
-// getRepoRoot returns the git repository root directory.
-// The result is cached for efficiency.
-func getRepoRoot() string {
-	repoRootOnce.Do(func() {
-		cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-		output, err := cmd.Output()
-		if err != nil {
-			repoRoot = ""
-			return
-		}
-		repoRoot = strings.TrimSpace(string(output))
+// Timeouts bounds how long a single git command may run. Zero means no limit.
+type Timeouts struct {
+	Local   time.Duration // commands that only touch the local repository
+	Network time.Duration // push, fetch, pull and other commands that talk to a remote
+}
+
+// DefaultTimeouts are applied to repositories returned by Open
+var DefaultTimeouts = Timeouts{
+	Local:   time.Minute,
+	Network: 5 * time.Minute,
+}
+
+// networkCommands are the git subcommands subject to the network timeout
+var networkCommands = map[string]bool{
+	"push":      true,
+	"fetch":     true,
+	"pull":      true,
+	"clone":     true,
+	"ls-remote": true,
+}
+
+// Open returns a Repo for the git repository containing dir.
+// Display paths are computed relative to dir.
+func Open(ctx context.Context, dir string) (*Repo, error) {
+	return OpenWithExecutor(ctx, dir, ProcessExecutor{})
+}
+
+// OpenWithExecutor is like Open but runs every git command, including the
+// initial repository lookup, through the given executor.
+func OpenWithExecutor(ctx context.Context, dir string, executor Executor) (*Repo, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+
+	result, err := executor.Execute(ctx, Command{
+		Dir:  absDir,
+		Args: []string{"rev-parse", "--show-toplevel", "--absolute-git-dir"},
 	})
-	return repoRoot
+	if err != nil || result.ExitCode != 0 {
+		return nil, fmt.Errorf("not a git repository: %s", absDir)
+	}
+
+	lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("not a git repository: %s", absDir)
+	}
+
+	return &Repo{
+		root:     lines[0],
+		gitDir:   lines[1],
+		workDir:  absDir,
+		executor: executor,
+		timeouts: DefaultTimeouts,
+		lock:     make(chan struct{}, 1),
+	}, nil
+}
+
+// Root returns the working tree root directory
+func (r *Repo) Root() string {
+	return r.root
+}
+
+// GitDir returns the absolute path to the git directory
+func (r *Repo) GitDir() string {
+	return r.gitDir
 }
 
-// ResetRepoRoot clears the cached repository root.
-// This is primarily for testing purposes.
-func ResetRepoRoot() {
-	repoRootOnce = sync.Once{}
-	repoRoot = ""
+// SetEnv sets extra environment variables (KEY=value) passed to every git command
+func (r *Repo) SetEnv(env []string) {
+	r.lock <- struct{}{}
+	defer func() { <-r.lock }()
+	r.env = append([]string(nil), env...)
 }
 
 // IsLocked returns true if a git operation is in progress (index.lock exists)
-func IsLocked() bool {
-	root := getRepoRoot()
-	if root == "" {
-		return false
-	}
-	_, err := os.Stat(filepath.Join(root, ".git", "index.lock"))
+func (r *Repo) IsLocked() bool {
+	_, err := os.Stat(filepath.Join(r.gitDir, "index.lock"))
 	return err == nil
 }
 
 // ToDisplayPath converts a repo-root-relative path to a path relative
-// to the current working directory (for display purposes).
-func ToDisplayPath(repoRelativePath string) string {
-	root := getRepoRoot()
-	if root == "" {
-		return repoRelativePath
-	}
+// to the directory the repository was opened from (for display purposes).
+func (r *Repo) ToDisplayPath(repoRelativePath string) string {
+	// Convert repo-relative path to absolute
+	absPath := filepath.Join(r.root, repoRelativePath)
 
-	cwd, err := os.Getwd()
+	// Get path relative to the working directory
+	relPath, err := filepath.Rel(r.workDir, absPath)
 	if err != nil {
 		return repoRelativePath
 	}
 
-	// Convert repo-relative path to absolute
-	absPath := filepath.Join(root, repoRelativePath)
+	return relPath
+}
 
-	// Get path relative to cwd
-	relPath, err := filepath.Rel(cwd, absPath)
-	if err != nil {
-		return repoRelativePath
+// SetExecutor replaces the executor used for subsequent git commands
+func (r *Repo) SetExecutor(executor Executor) {
+	r.lock <- struct{}{}
+	defer func() { <-r.lock }()
+	r.executor = executor
+}
+
+// SetTimeouts replaces the per-operation time limits
+func (r *Repo) SetTimeouts(timeouts Timeouts) {
+	r.lock <- struct{}{}
+	defer func() { <-r.lock }()
+	r.timeouts = timeouts
+}
+
+// timeoutFor returns the time limit that applies to a git command
+func (r *Repo) timeoutFor(args []string) time.Duration {
+	if len(args) > 0 && networkCommands[args[0]] {
+		return r.timeouts.Network
 	}
+	return r.timeouts.Local
+}
 
-	return relPath
+// execute runs a git command in the repository root through the executor.
+// The returned error is non-nil if the command could not run, exited non-zero,
+// or was cancelled or timed out through ctx.
+func (r *Repo) execute(ctx context.Context, stdin string, args ...string) (Result, error) {
+	return r.executeWithEnv(ctx, nil, stdin, args...)
+}
+
+// executeWithEnv is like execute but adds env (KEY=value) to the
+// repository's environment for this one command
+func (r *Repo) executeWithEnv(ctx context.Context, env []string, stdin string, args ...string) (Result, error) {
+	return r.executeCommand(ctx, Command{Args: args, Stdin: stdin, Env: env})
 }
 
-// Run executes a git command and returns the output
-func Run(args ...string) (string, error) {
-	gitMu.Lock()
-	defer gitMu.Unlock()
+// executeCommand runs cmd in the repository root, adding the repository's
+// environment to cmd.Env
+func (r *Repo) executeCommand(ctx context.Context, cmd Command) (Result, error) {
+	// Wait for any in-flight operation, giving up if ctx is cancelled first
+	select {
+	case r.lock <- struct{}{}:
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+	defer func() { <-r.lock }()
+
+	if timeout := r.timeoutFor(cmd.Args); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = getRepoRoot()
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	cmd.Dir = r.root
+	cmd.Env = append(slices.Clone(r.env), cmd.Env...)
+	result, err := r.executor.Execute(ctx, cmd)
+	if err != nil {
+		return result, err
+	}
+	if result.ExitCode != 0 {
+		return result, &ExitError{Code: result.ExitCode}
+	}
+	return result, nil
+}
 
-	err := cmd.Run()
+// Run executes a git command and returns the output.
+// Failures are returned as *Error, classified where possible.
+func (r *Repo) Run(ctx context.Context, args ...string) (string, error) {
+	result, err := r.execute(ctx, "", args...)
 	if err != nil {
-		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, stderr.String())
+		return "", newError(args, result, err)
 	}
-	return stdout.String(), nil
+	return result.Stdout, nil
 }
 
 // RunAllowFailure executes a git command and returns output even if the command fails
 // (useful for commands like diff --no-index which exit with 1 when there are differences)
-func RunAllowFailure(args ...string) (string, error) {
-	gitMu.Lock()
-	defer gitMu.Unlock()
-
-	cmd := exec.Command("git", args...)
-	cmd.Dir = getRepoRoot()
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
+func (r *Repo) RunAllowFailure(ctx context.Context, args ...string) (string, error) {
+	result, err := r.execute(ctx, "", args...)
 	// Return stdout even if there's an error (diff returns 1 when there are differences)
-	return stdout.String(), err
+	return result.Stdout, err
+}
+
+// runPatch feeds a patch to git apply with the given flags
+func (r *Repo) runPatch(ctx context.Context, patch string, flags ...string) error {
+	args := append([]string{"apply"}, flags...)
+	result, err := r.execute(ctx, patch, args...)
+	if err != nil {
+		return newError(args, result, err)
+	}
+	return nil
 }
 
 // hasCommits checks if the repository has any commits (HEAD exists)
-func hasCommits() bool {
-	_, err := Run("rev-parse", "HEAD")
+func (r *Repo) hasCommits(ctx context.Context) bool {
+	_, err := r.Run(ctx, "rev-parse", "HEAD")
 	return err == nil
 }
 
+// Config returns the value of a git config key, or "" if it isn't set
+func (r *Repo) Config(ctx context.Context, key string) (string, error) {
+	return r.config(ctx, "--get", key)
+}
+
+// config runs git config with args, treating an unset key as empty
+func (r *Repo) config(ctx context.Context, args ...string) (string, error) {
+	args = append([]string{"config"}, args...)
+	result, err := r.execute(ctx, "", args...)
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) && exitErr.Code == 1 {
+		return "", nil
+	}
+	if err != nil {
+		return "", newError(args, result, err)
+	}
+	return strings.TrimRight(result.Stdout, "\n"), nil
+}
+
+// configBool returns a boolean config key, false if it isn't set
+func (r *Repo) configBool(ctx context.Context, key string) (bool, error) {
+	value, err := r.config(ctx, "--type=bool", "--get", key)
+	return value == "true", err
+}
+
+// configList returns every value of a config key that may be repeated,
+// splitting each on commas
+func (r *Repo) configList(ctx context.Context, key string) ([]string, error) {
+	output, err := r.config(ctx, "--get-all", key)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, field := range strings.FieldsFunc(output, func(c rune) bool { return c == ',' || c == '\n' }) {
+		if field = strings.TrimSpace(field); field != "" {
+			values = append(values, field)
+		}
+	}
+	return values, nil
+}
+
 // StageFile stages a file
-func StageFile(path string) error {
-	_, err := Run("add", "--", path)
+func (r *Repo) StageFile(ctx context.Context, path string) error {
+	_, err := r.Run(ctx, "add", "--", path)
 	return err
 }
 
 // StageAll stages all changes (tracked and untracked)
-func StageAll() error {
-	_, err := Run("add", "-A")
+func (r *Repo) StageAll(ctx context.Context) error {
+	_, err := r.Run(ctx, "add", "-A")
 	return err
 }
 
 // UnstageFile unstages a file
-func UnstageFile(path string) error {
+func (r *Repo) UnstageFile(ctx context.Context, path string) error {
 	// When there are no commits, we can't use restore --staged because HEAD doesn't exist.
 	// Use git rm --cached instead.
-	if !hasCommits() {
-		_, err := Run("rm", "--cached", "--", path)
+	if !r.hasCommits(ctx) {
+		_, err := r.Run(ctx, "rm", "--cached", "--", path)
 		return err
 	}
-	_, err := Run("restore", "--staged", "--", path)
+	_, err := r.Run(ctx, "restore", "--staged", "--", path)
 	return err
 }
 
 // UnstageAll unstages all staged changes
-func UnstageAll() error {
+func (r *Repo) UnstageAll(ctx context.Context) error {
 	// When there are no commits, we can't use reset HEAD because HEAD doesn't exist.
 	// Use git rm -r --cached . instead.
-	if !hasCommits() {
-		_, err := Run("rm", "-r", "--cached", ".")
+	if !r.hasCommits(ctx) {
+		_, err := r.Run(ctx, "rm", "-r", "--cached", ".")
 		return err
 	}
-	_, err := Run("reset", "HEAD")
+	_, err := r.Run(ctx, "reset", "HEAD")
 	return err
 }
 
 // StashAll stashes all changes with an optional message
-func StashAll(message string) error {
+func (r *Repo) StashAll(ctx context.Context, message string) error {
 	if message == "" {
-		_, err := Run("stash", "push")
+		_, err := r.Run(ctx, "stash", "push")
 		return err
 	}
-	_, err := Run("stash", "push", "-m", message)
+	_, err := r.Run(ctx, "stash", "push", "-m", message)
 	return err
 }
 
 // StashFiles stashes specific files with an optional message
-func StashFiles(paths []string, message string) error {
+func (r *Repo) StashFiles(ctx context.Context, paths []string, message string) error {
 	args := []string{"stash", "push"}
 	if message != "" {
 		args = append(args, "-m", message)
 	}
 	args = append(args, "--")
 	args = append(args, paths...)
-	_, err := Run(args...)
+	_, err := r.Run(ctx, args...)
 	return err
 }
 
 // DiscardFile discards changes to a tracked file
-func DiscardFile(path string) error {
-	_, err := Run("restore", "--", path)
+func (r *Repo) DiscardFile(ctx context.Context, path string) error {
+	_, err := r.Run(ctx, "restore", "--", path)
 	return err
 }
 
 // DiscardUntracked removes an untracked file
-func DiscardUntracked(path string) error {
-	_, err := Run("clean", "-f", "--", path)
+func (r *Repo) DiscardUntracked(ctx context.Context, path string) error {
+	_, err := r.Run(ctx, "clean", "-f", "--", path)
 	return err
 }
 
 // StageHunk stages a specific hunk using patch mode
-func StageHunk(patch string) error {
-	cmd := exec.Command("git", "apply", "--cached")
-	cmd.Dir = getRepoRoot()
-	cmd.Stdin = strings.NewReader(patch)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		return fmt.Errorf("git apply --cached: %w: %s", err, stderr.String())
-	}
-	return nil
+func (r *Repo) StageHunk(ctx context.Context, patch string) error {
+	return r.runPatch(ctx, patch, "--cached")
 }
 
-// UnstageHunk unstages a specific hunk
-func UnstageHunk(patch string) error {
-	cmd := exec.Command("git", "apply", "--cached", "--reverse")
-	cmd.Dir = getRepoRoot()
-	cmd.Stdin = strings.NewReader(patch)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		return fmt.Errorf("git apply --cached --reverse: %w: %s", err, stderr.String())
-	}
-	return nil
+// CheckEditedHunk checks that a hand-edited patch applies to the index.
+// Line counts in the @@ headers are recomputed, so they needn't be fixed by hand.
+func (r *Repo) CheckEditedHunk(ctx context.Context, patch string) error {
+	return r.runPatch(ctx, patch, "--check", "--cached", "--recount")
 }
 
-// DiscardHunk discards a specific hunk from the working tree
-func DiscardHunk(patch string) error {
-	cmd := exec.Command("git", "apply", "--reverse")
-	cmd.Dir = getRepoRoot()
-	cmd.Stdin = strings.NewReader(patch)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		return fmt.Errorf("git apply --reverse: %w: %s", err, stderr.String())
-	}
-	return nil
+// StageEditedHunk stages a hand-edited patch, recomputing its line counts
+func (r *Repo) StageEditedHunk(ctx context.Context, patch string) error {
+	return r.runPatch(ctx, patch, "--cached", "--recount")
 }
 
-// IsGitRepo checks if the current directory is a git repository
-func IsGitRepo() bool {
-	_, err := Run("rev-parse", "--git-dir")
-	return err == nil
+// UnstageHunk unstages a specific hunk
+func (r *Repo) UnstageHunk(ctx context.Context, patch string) error {
+	return r.runPatch(ctx, patch, "--cached", "--reverse")
+}
+
+// DiscardHunk discards a specific hunk from the working tree
+func (r *Repo) DiscardHunk(ctx context.Context, patch string) error {
+	return r.runPatch(ctx, patch, "--reverse")
 }
 
 // GetBranch returns the current branch name
-func GetBranch() string {
-	output, err := Run("branch", "--show-current")
+func (r *Repo) GetBranch(ctx context.Context) string {
+	output, err := r.Run(ctx, "branch", "--show-current")
 	if err != nil {
 		return "unknown"
 	}
@@ -246,27 +376,101 @@ func GetBranch() string {
 
 // BranchStatus contains tracking information for the current branch
 type BranchStatus struct {
-	Name    string
-	Remote  string // e.g., "origin/master"
-	Ahead   int
-	Behind  int
+	Name     string // empty when HEAD is detached
+	Remote   string // e.g., "origin/master"; empty if there is no usable upstream
+	Upstream string // configured upstream, even if it no longer exists
+	Ahead    int
+	Behind   int
+	Head     string // commit ID of HEAD; empty before the first commit
+	Detached bool
 }
 
 // Push pushes to the remote
-func Push() error {
-	_, err := Run("push")
+func (r *Repo) Push(ctx context.Context) error {
+	_, err := r.runNetwork(ctx, nil, "push")
 	return err
 }
 
 // PushSetUpstream pushes and sets the upstream tracking branch
-func PushSetUpstream(remote, branch string) error {
-	_, err := Run("push", "-u", remote, branch)
+func (r *Repo) PushSetUpstream(ctx context.Context, remote, branch string) error {
+	if branch == "" {
+		return ErrDetachedHead
+	}
+	_, err := r.runNetwork(ctx, nil, "push", "-u", remote, branch)
+	return err
+}
+
+// PullStrategy is how Pull integrates the upstream branch
+type PullStrategy int
+
+const (
+	PullMerge       PullStrategy = iota // merge the upstream into the branch
+	PullRebase                          // rebase local commits onto the upstream
+	PullFastForward                     // only fast-forward, failing if the branches diverged
+)
+
+// String returns the strategy's name
+func (s PullStrategy) String() string {
+	switch s {
+	case PullRebase:
+		return "rebase"
+	case PullFastForward:
+		return "fast-forward"
+	}
+	return "merge"
+}
+
+// PullOptions says how PullWith pulls
+type PullOptions struct {
+	Strategy PullStrategy
+	Progress func(Progress) // if set, called with each progress update; see runNetwork
+}
+
+// Pull integrates the upstream branch with the given strategy
+func (r *Repo) Pull(ctx context.Context, strategy PullStrategy) error {
+	return r.PullWith(ctx, PullOptions{Strategy: strategy})
+}
+
+// PullWith integrates the upstream branch with the given options
+func (r *Repo) PullWith(ctx context.Context, opts PullOptions) error {
+	mode := "--no-rebase"
+	switch opts.Strategy {
+	case PullRebase:
+		mode = "--rebase"
+	case PullFastForward:
+		mode = "--ff-only"
+	}
+	_, err := r.runNetwork(ctx, opts.Progress, "pull", mode)
+	return err
+}
+
+// FetchOptions says what Fetch fetches
+type FetchOptions struct {
+	Remote string // remote to fetch; empty for the upstream's remote
+	All    bool   // fetch every remote, ignoring Remote
+	Prune  bool   // remove remote-tracking branches deleted on the remote
+
+	Progress func(Progress) // if set, called with each progress update; see runNetwork
+}
+
+// Fetch updates remote-tracking branches without touching the working tree
+func (r *Repo) Fetch(ctx context.Context, opts FetchOptions) error {
+	args := []string{"fetch"}
+	if opts.Prune {
+		args = append(args, "--prune")
+	}
+	if opts.All {
+		args = append(args, "--all")
+	} else if opts.Remote != "" {
+		args = append(args, opts.Remote)
+	}
+	_, err := r.runNetwork(ctx, opts.Progress, args...)
 	return err
 }
 
 // GetRemotes returns the list of configured remotes
-func GetRemotes() ([]string, error) {
-	output, err := Run("remote")
+func (r *Repo) GetRemotes(ctx context.Context) ([]string, error) {
+	output, err := r.Run(ctx, "remote")
 	if err != nil {
 		return nil, err
 	}
@@ -277,41 +481,53 @@ func GetRemotes() ([]string, error) {
 	return lines, nil
 }
 
-// Commit creates a commit with the given message
-func Commit(message string) error {
-	_, err := Run("commit", "-m", message)
+// Commit creates a commit with the given message, once the commit-msg
+// hooks accept it
+func (r *Repo) Commit(ctx context.Context, message string) error {
+	if err := r.CheckCommitMessage(ctx, message); err != nil {
+		return err
+	}
+	args := append([]string{"commit"}, r.signArgs()...)
+	_, err := r.Run(ctx, append(args, "-m", message)...)
+	return err
+}
+
+// AmendCommit replaces HEAD with a commit that adds the staged changes.
+// An empty message keeps HEAD's message; a new one is checked by the
+// commit-msg hooks first.
+func (r *Repo) AmendCommit(ctx context.Context, message string) error {
+	args := append([]string{"commit", "--amend"}, r.signArgs()...)
+	if message == "" {
+		args = append(args, "--no-edit")
+	} else {
+		if err := r.CheckCommitMessage(ctx, message); err != nil {
+			return err
+		}
+		args = append(args, "-m", message)
+	}
+	_, err := r.Run(ctx, args...)
 	return err
 }
 
-// GetLog returns the raw git log output
-func GetLog(limit int) (string, error) {
-	return Run("log", fmt.Sprintf("-%d", limit))
+// FixupCommit commits the staged changes as a "fixup!" commit for target,
+// for Autosquash to fold into it
+func (r *Repo) FixupCommit(ctx context.Context, target string) error {
+	args := append([]string{"commit"}, r.signArgs()...)
+	_, err := r.Run(ctx, append(args, "--fixup="+target)...)
+	return err
 }
 
 // GetBranchStatus returns the current branch and its tracking status
-func GetBranchStatus() BranchStatus {
-	status := BranchStatus{
-		Name: GetBranch(),
-	}
-
-	// Get the upstream tracking branch
-	upstream, err := Run("rev-parse", "--abbrev-ref", "@{upstream}")
-	if err != nil {
-		return status // No upstream configured
-	}
-	status.Remote = strings.TrimSpace(upstream)
-
-	// Get ahead/behind counts
-	output, err := Run("rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+func (r *Repo) GetBranchStatus(ctx context.Context) BranchStatus {
+	var status BranchStatus
+	output, err := r.Run(ctx, "status", "--porcelain=v2", "-z", "--branch", "--untracked-files=no")
 	if err != nil {
 		return status
 	}
-
-	parts := strings.Fields(strings.TrimSpace(output))
-	if len(parts) == 2 {
-		fmt.Sscanf(parts[0], "%d", &status.Ahead)
-		fmt.Sscanf(parts[1], "%d", &status.Behind)
+	for _, record := range strings.Split(output, "\x00") {
+		if strings.HasPrefix(record, "# ") {
+			parseBranchHeader(record, &status)
+		}
 	}
-
 	return status
 }
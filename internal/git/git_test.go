@@ -1,18 +1,44 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
-func TestIsGitRepo(t *testing.T) {
+func TestOpen(t *testing.T) {
 	t.Run("valid git repo", func(t *testing.T) {
 		repo := NewTestRepo(t)
 		defer repo.Cleanup()
 
-		if !IsGitRepo() {
-			t.Error("expected IsGitRepo to return true for a valid git repo")
+		r, err := Open(t.Context(), repo.Dir)
+		if err != nil {
+			t.Fatalf("expected Open to succeed for a valid git repo, got %v", err)
+		}
+		if r.GitDir() == "" {
+			t.Error("expected GitDir to be set")
+		}
+	})
+
+	t.Run("subdirectory", func(t *testing.T) {
+		repo := NewTestRepo(t)
+		defer repo.Cleanup()
+
+		repo.WriteFile("sub/file.txt", "content")
+
+		r, err := Open(t.Context(), filepath.Join(repo.Dir, "sub"))
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if r.Root() != repo.Repo.Root() {
+			t.Errorf("expected root %q, got %q", repo.Repo.Root(), r.Root())
+		}
+		if got := r.ToDisplayPath("sub/file.txt"); got != "file.txt" {
+			t.Errorf("expected display path 'file.txt', got %q", got)
 		}
 	})
 
@@ -24,24 +50,61 @@ func TestIsGitRepo(t *testing.T) {
 		}
 		defer os.RemoveAll(dir)
 
-		origDir, _ := os.Getwd()
-		defer os.Chdir(origDir)
-
-		os.Chdir(dir)
-
-		if IsGitRepo() {
-			t.Error("expected IsGitRepo to return false for a non-git directory")
+		if _, err := Open(t.Context(), dir); err == nil {
+			t.Error("expected Open to fail for a non-git directory")
 		}
 	})
 }
 
+func TestOpen_Independent(t *testing.T) {
+	repo1 := NewTestRepo(t)
+	defer repo1.Cleanup()
+	repo2 := NewTestRepo(t)
+	defer repo2.Cleanup()
+
+	repo1.InitialCommit()
+	repo2.InitialCommit()
+	repo1.WriteFile("one.txt", "one")
+	repo2.WriteFile("two.txt", "two")
+
+	status1, err := repo1.Repo.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	status2, err := repo2.Repo.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	if len(status1.Untracked) != 1 || status1.Untracked[0].Path != "one.txt" {
+		t.Errorf("expected repo1 to see only one.txt, got %+v", status1.Untracked)
+	}
+	if len(status2.Untracked) != 1 || status2.Untracked[0].Path != "two.txt" {
+		t.Errorf("expected repo2 to see only two.txt, got %+v", status2.Untracked)
+	}
+}
+
+func TestIsLocked(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	if repo.Repo.IsLocked() {
+		t.Error("expected repo not to be locked")
+	}
+
+	repo.WriteFile(".git/index.lock", "")
+	if !repo.Repo.IsLocked() {
+		t.Error("expected repo to be locked when index.lock exists")
+	}
+}
+
 func TestGetBranch(t *testing.T) {
 	repo := NewTestRepo(t)
 	defer repo.Cleanup()
 
 	repo.InitialCommit()
 
-	branch := GetBranch()
+	branch := repo.Repo.GetBranch(t.Context())
 	// Default branch could be "master" or "main" depending on git config
 	if branch != "master" && branch != "main" {
 		t.Errorf("expected branch to be 'master' or 'main', got %q", branch)
@@ -53,7 +116,7 @@ func TestRun(t *testing.T) {
 	defer repo.Cleanup()
 
 	t.Run("successful command", func(t *testing.T) {
-		output, err := Run("status")
+		output, err := repo.Repo.Run(t.Context(), "status")
 		if err != nil {
 			t.Errorf("expected no error, got %v", err)
 		}
@@ -63,7 +126,7 @@ func TestRun(t *testing.T) {
 	})
 
 	t.Run("failed command", func(t *testing.T) {
-		_, err := Run("invalid-command-that-does-not-exist")
+		_, err := repo.Repo.Run(t.Context(), "invalid-command-that-does-not-exist")
 		if err == nil {
 			t.Error("expected error for invalid command")
 		}
@@ -77,7 +140,7 @@ func TestRunAllowFailure(t *testing.T) {
 	// Create a file to test diff --no-index (which exits with 1 when there are differences)
 	repo.WriteFile("test.txt", "content")
 
-	output, err := RunAllowFailure("diff", "--no-index", "--", "/dev/null", "test.txt")
+	output, err := repo.Repo.RunAllowFailure(t.Context(), "diff", "--no-index", "--", "/dev/null", "test.txt")
 	// diff --no-index returns exit code 1 when there are differences
 	if err == nil {
 		t.Log("diff returned no error (no differences or error suppressed)")
@@ -95,7 +158,7 @@ func TestStageFile(t *testing.T) {
 	repo.InitialCommit()
 	repo.WriteFile("new-file.txt", "new content")
 
-	err := StageFile("new-file.txt")
+	err := repo.Repo.StageFile(t.Context(), "new-file.txt")
 	if err != nil {
 		t.Fatalf("StageFile failed: %v", err)
 	}
@@ -116,7 +179,7 @@ func TestStageAll(t *testing.T) {
 	repo.WriteFile("file2.txt", "content2")
 	repo.WriteFile("subdir/file3.txt", "content3")
 
-	err := StageAll()
+	err := repo.Repo.StageAll(t.Context())
 	if err != nil {
 		t.Fatalf("StageAll failed: %v", err)
 	}
@@ -148,7 +211,7 @@ func TestUnstageFile(t *testing.T) {
 		t.Fatalf("file should be staged initially, got: %s", output)
 	}
 
-	err := UnstageFile("test.txt")
+	err := repo.Repo.UnstageFile(t.Context(), "test.txt")
 	if err != nil {
 		t.Fatalf("UnstageFile failed: %v", err)
 	}
@@ -175,7 +238,7 @@ func TestUnstageAll(t *testing.T) {
 		t.Fatalf("files should be staged initially, got: %s", output)
 	}
 
-	err := UnstageAll()
+	err := repo.Repo.UnstageAll(t.Context())
 	if err != nil {
 		t.Fatalf("UnstageAll failed: %v", err)
 	}
@@ -204,7 +267,7 @@ func TestUnstageFileNoCommits(t *testing.T) {
 		t.Fatalf("file should be staged initially, got: %s", output)
 	}
 
-	err := UnstageFile("test.txt")
+	err := repo.Repo.UnstageFile(t.Context(), "test.txt")
 	if err != nil {
 		t.Fatalf("UnstageFile failed in repo with no commits: %v", err)
 	}
@@ -231,7 +294,7 @@ func TestUnstageAllNoCommits(t *testing.T) {
 		t.Fatalf("files should be staged initially, got: %s", output)
 	}
 
-	err := UnstageAll()
+	err := repo.Repo.UnstageAll(t.Context())
 	if err != nil {
 		t.Fatalf("UnstageAll failed in repo with no commits: %v", err)
 	}
@@ -261,7 +324,7 @@ func TestDiscardFile(t *testing.T) {
 		t.Fatalf("file should be modified, got: %s", output)
 	}
 
-	err := DiscardFile("test.txt")
+	err := repo.Repo.DiscardFile(t.Context(), "test.txt")
 	if err != nil {
 		t.Fatalf("DiscardFile failed: %v", err)
 	}
@@ -291,7 +354,7 @@ func TestDiscardUntracked(t *testing.T) {
 		t.Fatal("untracked file should exist")
 	}
 
-	err := DiscardUntracked("untracked.txt")
+	err := repo.Repo.DiscardUntracked(t.Context(), "untracked.txt")
 	if err != nil {
 		t.Fatalf("DiscardUntracked failed: %v", err)
 	}
@@ -309,7 +372,7 @@ func TestStashAll(t *testing.T) {
 	repo.CommitFile("test.txt", "original", "initial")
 	repo.WriteFile("test.txt", "modified")
 
-	err := StashAll("test stash message")
+	err := repo.Repo.StashAll(t.Context(), "test stash message")
 	if err != nil {
 		t.Fatalf("StashAll failed: %v", err)
 	}
@@ -338,7 +401,7 @@ func TestStashFiles(t *testing.T) {
 	repo.WriteFile("file2.txt", "modified2")
 
 	// Stash only file1.txt
-	err := StashFiles([]string{"file1.txt"}, "partial stash")
+	err := repo.Repo.StashFiles(t.Context(), []string{"file1.txt"}, "partial stash")
 	if err != nil {
 		t.Fatalf("StashFiles failed: %v", err)
 	}
@@ -361,7 +424,7 @@ func TestGetBranchStatus(t *testing.T) {
 
 	repo.InitialCommit()
 
-	status := GetBranchStatus()
+	status := repo.Repo.GetBranchStatus(t.Context())
 	if status.Name != "master" && status.Name != "main" {
 		t.Errorf("expected branch name to be 'master' or 'main', got %q", status.Name)
 	}
@@ -385,42 +448,123 @@ func TestGetBranchStatusWithRemote(t *testing.T) {
 	// Make local commits ahead
 	repo.CommitFile("ahead.txt", "ahead content", "ahead commit")
 
-	status := GetBranchStatus()
+	status := repo.Repo.GetBranchStatus(t.Context())
 	if status.Ahead != 1 {
 		t.Errorf("expected 1 commit ahead, got %d", status.Ahead)
 	}
 }
 
-func TestGetLog(t *testing.T) {
+func TestFetch(t *testing.T) {
 	repo := NewTestRepo(t)
 	defer repo.Cleanup()
 
-	repo.CommitFile("file1.txt", "content1", "First commit")
-	repo.CommitFile("file2.txt", "content2", "Second commit")
-	repo.CommitFile("file3.txt", "content3", "Third commit")
+	repo.InitialCommit()
+	remoteDir := repo.SetupRemote()
+	defer os.RemoveAll(remoteDir)
+	repo.PushToRemote()
+	repo.Git("push", "origin", "HEAD:gone")
+	repo.Git("remote", "add", "mirror", remoteDir)
 
-	log, err := GetLog(2)
-	if err != nil {
-		t.Fatalf("GetLog failed: %v", err)
+	repo.PushFromClone(remoteDir, "theirs.txt", "theirs\n", "theirs")
+	if status := repo.Repo.GetBranchStatus(t.Context()); status.Behind != 0 {
+		t.Fatalf("expected nothing behind before fetching, got %d", status.Behind)
 	}
 
-	if !strings.Contains(log, "Third commit") {
-		t.Errorf("expected log to contain 'Third commit', got: %s", log)
+	if err := repo.Repo.Fetch(t.Context(), FetchOptions{Remote: "origin"}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
 	}
-	if !strings.Contains(log, "Second commit") {
-		t.Errorf("expected log to contain 'Second commit', got: %s", log)
+	if status := repo.Repo.GetBranchStatus(t.Context()); status.Behind != 1 {
+		t.Errorf("expected 1 commit behind after fetching, got %d", status.Behind)
 	}
-	// Should not contain first commit (limit 2)
-	lines := strings.Split(log, "\n")
-	commitCount := 0
-	for _, line := range lines {
-		if strings.HasPrefix(line, "commit ") {
-			commitCount++
-		}
+	if _, err := repo.GitAllowFailure("rev-parse", "--verify", "mirror/gone"); err == nil {
+		t.Error("fetching origin should leave mirror alone")
+	}
+
+	if err := repo.Repo.Fetch(t.Context(), FetchOptions{All: true}); err != nil {
+		t.Fatalf("Fetch --all failed: %v", err)
 	}
-	if commitCount != 2 {
-		t.Errorf("expected 2 commits in log, got %d", commitCount)
+	if _, err := repo.GitAllowFailure("rev-parse", "--verify", "mirror/gone"); err != nil {
+		t.Error("fetching all remotes should fetch mirror")
+	}
+
+	// --prune drops branches deleted on the remote
+	repo.Git("push", "origin", "--delete", "gone")
+	if err := repo.Repo.Fetch(t.Context(), FetchOptions{All: true}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
 	}
+	if _, err := repo.GitAllowFailure("rev-parse", "--verify", "mirror/gone"); err != nil {
+		t.Error("fetching without --prune should keep mirror/gone")
+	}
+	if err := repo.Repo.Fetch(t.Context(), FetchOptions{Remote: "mirror", Prune: true}); err != nil {
+		t.Fatalf("Fetch --prune failed: %v", err)
+	}
+	if _, err := repo.GitAllowFailure("rev-parse", "--verify", "mirror/gone"); err == nil {
+		t.Error("fetching with --prune should remove mirror/gone")
+	}
+}
+
+func TestPullStrategies(t *testing.T) {
+	setup := func(t *testing.T) *TestRepo {
+		repo := NewTestRepo(t)
+		repo.CommitFile("base.txt", "base\n", "base")
+		remoteDir := repo.SetupRemote()
+		t.Cleanup(func() { os.RemoveAll(remoteDir) })
+		repo.PushToRemote()
+		repo.PushFromClone(remoteDir, "theirs.txt", "theirs\n", "theirs")
+		return repo
+	}
+
+	t.Run("fast-forward", func(t *testing.T) {
+		repo := setup(t)
+		defer repo.Cleanup()
+		if err := repo.Repo.Pull(t.Context(), PullFastForward); err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+		if status := repo.Repo.GetBranchStatus(t.Context()); status.Ahead != 0 || status.Behind != 0 {
+			t.Errorf("expected to be up to date, got %d ahead, %d behind", status.Ahead, status.Behind)
+		}
+	})
+
+	t.Run("fast-forward diverged", func(t *testing.T) {
+		repo := setup(t)
+		defer repo.Cleanup()
+		repo.CommitFile("ours.txt", "ours\n", "ours")
+		if err := repo.Repo.Pull(t.Context(), PullFastForward); !errors.Is(err, ErrNotFastForward) {
+			t.Fatalf("expected ErrNotFastForward, got %v", err)
+		}
+	})
+
+	t.Run("rebase", func(t *testing.T) {
+		repo := setup(t)
+		defer repo.Cleanup()
+		repo.CommitFile("ours.txt", "ours\n", "ours")
+		if err := repo.Repo.Pull(t.Context(), PullRebase); err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+		status := repo.Repo.GetBranchStatus(t.Context())
+		if status.Ahead != 1 || status.Behind != 0 {
+			t.Errorf("expected 1 ahead, 0 behind, got %d ahead, %d behind", status.Ahead, status.Behind)
+		}
+		if parents := strings.Fields(repo.Git("log", "-1", "--format=%P")); len(parents) != 1 {
+			t.Errorf("rebase should not make a merge commit, HEAD has parents %v", parents)
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		repo := setup(t)
+		defer repo.Cleanup()
+		repo.CommitFile("ours.txt", "ours\n", "ours")
+		if err := repo.Repo.Pull(t.Context(), PullMerge); err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+		status := repo.Repo.GetBranchStatus(t.Context())
+		if status.Ahead != 2 || status.Behind != 0 {
+			t.Errorf("expected 2 ahead, 0 behind, got %d ahead, %d behind", status.Ahead, status.Behind)
+		}
+		if parents := strings.Fields(repo.Git("log", "-1", "--format=%P")); len(parents) != 2 {
+			t.Errorf("merge should make a merge commit, HEAD has parents %v", parents)
+		}
+	})
 }
 
 func TestStageHunk(t *testing.T) {
@@ -434,7 +578,7 @@ func TestStageHunk(t *testing.T) {
 	repo.WriteFile("test.txt", "line1\nmodified2\nline3\nmodified4\nline5\n")
 
 	// Get the unstaged diff
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -450,13 +594,13 @@ func TestStageHunk(t *testing.T) {
 	// Generate and apply patch for first hunk
 	patch := diff.Files[0].Hunks[0].GeneratePatch(&diff.Files[0])
 
-	err = StageHunk(patch)
+	err = repo.Repo.StageHunk(t.Context(), patch)
 	if err != nil {
 		t.Fatalf("StageHunk failed: %v", err)
 	}
 
 	// Verify something is staged
-	stagedDiff, err := GetStagedDiff()
+	stagedDiff, err := repo.Repo.GetStagedDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetStagedDiff failed: %v", err)
 	}
@@ -476,7 +620,7 @@ func TestUnstageHunk(t *testing.T) {
 	repo.Git("add", "test.txt")
 
 	// Get the staged diff
-	stagedDiff, err := GetStagedDiff()
+	stagedDiff, err := repo.Repo.GetStagedDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetStagedDiff failed: %v", err)
 	}
@@ -488,13 +632,13 @@ func TestUnstageHunk(t *testing.T) {
 	// Generate patch and unstage
 	patch := stagedDiff.Files[0].Hunks[0].GeneratePatch(&stagedDiff.Files[0])
 
-	err = UnstageHunk(patch)
+	err = repo.Repo.UnstageHunk(t.Context(), patch)
 	if err != nil {
 		t.Fatalf("UnstageHunk failed: %v", err)
 	}
 
 	// Verify nothing is staged
-	stagedDiff, err = GetStagedDiff()
+	stagedDiff, err = repo.Repo.GetStagedDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetStagedDiff failed: %v", err)
 	}
@@ -515,7 +659,7 @@ func TestDiscardHunk(t *testing.T) {
 	repo.WriteFile("test.txt", "line1\nmodified\nline3\n")
 
 	// Get the unstaged diff
-	diff, err := GetDiff()
+	diff, err := repo.Repo.GetDiff(t.Context())
 	if err != nil {
 		t.Fatalf("GetDiff failed: %v", err)
 	}
@@ -527,7 +671,7 @@ func TestDiscardHunk(t *testing.T) {
 	// Generate patch and discard
 	patch := diff.Files[0].Hunks[0].GeneratePatch(&diff.Files[0])
 
-	err = DiscardHunk(patch)
+	err = repo.Repo.DiscardHunk(t.Context(), patch)
 	if err != nil {
 		t.Fatalf("DiscardHunk failed: %v", err)
 	}
@@ -538,3 +682,240 @@ func TestDiscardHunk(t *testing.T) {
 		t.Errorf("expected original content, got: %s", content)
 	}
 }
+
+func TestExecutor_RoutesAllCommands(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("test.txt", "line1\nline2\nline3\n", "initial")
+	repo.WriteFile("test.txt", "line1\nmodified\nline3\n")
+
+	var calls []Command
+	repo.Repo.SetExecutor(ExecutorFunc(func(ctx context.Context, cmd Command) (Result, error) {
+		calls = append(calls, cmd)
+		return ProcessExecutor{}.Execute(ctx, cmd)
+	}))
+
+	diff, err := repo.Repo.GetDiff(t.Context())
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	patch := diff.Files[0].Hunks[0].GeneratePatch(&diff.Files[0])
+	if err := repo.Repo.StageHunk(t.Context(), patch); err != nil {
+		t.Fatalf("StageHunk failed: %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 executor calls, got %d", len(calls))
+	}
+	if strings.Join(calls[0].Args, " ") != "diff" {
+		t.Errorf("expected first call to be 'diff', got %v", calls[0].Args)
+	}
+	if strings.Join(calls[1].Args, " ") != "apply --cached" {
+		t.Errorf("expected second call to be 'apply --cached', got %v", calls[1].Args)
+	}
+	if calls[1].Stdin != patch {
+		t.Error("expected patch to be passed on stdin")
+	}
+	if calls[1].Dir != repo.Repo.Root() {
+		t.Errorf("expected command to run in %q, got %q", repo.Repo.Root(), calls[1].Dir)
+	}
+}
+
+func TestExecutor_Fake(t *testing.T) {
+	fake := ExecutorFunc(func(ctx context.Context, cmd Command) (Result, error) {
+		switch strings.Join(cmd.Args, " ") {
+		case "rev-parse --show-toplevel --absolute-git-dir":
+			return Result{Stdout: "/fake\n/fake/.git\n"}, nil
+		case "branch --show-current":
+			return Result{Stdout: "fake-branch\n"}, nil
+		default:
+			return Result{Stderr: "fatal: unexpected command", ExitCode: 128}, nil
+		}
+	})
+
+	repo, err := OpenWithExecutor(t.Context(), t.TempDir(), fake)
+	if err != nil {
+		t.Fatalf("OpenWithExecutor failed: %v", err)
+	}
+	if repo.Root() != "/fake" || repo.GitDir() != "/fake/.git" {
+		t.Errorf("unexpected root/git dir: %q %q", repo.Root(), repo.GitDir())
+	}
+	if branch := repo.GetBranch(t.Context()); branch != "fake-branch" {
+		t.Errorf("expected fake-branch, got %q", branch)
+	}
+
+	_, err = repo.Run(t.Context(), "push")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 128 {
+		t.Fatalf("expected ExitError with code 128, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "unexpected command") {
+		t.Errorf("expected stderr in error message, got %v", err)
+	}
+}
+
+func TestExecutor_Env(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.Repo.SetEnv([]string{"GIT_AUTHOR_NAME=Env Author"})
+	repo.WriteFile("file.txt", "content")
+	repo.Git("add", "file.txt")
+
+	if err := repo.Repo.Commit(t.Context(), "env commit"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	author := repo.Git("log", "-1", "--format=%an")
+	if strings.TrimSpace(author) != "Env Author" {
+		t.Errorf("expected author from env, got %q", author)
+	}
+}
+
+// blockingExecutor answers the repository lookup and then blocks every
+// other command until its context is done
+func blockingExecutor(started chan<- struct{}) ExecutorFunc {
+	return func(ctx context.Context, cmd Command) (Result, error) {
+		if cmd.Args[0] == "rev-parse" {
+			return Result{Stdout: "/fake\n/fake/.git\n"}, nil
+		}
+		if started != nil {
+			started <- struct{}{}
+		}
+		<-ctx.Done()
+		return Result{}, ctx.Err()
+	}
+}
+
+func TestExecute_Cancel(t *testing.T) {
+	started := make(chan struct{}, 1)
+	repo, err := OpenWithExecutor(t.Context(), t.TempDir(), blockingExecutor(started))
+	if err != nil {
+		t.Fatalf("OpenWithExecutor failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	err = repo.Push(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestExecute_Timeout(t *testing.T) {
+	repo, err := OpenWithExecutor(t.Context(), t.TempDir(), blockingExecutor(nil))
+	if err != nil {
+		t.Fatalf("OpenWithExecutor failed: %v", err)
+	}
+	repo.SetTimeouts(Timeouts{Local: 10 * time.Millisecond, Network: time.Hour})
+
+	_, err = repo.Run(t.Context(), "status")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestExecute_NetworkTimeout(t *testing.T) {
+	repo, err := OpenWithExecutor(t.Context(), t.TempDir(), blockingExecutor(nil))
+	if err != nil {
+		t.Fatalf("OpenWithExecutor failed: %v", err)
+	}
+	repo.SetTimeouts(Timeouts{Local: time.Hour, Network: 10 * time.Millisecond})
+
+	if err := repo.Push(t.Context()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected push to hit the network timeout, got %v", err)
+	}
+}
+
+func TestExecute_CancelWhileWaitingForLock(t *testing.T) {
+	started := make(chan struct{}, 1)
+	repo, err := OpenWithExecutor(t.Context(), t.TempDir(), blockingExecutor(started))
+	if err != nil {
+		t.Fatalf("OpenWithExecutor failed: %v", err)
+	}
+
+	// Hold the lock with a long-running command
+	holdCtx, release := context.WithCancel(t.Context())
+	defer release()
+	done := make(chan struct{})
+	go func() {
+		repo.Run(holdCtx, "status")
+		close(done)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := repo.Run(ctx, "diff"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected waiting for the lock to time out, got %v", err)
+	}
+
+	release()
+	<-done
+}
+
+func TestProcessExecutor_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := ProcessExecutor{}.Execute(ctx, Command{Dir: t.TempDir(), Args: []string{"--version"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAmendCommit(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.CommitFile("file.txt", "one\n", "Add file\n\nWith a body.")
+
+	repo.WriteFile("file.txt", "two\n")
+	repo.Git("add", "file.txt")
+	if err := repo.Repo.AmendCommit(t.Context(), ""); err != nil {
+		t.Fatalf("AmendCommit failed: %v", err)
+	}
+	if got := repo.Git("log", "-1", "--format=%B"); strings.TrimSpace(got) != "Add file\n\nWith a body." {
+		t.Errorf("amend without a message should keep HEAD's, got %q", got)
+	}
+	if got := repo.Git("show", "HEAD:file.txt"); got != "two\n" {
+		t.Errorf("amend should include the staged change, got %q", got)
+	}
+	if got := strings.TrimSpace(repo.Git("rev-list", "--count", "HEAD")); got != "2" {
+		t.Errorf("amend should replace HEAD, got %s commits", got)
+	}
+
+	// Message-only amend, nothing staged
+	if err := repo.Repo.AmendCommit(t.Context(), "Add file, reworded"); err != nil {
+		t.Fatalf("AmendCommit failed: %v", err)
+	}
+	if got := repo.Git("log", "-1", "--format=%s"); strings.TrimSpace(got) != "Add file, reworded" {
+		t.Errorf("subject = %q", got)
+	}
+}
+
+func TestFixupCommit(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.CommitFile("file.txt", "one\n", "Add file")
+	target := strings.TrimSpace(repo.Git("rev-parse", "HEAD"))
+	repo.CommitFile("other.txt", "other\n", "Add other")
+
+	repo.WriteFile("file.txt", "two\n")
+	repo.Git("add", "file.txt")
+	if err := repo.Repo.FixupCommit(t.Context(), target); err != nil {
+		t.Fatalf("FixupCommit failed: %v", err)
+	}
+	if got := repo.Git("log", "-1", "--format=%s"); strings.TrimSpace(got) != "fixup! Add file" {
+		t.Errorf("subject = %q, want a fixup! commit", got)
+	}
+
+	// Nothing staged
+	if err := repo.Repo.FixupCommit(t.Context(), target); !errors.Is(err, ErrNothingToCommit) {
+		t.Errorf("expected ErrNothingToCommit, got %v", err)
+	}
+}
@@ -0,0 +1,395 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is a single entry of the commit log
+type Commit struct {
+	Hash           string
+	Parents        []string
+	AuthorName     string
+	AuthorEmail    string
+	AuthorDate     time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommitDate     time.Time
+	Subject        string
+	Body           string
+	Refs           []Ref // branches, tags and HEAD pointing at the commit
+	Signature      Signature
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// IsMerge returns true if the commit has more than one parent
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// RefKind classifies a ref decoration
+type RefKind int
+
+const (
+	RefBranch RefKind = iota
+	RefRemote
+	RefTag
+	RefHead  // detached HEAD
+	RefOther // anything outside heads, remotes and tags
+)
+
+// Ref is a decoration on a commit in the log
+type Ref struct {
+	Name string // short name, e.g. "main", "origin/main", "v1.0"
+	Kind RefKind
+	Head bool // HEAD points at this branch
+}
+
+// String formats the ref like git log's decorations
+func (r Ref) String() string {
+	switch {
+	case r.Head:
+		return "HEAD -> " + r.Name
+	case r.Kind == RefTag:
+		return "tag: " + r.Name
+	}
+	return r.Name
+}
+
+// logFormat separates fields with the unit separator and ends each commit
+// with the record separator, neither of which appear in commit messages.
+// The signature fields are left empty: checking them runs gpg or
+// ssh-keygen for every signed commit.
+const logFormat = "--format=%H%x1f%P%x1f%an%x1f%ae%x1f%at%x1f%cn%x1f%ce%x1f%ct%x1f%D%x1f%x1f%x1f%x1f%s%x1f%b%x1e"
+
+// signedLogFormat is logFormat with the signature fields filled in
+const signedLogFormat = "--format=%H%x1f%P%x1f%an%x1f%ae%x1f%at%x1f%cn%x1f%ce%x1f%ct%x1f%D%x1f%G?%x1f%GS%x1f%GK%x1f%s%x1f%b%x1e"
+
+const logFieldCount = 14
+
+// LogFilter narrows the commits returned by GetLog. The zero value
+// matches every commit.
+type LogFilter struct {
+	Author    string   // --author
+	Grep      string   // --grep: search commit messages
+	Pickaxe   string   // -S: commits that change how often this string occurs
+	DiffRegex string   // -G: commits with an added or removed line matching this regex
+	Since     string   // --since
+	Until     string   // --until
+	Paths     []string // only commits touching these paths
+	Follow    bool     // --follow a single path across renames
+}
+
+// IsEmpty returns true if the filter matches every commit
+func (f LogFilter) IsEmpty() bool {
+	return f.String() == ""
+}
+
+// KeepsTopology returns true if the filtered commits still link up through
+// their parents. Path filters rewrite parents to the nearest matching
+// commit; the other filters skip commits and leave parents dangling.
+func (f LogFilter) KeepsTopology() bool {
+	return f.Author == "" && f.Grep == "" && f.Pickaxe == "" && f.DiffRegex == "" &&
+		f.Since == "" && f.Until == "" && !f.Follow
+}
+
+// args returns the git log options for the filter; pathspecs come last
+func (f LogFilter) args() []string {
+	var args []string
+	if f.Author != "" {
+		args = append(args, "--author="+f.Author)
+	}
+	if f.Grep != "" {
+		args = append(args, "--grep="+f.Grep)
+	}
+	if f.Pickaxe != "" {
+		args = append(args, "-S"+f.Pickaxe)
+	}
+	if f.DiffRegex != "" {
+		args = append(args, "-G"+f.DiffRegex)
+	}
+	if f.Since != "" {
+		args = append(args, "--since="+f.Since)
+	}
+	if f.Until != "" {
+		args = append(args, "--until="+f.Until)
+	}
+	if len(f.Paths) > 0 {
+		if f.Follow {
+			args = append(args, "--follow")
+		} else {
+			args = append(args, "--parents")
+		}
+		args = append(args, "--")
+		args = append(args, f.Paths...)
+	}
+	return args
+}
+
+// String formats the filter as git log options, the same syntax
+// ParseLogFilter reads
+func (f LogFilter) String() string {
+	var parts []string
+	option := func(name, value string) {
+		if value != "" {
+			parts = append(parts, name+quoteFilterValue(value))
+		}
+	}
+	option("--author=", f.Author)
+	option("--grep=", f.Grep)
+	option("-S", f.Pickaxe)
+	option("-G", f.DiffRegex)
+	option("--since=", f.Since)
+	option("--until=", f.Until)
+	if f.Follow {
+		parts = append(parts, "--follow")
+	}
+	if len(f.Paths) > 0 {
+		parts = append(parts, "--")
+		for _, path := range f.Paths {
+			parts = append(parts, quoteFilterValue(path))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func quoteFilterValue(value string) string {
+	if strings.ContainsAny(value, " \t\"'") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// ParseLogFilter parses git log style filter options, e.g.
+// `--author=alice --grep="fix crash" -S needle --since=2.weeks -- src/`.
+// Words that aren't options are taken as paths.
+func ParseLogFilter(input string) (LogFilter, error) {
+	words, err := splitFilterWords(input)
+	if err != nil {
+		return LogFilter{}, err
+	}
+
+	var f LogFilter
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "--" {
+			f.Paths = append(f.Paths, words[i+1:]...)
+			break
+		}
+		if word == "--follow" {
+			f.Follow = true
+			continue
+		}
+		if !strings.HasPrefix(word, "-") {
+			f.Paths = append(f.Paths, word)
+			continue
+		}
+
+		// Split "--name=value", "-Svalue" and "--name value"
+		name, value, hasValue := word, "", false
+		if strings.HasPrefix(word, "--") {
+			name, value, hasValue = strings.Cut(word, "=")
+		} else if len(word) > 2 {
+			name, value, hasValue = word[:2], word[2:], true
+		}
+		var target *string
+		switch name {
+		case "--author":
+			target = &f.Author
+		case "--grep":
+			target = &f.Grep
+		case "-S":
+			target = &f.Pickaxe
+		case "-G":
+			target = &f.DiffRegex
+		case "--since", "--after":
+			target = &f.Since
+		case "--until", "--before":
+			target = &f.Until
+		default:
+			return LogFilter{}, fmt.Errorf("unknown filter option %s", name)
+		}
+		if !hasValue {
+			if i+1 >= len(words) {
+				return LogFilter{}, fmt.Errorf("%s needs a value", name)
+			}
+			i++
+			value = words[i]
+		}
+		*target = value
+	}
+
+	if f.Pickaxe != "" && f.DiffRegex != "" {
+		return LogFilter{}, fmt.Errorf("use either -S or -G, not both")
+	}
+	if f.Follow && len(f.Paths) != 1 {
+		return LogFilter{}, fmt.Errorf("--follow needs exactly one path")
+	}
+	return f, nil
+}
+
+// splitFilterWords splits input on whitespace, keeping quoted strings
+// together. Double-quoted strings may contain Go escapes.
+func splitFilterWords(input string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '"':
+			quoted, err := strconv.QuotedPrefix(input[i:])
+			if err != nil {
+				return nil, fmt.Errorf("unterminated quote in filter")
+			}
+			unquoted, _ := strconv.Unquote(quoted)
+			word.WriteString(unquoted)
+			i += len(quoted) - 1
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(input[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in filter")
+			}
+			word.WriteString(input[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// GetLog returns up to limit commits reachable from HEAD that match the
+// filter, newest first, after skipping the first skip of them. Successive
+// pages line up as long as history doesn't change between calls. The
+// commits' signatures are not checked; see GetSignedLog.
+func (r *Repo) GetLog(ctx context.Context, filter LogFilter, skip, limit int) ([]Commit, error) {
+	return r.getLog(ctx, logFormat, filter, skip, limit)
+}
+
+// GetSignedLog is like GetLog but also verifies each commit's signature
+func (r *Repo) GetSignedLog(ctx context.Context, filter LogFilter, skip, limit int) ([]Commit, error) {
+	return r.getLog(ctx, signedLogFormat, filter, skip, limit)
+}
+
+func (r *Repo) getLog(ctx context.Context, format string, filter LogFilter, skip, limit int) ([]Commit, error) {
+	if !r.hasCommits(ctx) {
+		return nil, nil
+	}
+	args := []string{"log", fmt.Sprintf("--skip=%d", skip), fmt.Sprintf("--max-count=%d", limit), "--decorate=full", format}
+	output, err := r.Run(ctx, append(args, filter.args()...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output), nil
+}
+
+// parseLog parses git log output produced with logFormat or signedLogFormat
+func parseLog(output string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x1f", logFieldCount)
+		if len(fields) < logFieldCount {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:           fields[0],
+			Parents:        strings.Fields(fields[1]),
+			AuthorName:     fields[2],
+			AuthorEmail:    fields[3],
+			AuthorDate:     parseUnixTime(fields[4]),
+			CommitterName:  fields[5],
+			CommitterEmail: fields[6],
+			CommitDate:     parseUnixTime(fields[7]),
+			Refs:           parseRefs(fields[8]),
+			Signature: Signature{
+				Status: parseSignatureStatus(fields[9]),
+				Signer: fields[10],
+				Key:    fields[11],
+			},
+			Subject: fields[12],
+			Body:    strings.TrimRight(fields[13], "\n"),
+		})
+	}
+	return commits
+}
+
+func parseUnixTime(s string) time.Time {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+// parseRefs parses a %D decoration list with full ref names, like
+// "HEAD -> refs/heads/main, tag: refs/tags/v1.0, refs/remotes/origin/main"
+func parseRefs(s string) []Ref {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var refs []Ref
+	for _, decoration := range strings.Split(s, ", ") {
+		var ref Ref
+		name := decoration
+		if rest, ok := strings.CutPrefix(name, "HEAD -> "); ok {
+			ref.Head = true
+			name = rest
+		}
+		name = strings.TrimPrefix(name, "tag: ")
+		switch {
+		case name == "HEAD":
+			ref.Name, ref.Kind = name, RefHead
+		case strings.HasPrefix(name, "refs/heads/"):
+			ref.Name, ref.Kind = strings.TrimPrefix(name, "refs/heads/"), RefBranch
+		case strings.HasPrefix(name, "refs/remotes/"):
+			ref.Name, ref.Kind = strings.TrimPrefix(name, "refs/remotes/"), RefRemote
+		case strings.HasPrefix(name, "refs/tags/"):
+			ref.Name, ref.Kind = strings.TrimPrefix(name, "refs/tags/"), RefTag
+		default:
+			ref.Name, ref.Kind = strings.TrimPrefix(name, "refs/"), RefOther
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// GetCommitDiff returns the changes introduced by a commit. Merge commits
+// are diffed against their first parent. Like stash diffs, the hunks are
+// returned as the unstaged side of the result.
+func (r *Repo) GetCommitDiff(ctx context.Context, hash string) (*CombinedDiffResult, error) {
+	output, err := r.Run(ctx, "show", "--format=", "--patch", "--diff-merges=first-parent", hash)
+	if err != nil {
+		return nil, err
+	}
+	return &CombinedDiffResult{
+		StagedDiff:   &DiffResult{},
+		UnstagedDiff: r.parseDiff(output),
+	}, nil
+}
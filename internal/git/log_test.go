@@ -0,0 +1,310 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGetLog(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("file1.txt", "content1", "First commit")
+	repo.CommitFile("file2.txt", "content2", "Second commit")
+	repo.CommitFile("file3.txt", "content3", "Third commit\n\nWith a body\nover two lines")
+
+	commits, err := repo.Repo.GetLog(t.Context(), LogFilter{}, 0, 2)
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+
+	// Should not contain first commit (limit 2)
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits in log, got %d", len(commits))
+	}
+
+	head := commits[0]
+	if head.Subject != "Third commit" {
+		t.Errorf("expected subject 'Third commit', got %q", head.Subject)
+	}
+	if head.Body != "With a body\nover two lines" {
+		t.Errorf("expected body to be parsed, got %q", head.Body)
+	}
+	if head.AuthorName != "Test User" || head.AuthorEmail != "test@example.com" {
+		t.Errorf("unexpected author %q <%s>", head.AuthorName, head.AuthorEmail)
+	}
+	if head.AuthorDate.IsZero() || head.CommitDate.IsZero() {
+		t.Error("expected author and commit dates to be set")
+	}
+	if len(head.Hash) != 40 || len(head.ShortHash()) != 7 {
+		t.Errorf("unexpected hash %q", head.Hash)
+	}
+	if len(head.Parents) != 1 || head.Parents[0] != commits[1].Hash {
+		t.Errorf("expected parent %s, got %v", commits[1].Hash, head.Parents)
+	}
+	if len(head.Refs) != 1 || !head.Refs[0].Head || head.Refs[0].Kind != RefBranch {
+		t.Errorf("expected HEAD -> branch decoration, got %v", head.Refs)
+	}
+
+	if commits[1].Subject != "Second commit" || commits[1].Body != "" {
+		t.Errorf("unexpected second commit %q / %q", commits[1].Subject, commits[1].Body)
+	}
+	if commits[1].Refs != nil {
+		t.Errorf("expected no decorations, got %v", commits[1].Refs)
+	}
+}
+
+func TestGetLogPages(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	for i := range 5 {
+		repo.CommitFile("file.txt", fmt.Sprintf("%d\n", i), fmt.Sprintf("Commit %d", i))
+	}
+
+	var subjects []string
+	for skip := 0; ; skip += 2 {
+		page, err := repo.Repo.GetLog(t.Context(), LogFilter{}, skip, 2)
+		if err != nil {
+			t.Fatalf("GetLog failed: %v", err)
+		}
+		for _, c := range page {
+			subjects = append(subjects, c.Subject)
+		}
+		if len(page) < 2 {
+			break
+		}
+	}
+
+	want := "Commit 4,Commit 3,Commit 2,Commit 1,Commit 0"
+	if got := strings.Join(subjects, ","); got != want {
+		t.Errorf("expected pages to cover the log in order, got %s", got)
+	}
+}
+
+func TestGetLogNoCommits(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	commits, err := repo.Repo.GetLog(t.Context(), LogFilter{}, 0, 10)
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+	if len(commits) != 0 {
+		t.Errorf("expected no commits, got %d", len(commits))
+	}
+}
+
+func TestParseLogDecorations(t *testing.T) {
+	decorations := "HEAD -> refs/heads/main, refs/remotes/origin/main, tag: refs/tags/v1.0, refs/heads/feature/x, refs/stash"
+	output := "abc\x1f\x1fA\x1fa@x\x1f0\x1fC\x1fc@x\x1f0\x1f" + decorations + "\x1fN\x1f\x1f\x1fsubject\x1f\x1e\n"
+
+	commits := parseLog(output)
+	if len(commits) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(commits))
+	}
+	want := []Ref{
+		{Name: "main", Kind: RefBranch, Head: true},
+		{Name: "origin/main", Kind: RefRemote},
+		{Name: "v1.0", Kind: RefTag},
+		{Name: "feature/x", Kind: RefBranch},
+		{Name: "stash", Kind: RefOther},
+	}
+	if len(commits[0].Refs) != len(want) {
+		t.Fatalf("expected refs %v, got %v", want, commits[0].Refs)
+	}
+	for i, ref := range want {
+		if commits[0].Refs[i] != ref {
+			t.Errorf("ref %d: expected %+v, got %+v", i, ref, commits[0].Refs[i])
+		}
+	}
+	if got := commits[0].Refs[2].String(); got != "tag: v1.0" {
+		t.Errorf("expected tag to format as 'tag: v1.0', got %q", got)
+	}
+	if len(commits[0].Parents) != 0 {
+		t.Errorf("expected root commit to have no parents, got %v", commits[0].Parents)
+	}
+}
+
+func TestParseLogDetachedHead(t *testing.T) {
+	refs := parseRefs("HEAD, refs/heads/main")
+	if len(refs) != 2 || refs[0].Kind != RefHead || refs[0].String() != "HEAD" || refs[1].Head {
+		t.Errorf("expected detached HEAD and a plain branch, got %+v", refs)
+	}
+}
+
+func TestGetCommitDiff(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("a.txt", "one\n", "add a")
+	repo.WriteFile("a.txt", "two\n")
+	repo.WriteFile("b.txt", "new\n")
+	repo.Git("add", ".")
+	repo.Git("commit", "-m", "change a, add b")
+
+	commits, err := repo.Repo.GetLog(t.Context(), LogFilter{}, 0, 1)
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+
+	diff, err := repo.Repo.GetCommitDiff(t.Context(), commits[0].Hash)
+	if err != nil {
+		t.Fatalf("GetCommitDiff failed: %v", err)
+	}
+	hunks := diff.GetAllHunksCombined()
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(hunks))
+	}
+	if hunks[0].FilePath != "a.txt" || hunks[1].FilePath != "b.txt" {
+		t.Errorf("unexpected files %q, %q", hunks[0].FilePath, hunks[1].FilePath)
+	}
+	if hunks[0].Staged {
+		t.Error("commit hunks should not be marked staged")
+	}
+}
+
+func TestGetCommitDiffMerge(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.CreateBranch("feature", true)
+	repo.CommitFile("feature.txt", "feature\n", "feature work")
+	repo.Git("checkout", "-")
+	repo.CommitFile("main.txt", "main\n", "main work")
+	repo.Git("merge", "--no-ff", "-m", "merge feature", "feature")
+
+	commits, err := repo.Repo.GetLog(t.Context(), LogFilter{}, 0, 1)
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+	if !commits[0].IsMerge() {
+		t.Fatalf("expected a merge commit, got parents %v", commits[0].Parents)
+	}
+
+	diff, err := repo.Repo.GetCommitDiff(t.Context(), commits[0].Hash)
+	if err != nil {
+		t.Fatalf("GetCommitDiff failed: %v", err)
+	}
+	hunks := diff.GetAllHunksCombined()
+	if len(hunks) != 1 || hunks[0].FilePath != "feature.txt" {
+		t.Errorf("expected the merged feature.txt against the first parent, got %v", hunks)
+	}
+}
+
+func TestParseLogFilter(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LogFilter
+		wantErr bool
+	}{
+		{input: "", want: LogFilter{}},
+		{input: "--author=alice", want: LogFilter{Author: "alice"}},
+		{input: "--author alice --grep='fix crash'", want: LogFilter{Author: "alice", Grep: "fix crash"}},
+		{input: `--grep="say \"hi\""`, want: LogFilter{Grep: `say "hi"`}},
+		{input: "-Sneedle", want: LogFilter{Pickaxe: "needle"}},
+		{input: "-S needle", want: LogFilter{Pickaxe: "needle"}},
+		{input: "-G 'func \\w+'", want: LogFilter{DiffRegex: `func \w+`}},
+		{input: "--since=2.weeks --before=2024-01-01", want: LogFilter{Since: "2.weeks", Until: "2024-01-01"}},
+		{input: "src/ main.go", want: LogFilter{Paths: []string{"src/", "main.go"}}},
+		{input: "--follow -- -odd-name", want: LogFilter{Paths: []string{"-odd-name"}, Follow: true}},
+		{input: "--bogus", wantErr: true},
+		{input: "--author", wantErr: true},
+		{input: "-S a -G b", wantErr: true},
+		{input: "--follow a b", wantErr: true},
+		{input: "--grep='open", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLogFilter(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLogFilter failed: %v", err)
+			}
+			if got.String() != tt.want.String() {
+				t.Errorf("got %q, want %q", got.String(), tt.want.String())
+			}
+			// The formatted filter parses back to the same filter
+			again, err := ParseLogFilter(got.String())
+			if err != nil || again.String() != got.String() {
+				t.Errorf("round trip of %q gave %q (%v)", got.String(), again.String(), err)
+			}
+		})
+	}
+}
+
+func TestGetLogFilter(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("a.txt", "needle\n", "Add a")
+	repo.CommitFile("b.txt", "hay\n", "Add b")
+	repo.Git("-c", "user.name=Other", "commit", "--allow-empty", "-m", "Fix crash")
+	repo.CommitFile("a.txt", "needle\nmore\n", "Grow a")
+
+	subjects := func(filter LogFilter) string {
+		t.Helper()
+		commits, err := repo.Repo.GetLog(t.Context(), filter, 0, 10)
+		if err != nil {
+			t.Fatalf("GetLog(%s) failed: %v", filter, err)
+		}
+		var s []string
+		for _, c := range commits {
+			s = append(s, c.Subject)
+		}
+		return strings.Join(s, ",")
+	}
+
+	if got := subjects(LogFilter{Author: "Other"}); got != "Fix crash" {
+		t.Errorf("author filter: got %s", got)
+	}
+	if got := subjects(LogFilter{Grep: "crash"}); got != "Fix crash" {
+		t.Errorf("grep filter: got %s", got)
+	}
+	if got := subjects(LogFilter{Pickaxe: "needle"}); got != "Add a" {
+		t.Errorf("pickaxe filter: got %s", got)
+	}
+	if got := subjects(LogFilter{DiffRegex: "^mo"}); got != "Grow a" {
+		t.Errorf("diff regex filter: got %s", got)
+	}
+
+	commits, err := repo.Repo.GetLog(t.Context(), LogFilter{Paths: []string{"a.txt"}}, 0, 10)
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Parents[0] != commits[1].Hash {
+		t.Errorf("path filter should rewrite parents to the previous matching commit, got %+v", commits)
+	}
+}
+
+func TestGetLogFollow(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("old.txt", "line one\nline two\nline three\n", "Create")
+	repo.Git("mv", "old.txt", "new.txt")
+	repo.Git("commit", "-m", "Rename")
+
+	filter := LogFilter{Paths: []string{"new.txt"}, Follow: true}
+	commits, err := repo.Repo.GetLog(t.Context(), filter, 0, 10)
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+	if len(commits) != 2 || commits[1].Subject != "Create" {
+		t.Errorf("expected history to follow the rename, got %d commits", len(commits))
+	}
+	if filter.KeepsTopology() {
+		t.Error("--follow should not claim to keep topology")
+	}
+	if !(LogFilter{Paths: []string{"new.txt"}}).KeepsTopology() {
+		t.Error("path filters keep topology")
+	}
+}
@@ -0,0 +1,162 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Conventional widths LintCommitMessage checks a message against
+const (
+	SubjectLimit  = 50
+	BodyLineLimit = 72
+)
+
+// messageHistoryFile holds the messages recorded by SaveMessage, oldest
+// first and separated by NUL bytes
+const messageHistoryFile = "go-on-git-messages"
+
+// MessageHistoryLimit is the number of messages SaveMessage keeps
+const MessageHistoryLimit = 50
+
+// scissorsLine marks where git commit --verbose starts the diff in
+// COMMIT_EDITMSG; everything from it on is dropped
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// CommitMsgHook checks a commit message before Commit or AmendCommit
+// hands it to git. A non-nil error stops the commit and is returned as is.
+type CommitMsgHook func(ctx context.Context, message string) error
+
+// AddCommitMsgHook registers a hook run on every message committed through
+// the repository. Hooks run in the order they were added, before git's own
+// commit-msg hook.
+func (r *Repo) AddCommitMsgHook(hook CommitMsgHook) {
+	r.settings.Lock()
+	defer r.settings.Unlock()
+	r.commitMsgHooks = append(r.commitMsgHooks, hook)
+}
+
+// CheckCommitMessage runs the registered commit-msg hooks on message,
+// stopping at the first that fails
+func (r *Repo) CheckCommitMessage(ctx context.Context, message string) error {
+	r.settings.Lock()
+	hooks := slices.Clone(r.commitMsgHooks)
+	r.settings.Unlock()
+	for _, hook := range hooks {
+		if err := hook(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LintCommitMessage returns style warnings for a commit message: a subject
+// longer than SubjectLimit, a missing blank line after the subject, and
+// body lines longer than BodyLineLimit. Comment lines are not checked.
+func LintCommitMessage(message string) []string {
+	lines := strings.Split(StripComments(message), "\n")
+	var warnings []string
+	if n := utf8.RuneCountInString(lines[0]); n > SubjectLimit {
+		warnings = append(warnings, fmt.Sprintf("subject is %d characters, keep it within %d", n, SubjectLimit))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		warnings = append(warnings, "leave a blank line between the subject and the body")
+	}
+	for i := 1; i < len(lines); i++ {
+		if n := utf8.RuneCountInString(lines[i]); n > BodyLineLimit {
+			warnings = append(warnings, fmt.Sprintf("line %d is %d characters, wrap the body at %d", i+1, n, BodyLineLimit))
+			break
+		}
+	}
+	return warnings
+}
+
+// StripComments removes the lines starting with "#" that git commit drops
+// from an edited message, along with the blank lines left at either end
+func StripComments(message string) string {
+	var kept []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Trim(strings.Join(kept, "\n"), "\n")
+}
+
+// CommitTemplate returns the contents of the commit.template file, or ""
+// if none is configured. A relative path is taken from the working tree
+// root, as git commit does.
+func (r *Repo) CommitTemplate(ctx context.Context) (string, error) {
+	path, err := r.config(ctx, "--path", "--get", "commit.template")
+	if err != nil || path == "" {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.root, path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading commit.template: %w", err)
+	}
+	return string(content), nil
+}
+
+// MessageHistory returns the messages recorded by SaveMessage, newest first
+func (r *Repo) MessageHistory() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(r.gitDir, messageHistoryFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var messages []string
+	for _, message := range strings.Split(string(data), "\x00") {
+		if message != "" {
+			messages = append(messages, message)
+		}
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// SaveMessage records a commit message in the repository's history, so it
+// can be reused after the commit fails or is abandoned. Saving a message
+// again moves it to the front; only the newest MessageHistoryLimit are kept.
+func (r *Repo) SaveMessage(message string) error {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil
+	}
+	history, err := r.MessageHistory()
+	if err != nil {
+		return err
+	}
+	history = slices.DeleteFunc(history, func(m string) bool { return m == message })
+	history = append([]string{message}, history...)
+	if len(history) > MessageHistoryLimit {
+		history = history[:MessageHistoryLimit]
+	}
+	slices.Reverse(history)
+	return os.WriteFile(filepath.Join(r.gitDir, messageHistoryFile), []byte(strings.Join(history, "\x00")), 0o644)
+}
+
+// SavedCommitMessage returns the message of the last commit git prepared,
+// from COMMIT_EDITMSG, without comments or a --verbose diff. It returns ""
+// if git hasn't saved one.
+func (r *Repo) SavedCommitMessage() (string, error) {
+	data, err := os.ReadFile(filepath.Join(r.gitDir, "COMMIT_EDITMSG"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	message, _, _ := strings.Cut(string(data), scissorsLine)
+	return StripComments(message), nil
+}
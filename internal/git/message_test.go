@@ -0,0 +1,153 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLintCommitMessage(t *testing.T) {
+	long := strings.Repeat("x", SubjectLimit+1)
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"clean", "Add a thing\n\nBecause we need it.", nil},
+		{"subject only", "Add a thing", nil},
+		{"long subject", long, []string{"subject is 51 characters, keep it within 50"}},
+		{"no blank line", "Add a thing\nBecause we need it.", []string{"leave a blank line between the subject and the body"}},
+		{"long body line", "Add a thing\n\n" + strings.Repeat("y", BodyLineLimit+1), []string{"line 3 is 73 characters, wrap the body at 72"}},
+		{"comments ignored", "# " + long + "\nAdd a thing\n# comment", nil},
+		{"multibyte subject", strings.Repeat("é", SubjectLimit), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LintCommitMessage(tt.message)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("LintCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	got := StripComments("\n# Please enter a message\nSubject\n\n#123 is a comment too\nBody\n# trailing\n")
+	if got != "Subject\n\nBody" {
+		t.Errorf("StripComments() = %q", got)
+	}
+}
+
+func TestCommitTemplate(t *testing.T) {
+	repo := NewTestRepo(t)
+
+	template, err := repo.Repo.CommitTemplate(t.Context())
+	if err != nil || template != "" {
+		t.Fatalf("without commit.template got %q, %v", template, err)
+	}
+
+	// A relative path is read from the working tree root
+	repo.WriteFile(".gitmessage", "Subject\n\n# Why:\n")
+	repo.Git("config", "commit.template", ".gitmessage")
+	template, err = repo.Repo.CommitTemplate(t.Context())
+	if err != nil {
+		t.Fatalf("CommitTemplate failed: %v", err)
+	}
+	if template != "Subject\n\n# Why:\n" {
+		t.Errorf("template = %q", template)
+	}
+
+	repo.Git("config", "commit.template", "missing")
+	if _, err := repo.Repo.CommitTemplate(t.Context()); err == nil {
+		t.Error("expected an error for a missing template file")
+	}
+}
+
+func TestCommitMsgHook(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("file.txt", "one\n", "Initial")
+
+	errWIP := errors.New("no WIP commits")
+	var seen []string
+	repo.Repo.AddCommitMsgHook(func(_ context.Context, message string) error {
+		seen = append(seen, message)
+		if strings.HasPrefix(message, "WIP") {
+			return errWIP
+		}
+		return nil
+	})
+
+	repo.WriteFile("file.txt", "two\n")
+	repo.Git("add", "file.txt")
+	if err := repo.Repo.Commit(t.Context(), "WIP stuff"); !errors.Is(err, errWIP) {
+		t.Fatalf("expected the hook's error, got %v", err)
+	}
+	if got := strings.TrimSpace(repo.Git("rev-list", "--count", "HEAD")); got != "1" {
+		t.Errorf("a rejected message should not be committed, got %s commits", got)
+	}
+	if err := repo.Repo.AmendCommit(t.Context(), "WIP again"); !errors.Is(err, errWIP) {
+		t.Errorf("amend should run the hook too, got %v", err)
+	}
+
+	// Keeping HEAD's message needs no check
+	if err := repo.Repo.AmendCommit(t.Context(), ""); err != nil {
+		t.Fatalf("AmendCommit failed: %v", err)
+	}
+	if err := repo.Repo.Commit(t.Context(), "Real work"); err == nil {
+		t.Error("expected nothing to commit after the amend")
+	}
+	if strings.Join(seen, "|") != "WIP stuff|WIP again|Real work" {
+		t.Errorf("hook saw %q", seen)
+	}
+}
+
+func TestMessageHistory(t *testing.T) {
+	repo := NewTestRepo(t)
+
+	history, err := repo.Repo.MessageHistory()
+	if err != nil || len(history) != 0 {
+		t.Fatalf("expected no history, got %q, %v", history, err)
+	}
+
+	for _, message := range []string{"First", "Second\n\nWith a body\n", "  ", "First"} {
+		if err := repo.Repo.SaveMessage(message); err != nil {
+			t.Fatalf("SaveMessage failed: %v", err)
+		}
+	}
+	history, err = repo.Repo.MessageHistory()
+	if err != nil {
+		t.Fatalf("MessageHistory failed: %v", err)
+	}
+	if strings.Join(history, "|") != "First|Second\n\nWith a body" {
+		t.Errorf("history = %q, want newest first without duplicates", history)
+	}
+
+	for i := range MessageHistoryLimit + 5 {
+		repo.Repo.SaveMessage(strings.Repeat("x", i+1))
+	}
+	history, _ = repo.Repo.MessageHistory()
+	if len(history) != MessageHistoryLimit || history[0] != strings.Repeat("x", MessageHistoryLimit+5) {
+		t.Errorf("expected the newest %d messages, got %d", MessageHistoryLimit, len(history))
+	}
+}
+
+func TestSavedCommitMessage(t *testing.T) {
+	repo := NewTestRepo(t)
+
+	message, err := repo.Repo.SavedCommitMessage()
+	if err != nil || message != "" {
+		t.Fatalf("expected no saved message, got %q, %v", message, err)
+	}
+
+	repo.CommitFile("file.txt", "content\n", "Add file\n\nWith a body")
+	message, err = repo.Repo.SavedCommitMessage()
+	if err != nil || message != "Add file\n\nWith a body" {
+		t.Errorf("message = %q, %v", message, err)
+	}
+
+	repo.WriteFile(".git/COMMIT_EDITMSG", "Verbose\n# Please enter\n"+scissorsLine+"\ndiff --git a/x b/x\n")
+	if message, _ := repo.Repo.SavedCommitMessage(); message != "Verbose" {
+		t.Errorf("message = %q, want the diff dropped", message)
+	}
+}
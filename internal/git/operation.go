@@ -0,0 +1,152 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Operation is a multi-step git command that can stop part-way through
+type Operation int
+
+const (
+	OpNone Operation = iota
+	OpMerge
+	OpRebase
+	OpCherryPick
+	OpRevert
+	OpBisect
+	OpApplyMailbox // git am
+)
+
+func (o Operation) String() string {
+	switch o {
+	case OpMerge:
+		return "merge"
+	case OpRebase:
+		return "rebase"
+	case OpCherryPick:
+		return "cherry-pick"
+	case OpRevert:
+		return "revert"
+	case OpBisect:
+		return "bisect"
+	case OpApplyMailbox:
+		return "am"
+	default:
+		return ""
+	}
+}
+
+// CanSkip returns true if the operation supports --skip
+func (o Operation) CanSkip() bool {
+	return o != OpNone && o != OpMerge
+}
+
+// CanContinue returns true if the operation supports --continue
+func (o Operation) CanContinue() bool {
+	return o != OpNone && o != OpBisect
+}
+
+// OperationState describes the operation in progress, if any
+type OperationState struct {
+	Op    Operation
+	Step  int // current step for rebase and am; 0 if unknown
+	Total int
+}
+
+// InProgress returns true if an operation is in progress
+func (s OperationState) InProgress() bool {
+	return s.Op != OpNone
+}
+
+// GetOperationState detects an in-progress operation from the state
+// files git leaves in the git directory
+func (r *Repo) GetOperationState() OperationState {
+	switch {
+	case r.gitPathExists("rebase-merge"):
+		return OperationState{
+			Op:    OpRebase,
+			Step:  r.readGitInt("rebase-merge/msgnum"),
+			Total: r.readGitInt("rebase-merge/end"),
+		}
+	case r.gitPathExists("rebase-apply"):
+		op := OpRebase
+		if r.gitPathExists("rebase-apply/applying") {
+			op = OpApplyMailbox
+		}
+		return OperationState{
+			Op:    op,
+			Step:  r.readGitInt("rebase-apply/next"),
+			Total: r.readGitInt("rebase-apply/last"),
+		}
+	case r.gitPathExists("MERGE_HEAD"):
+		return OperationState{Op: OpMerge}
+	case r.gitPathExists("CHERRY_PICK_HEAD"):
+		return OperationState{Op: OpCherryPick}
+	case r.gitPathExists("REVERT_HEAD"):
+		return OperationState{Op: OpRevert}
+	case r.gitPathExists("BISECT_LOG"):
+		return OperationState{Op: OpBisect}
+	}
+	return OperationState{}
+}
+
+func (r *Repo) gitPathExists(name string) bool {
+	_, err := os.Stat(filepath.Join(r.gitDir, name))
+	return err == nil
+}
+
+func (r *Repo) readGitInt(name string) int {
+	data, err := os.ReadFile(filepath.Join(r.gitDir, name))
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return n
+}
+
+// ContinueOperation resumes the operation after conflicts are resolved,
+// reusing the prepared commit message instead of opening an editor.
+// GIT_EDITOR takes precedence over core.editor, so it is set rather than
+// the config.
+func (r *Repo) ContinueOperation(ctx context.Context, op Operation) error {
+	if !op.CanContinue() {
+		return fmt.Errorf("%s cannot be continued", op)
+	}
+	args := []string{op.String(), "--continue"}
+	result, err := r.executeWithEnv(ctx, []string{"GIT_EDITOR=true"}, "", args...)
+	if err != nil {
+		return newError(args, result, err)
+	}
+	return nil
+}
+
+// SkipOperation skips the current step of the operation
+func (r *Repo) SkipOperation(ctx context.Context, op Operation) error {
+	if !op.CanSkip() {
+		return fmt.Errorf("%s cannot be skipped", op)
+	}
+	if op == OpBisect {
+		_, err := r.Run(ctx, "bisect", "skip")
+		return err
+	}
+	_, err := r.Run(ctx, op.String(), "--skip")
+	return err
+}
+
+// AbortOperation abandons the operation and restores the pre-operation state
+func (r *Repo) AbortOperation(ctx context.Context, op Operation) error {
+	switch op {
+	case OpNone:
+		return fmt.Errorf("no operation in progress")
+	case OpBisect:
+		_, err := r.Run(ctx, "bisect", "reset")
+		return err
+	}
+	_, err := r.Run(ctx, op.String(), "--abort")
+	return err
+}
@@ -0,0 +1,189 @@
+package git
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetOperationStateNone(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+
+	state := repo.Repo.GetOperationState()
+	if state.InProgress() {
+		t.Errorf("expected no operation, got %s", state.Op)
+	}
+}
+
+func TestOperationMergeAbort(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", "base\n", "ours\n", "theirs\n")
+
+	status, err := repo.Repo.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if status.Operation.Op != OpMerge {
+		t.Fatalf("expected merge in progress, got %q", status.Operation.Op)
+	}
+
+	if err := repo.Repo.AbortOperation(t.Context(), OpMerge); err != nil {
+		t.Fatalf("AbortOperation failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.InProgress() {
+		t.Errorf("expected merge to be aborted, got %s", state.Op)
+	}
+	if got := repo.ReadFile("file.txt"); got != "ours\n" {
+		t.Errorf("expected file restored to ours, got %q", got)
+	}
+}
+
+func TestOperationMergeContinue(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.CreateConflict("file.txt", "base\n", "ours\n", "theirs\n")
+
+	// Continuing with unresolved paths fails and leaves the merge in place
+	err := repo.Repo.ContinueOperation(t.Context(), OpMerge)
+	if !errors.Is(err, ErrMergeConflict) {
+		t.Errorf("expected ErrMergeConflict, got %v", err)
+	}
+
+	if err := repo.Repo.TakeTheirs(t.Context(), "file.txt"); err != nil {
+		t.Fatalf("TakeTheirs failed: %v", err)
+	}
+	// The user's editor must not be started, whatever it is
+	t.Setenv("GIT_EDITOR", "false")
+	if err := repo.Repo.ContinueOperation(t.Context(), OpMerge); err != nil {
+		t.Fatalf("ContinueOperation failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.InProgress() {
+		t.Errorf("expected merge to be finished, got %s", state.Op)
+	}
+	if parents := repo.Git("log", "-1", "--format=%P"); len(parents) < 80 {
+		t.Errorf("expected a merge commit, got parents %q", parents)
+	}
+}
+
+func TestOperationRebase(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.CommitFile("file.txt", "base\n", "base")
+	repo.CreateBranch("feature", true)
+	repo.CommitFile("file.txt", "feature\n", "feature change")
+	repo.CommitFile("other.txt", "other\n", "feature other")
+	repo.Git("checkout", "-")
+	repo.CommitFile("file.txt", "main\n", "main change")
+	repo.Git("checkout", "feature")
+
+	if _, err := repo.GitAllowFailure("rebase", "-"); err == nil {
+		t.Fatal("expected rebase to conflict")
+	}
+
+	state := repo.Repo.GetOperationState()
+	if state.Op != OpRebase {
+		t.Fatalf("expected rebase in progress, got %q", state.Op)
+	}
+	if state.Step != 1 || state.Total != 2 {
+		t.Errorf("expected step 1/2, got %d/%d", state.Step, state.Total)
+	}
+
+	if err := repo.Repo.SkipOperation(t.Context(), OpRebase); err != nil {
+		t.Fatalf("SkipOperation failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.InProgress() {
+		t.Errorf("expected rebase to be finished, got %s", state.Op)
+	}
+	if got := repo.ReadFile("file.txt"); got != "main\n" {
+		t.Errorf("expected skipped commit to be dropped, got %q", got)
+	}
+	if !repo.FileExists("other.txt") {
+		t.Error("expected remaining commit to be applied")
+	}
+}
+
+func TestOperationCherryPickContinue(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.CommitFile("file.txt", "base\n", "base")
+	repo.CreateBranch("other", true)
+	repo.CommitFile("file.txt", "other\n", "other change")
+	repo.Git("checkout", "-")
+	repo.CommitFile("file.txt", "main\n", "main change")
+
+	if _, err := repo.GitAllowFailure("cherry-pick", "other"); err == nil {
+		t.Fatal("expected cherry-pick to conflict")
+	}
+	if state := repo.Repo.GetOperationState(); state.Op != OpCherryPick {
+		t.Fatalf("expected cherry-pick in progress, got %q", state.Op)
+	}
+
+	if err := repo.Repo.TakeTheirs(t.Context(), "file.txt"); err != nil {
+		t.Fatalf("TakeTheirs failed: %v", err)
+	}
+	if err := repo.Repo.ContinueOperation(t.Context(), OpCherryPick); err != nil {
+		t.Fatalf("ContinueOperation failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.InProgress() {
+		t.Errorf("expected cherry-pick to be finished, got %s", state.Op)
+	}
+	if subject := repo.Git("log", "-1", "--format=%s"); subject != "other change\n" {
+		t.Errorf("expected picked commit message to be kept, got %q", subject)
+	}
+}
+
+func TestOperationRevert(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.CommitFile("file.txt", "one\n", "one")
+	repo.CommitFile("file.txt", "two\n", "two")
+
+	if _, err := repo.GitAllowFailure("revert", "--no-edit", "HEAD~1"); err == nil {
+		t.Fatal("expected revert to conflict")
+	}
+	if state := repo.Repo.GetOperationState(); state.Op != OpRevert {
+		t.Fatalf("expected revert in progress, got %q", state.Op)
+	}
+
+	if err := repo.Repo.AbortOperation(t.Context(), OpRevert); err != nil {
+		t.Fatalf("AbortOperation failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.InProgress() {
+		t.Errorf("expected revert to be aborted, got %s", state.Op)
+	}
+}
+
+func TestOperationBisect(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	repo.CommitFile("file.txt", "one\n", "one")
+	repo.CommitFile("file.txt", "two\n", "two")
+	repo.Git("bisect", "start", "HEAD", "HEAD~2")
+
+	if state := repo.Repo.GetOperationState(); state.Op != OpBisect {
+		t.Fatalf("expected bisect in progress, got %q", state.Op)
+	}
+	if err := repo.Repo.ContinueOperation(t.Context(), OpBisect); err == nil {
+		t.Error("expected bisect to not support continue")
+	}
+
+	if err := repo.Repo.AbortOperation(t.Context(), OpBisect); err != nil {
+		t.Fatalf("AbortOperation failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.InProgress() {
+		t.Errorf("expected bisect to be reset, got %s", state.Op)
+	}
+}
+
+func TestOperationSkipUnsupported(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+
+	if err := repo.Repo.SkipOperation(t.Context(), OpMerge); err == nil {
+		t.Error("expected merge to not support skip")
+	}
+	if err := repo.Repo.AbortOperation(t.Context(), OpNone); err == nil {
+		t.Error("expected abort without an operation to fail")
+	}
+}
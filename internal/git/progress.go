@@ -0,0 +1,100 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Progress is one update from the progress meter git prints to stderr
+// while it counts, compresses, writes or receives objects
+type Progress struct {
+	Phase      string // e.g. "Counting objects" or "Writing objects"
+	Remote     bool   // reported by the remote end ("remote: ...")
+	Percent    int    // -1 for phases that only count, such as "Enumerating objects"
+	Current    int
+	Total      int
+	Throughput string // e.g. "1.20 MiB | 600.00 KiB/s", when git reports it
+	Done       bool   // the phase has finished
+}
+
+var (
+	progressPercentPattern = regexp.MustCompile(`^([A-Z][A-Za-z ]*?):\s+(\d+)% \((\d+)/(\d+)\)(.*)$`)
+	progressCountPattern   = regexp.MustCompile(`^([A-Z][A-Za-z ]*?): (\d+)(, done\.)?$`)
+)
+
+// ParseProgress parses one line of git's progress output, as split at
+// the carriage returns and newlines git writes between updates
+func ParseProgress(line string) (Progress, bool) {
+	line = strings.TrimSpace(line)
+	var p Progress
+	if rest, found := strings.CutPrefix(line, "remote: "); found {
+		line = strings.TrimSpace(rest)
+		p.Remote = true
+	}
+	if m := progressPercentPattern.FindStringSubmatch(line); m != nil {
+		p.Phase = m[1]
+		p.Percent, _ = strconv.Atoi(m[2])
+		p.Current, _ = strconv.Atoi(m[3])
+		p.Total, _ = strconv.Atoi(m[4])
+		rest, done := strings.CutSuffix(m[5], ", done.")
+		p.Throughput = strings.TrimPrefix(rest, ", ")
+		p.Done = done
+		return p, true
+	}
+	if m := progressCountPattern.FindStringSubmatch(line); m != nil {
+		p.Phase = m[1]
+		p.Percent = -1
+		p.Current, _ = strconv.Atoi(m[2])
+		p.Done = m[3] != ""
+		return p, true
+	}
+	return Progress{}, false
+}
+
+// progressWriter splits stderr into lines as it is written and reports
+// the progress updates among them
+type progressWriter struct {
+	mu         sync.Mutex
+	buf        []byte
+	onProgress func(Progress)
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		if p, ok := ParseProgress(string(w.buf[:i])); ok {
+			w.onProgress(p)
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// runNetwork runs a command that talks to a remote. If onProgress is not
+// nil, the command runs with --progress and onProgress is called for each
+// update as git prints it: from the goroutine reading git's stderr, and
+// not after the command returns.
+func (r *Repo) runNetwork(ctx context.Context, onProgress func(Progress), args ...string) (string, error) {
+	if onProgress == nil {
+		return r.Run(ctx, args...)
+	}
+	args = append([]string{args[0], "--progress"}, args[1:]...)
+	result, err := r.executeCommand(ctx, Command{
+		Args:   args,
+		Stderr: &progressWriter{onProgress: onProgress},
+	})
+	if err != nil {
+		return "", newError(args, result, err)
+	}
+	return result.Stdout, nil
+}
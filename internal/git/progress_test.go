@@ -0,0 +1,99 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		line string
+		want Progress
+		ok   bool
+	}{
+		{"Counting objects:  40% (2/5)", Progress{Phase: "Counting objects", Percent: 40, Current: 2, Total: 5}, true},
+		{"Compressing objects: 100% (5/5), done.", Progress{Phase: "Compressing objects", Percent: 100, Current: 5, Total: 5, Done: true}, true},
+		{
+			"Writing objects: 100% (3/3), 293.29 KiB | 24.44 MiB/s, done.",
+			Progress{Phase: "Writing objects", Percent: 100, Current: 3, Total: 3, Throughput: "293.29 KiB | 24.44 MiB/s", Done: true},
+			true,
+		},
+		{
+			"Receiving objects:  45% (450/1000), 1.20 MiB | 1.10 MiB/s",
+			Progress{Phase: "Receiving objects", Percent: 45, Current: 450, Total: 1000, Throughput: "1.20 MiB | 1.10 MiB/s"},
+			true,
+		},
+		{"remote: Compressing objects:  33% (1/3)        ", Progress{Phase: "Compressing objects", Remote: true, Percent: 33, Current: 1, Total: 3}, true},
+		{"Enumerating objects: 5, done.", Progress{Phase: "Enumerating objects", Percent: -1, Current: 5, Done: true}, true},
+		{"Total 3 (delta 1), reused 0 (delta 0), pack-reused 0", Progress{}, false},
+		{"   5888668..08d99c2  main -> main", Progress{}, false},
+		{"To ../remote.git", Progress{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseProgress(tt.line)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseProgress(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	var got []string
+	w := &progressWriter{onProgress: func(p Progress) {
+		got = append(got, fmt.Sprintf("%s %d/%d", p.Phase, p.Current, p.Total))
+	}}
+	// Updates split across writes, separated by \r and \n
+	for _, chunk := range []string{"Counting objects:  50% (1/2)\rCount", "ing objects: 100% (2/2)\r", "Counting objects: 100% (2/2), done.\nTo remote\n"} {
+		w.Write([]byte(chunk))
+	}
+	want := []string{"Counting objects 1/2", "Counting objects 2/2", "Counting objects 2/2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("updates = %q, want %q", got, want)
+	}
+}
+
+func TestPushFetchPullProgress(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("file.txt", strings.Repeat("content\n", 1000), "initial")
+	remoteDir := repo.SetupRemote()
+	defer os.RemoveAll(remoteDir)
+	repo.PushToRemote()
+	repo.CommitFile("more.txt", strings.Repeat("more\n", 1000), "more")
+
+	var updates []Progress
+	onProgress := func(p Progress) { updates = append(updates, p) }
+	if err := repo.Repo.PushWith(t.Context(), PushOptions{Progress: onProgress}); err != nil {
+		t.Fatalf("PushWith failed: %v", err)
+	}
+	if !slices.ContainsFunc(updates, func(p Progress) bool { return p.Phase == "Writing objects" && p.Done }) {
+		t.Errorf("expected a finished Writing objects update, got %+v", updates)
+	}
+
+	updates = nil
+	repo.PushFromClone(remoteDir, "theirs.txt", strings.Repeat("theirs\n", 1000), "theirs")
+	if err := repo.Repo.Fetch(t.Context(), FetchOptions{Remote: "origin", Progress: onProgress}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if !slices.ContainsFunc(updates, func(p Progress) bool { return p.Remote && p.Phase == "Counting objects" }) {
+		t.Errorf("expected the remote's Counting objects updates, got %+v", updates)
+	}
+
+	updates = nil
+	repo.PushFromClone(remoteDir, "again.txt", strings.Repeat("again\n", 1000), "again")
+	if err := repo.Repo.PullWith(t.Context(), PullOptions{Strategy: PullFastForward, Progress: onProgress}); err != nil {
+		t.Fatalf("PullWith failed: %v", err)
+	}
+	if len(updates) == 0 {
+		t.Error("expected progress updates from the pull")
+	}
+
+	// Without a callback nothing changes
+	if err := repo.Repo.Fetch(t.Context(), FetchOptions{}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+}
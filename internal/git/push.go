@@ -0,0 +1,103 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// PushOptions are the less common ways to push
+type PushOptions struct {
+	Remote      string // remote to push to; empty for the upstream's remote
+	Branch      string // remote branch to push HEAD to; empty for the upstream branch
+	SetUpstream bool   // -u
+
+	// ForceWithLease overwrites the remote branch only if it is still
+	// where we think it is: at Lease, or at its remote-tracking branch if
+	// Lease is empty. Needs Branch to name the branch the lease is for.
+	ForceWithLease  bool
+	Lease           string
+	ForceIfIncludes bool // also require HEAD to include the remote-tracking branch
+
+	NoVerify bool     // skip the pre-push hook
+	Options  []string // -o push options passed to the remote's hooks
+	Tags     bool     // push all tags as well
+
+	Progress func(Progress) // if set, called with each progress update; see runNetwork
+}
+
+// args returns the git push arguments for the options
+func (o PushOptions) args() []string {
+	args := []string{"push"}
+	if o.SetUpstream {
+		args = append(args, "-u")
+	}
+	if o.ForceWithLease {
+		lease := "--force-with-lease"
+		if o.Branch != "" {
+			lease += "=" + o.Branch
+			if o.Lease != "" {
+				lease += ":" + o.Lease
+			}
+		}
+		args = append(args, lease)
+		if o.ForceIfIncludes {
+			args = append(args, "--force-if-includes")
+		}
+	}
+	if o.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	for _, option := range o.Options {
+		args = append(args, "-o", option)
+	}
+	if o.Tags {
+		args = append(args, "--tags")
+	}
+	if o.Remote != "" {
+		args = append(args, o.Remote)
+		if o.Branch != "" {
+			args = append(args, "HEAD:refs/heads/"+o.Branch)
+		}
+	}
+	return args
+}
+
+// PushWith pushes HEAD with the given options
+func (r *Repo) PushWith(ctx context.Context, opts PushOptions) error {
+	_, err := r.runNetwork(ctx, opts.Progress, opts.args()...)
+	return err
+}
+
+// Upstream returns the remote and remote branch the current branch
+// tracks, or empty strings if it has no upstream
+func (r *Repo) Upstream(ctx context.Context) (remote, branch string, err error) {
+	current := r.GetBranch(ctx)
+	if current == "" || current == "unknown" {
+		return "", "", nil
+	}
+	if remote, err = r.Config(ctx, "branch."+current+".remote"); err != nil {
+		return "", "", err
+	}
+	merge, err := r.Config(ctx, "branch."+current+".merge")
+	if err != nil {
+		return "", "", err
+	}
+	return remote, strings.TrimPrefix(merge, "refs/heads/"), nil
+}
+
+// RemoteBranchHead returns the commit the remote-tracking branch for
+// branch on remote points at, as of the last fetch or push, or "" if
+// there is none
+func (r *Repo) RemoteBranchHead(ctx context.Context, remote, branch string) (string, error) {
+	args := []string{"rev-parse", "--verify", "--quiet", "refs/remotes/" + remote + "/" + branch}
+	result, err := r.execute(ctx, "", args...)
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) && exitErr.Code == 1 {
+		return "", nil
+	}
+	if err != nil {
+		return "", newError(args, result, err)
+	}
+	return strings.TrimSpace(result.Stdout), nil
+}
@@ -0,0 +1,183 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPushOptionsArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts PushOptions
+		want string
+	}{
+		{"default", PushOptions{}, "push"},
+		{"other branch", PushOptions{Remote: "fork", Branch: "topic", SetUpstream: true}, "push -u fork HEAD:refs/heads/topic"},
+		{"lease", PushOptions{Remote: "origin", Branch: "main", ForceWithLease: true, Lease: "abc"}, "push --force-with-lease=main:abc origin HEAD:refs/heads/main"},
+		{"lease on tracking branch", PushOptions{ForceWithLease: true, ForceIfIncludes: true}, "push --force-with-lease --force-if-includes"},
+		{"if-includes needs a lease", PushOptions{ForceIfIncludes: true}, "push"},
+		{"hooks and tags", PushOptions{NoVerify: true, Options: []string{"ci.skip", "reviewer=jane"}, Tags: true}, "push --no-verify -o ci.skip -o reviewer=jane --tags"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(tt.opts.args(), " "); got != tt.want {
+				t.Errorf("args() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// setupDivergedRemote pushes a commit, then lets someone else push over
+// it while the repo makes a commit of its own
+func setupDivergedRemote(t *testing.T) (repo *TestRepo, remoteDir, pushed string) {
+	t.Helper()
+	repo = NewTestRepo(t)
+	repo.CommitFile("base.txt", "base\n", "base")
+	remoteDir = repo.SetupRemote()
+	t.Cleanup(func() { os.RemoveAll(remoteDir) })
+	repo.PushToRemote()
+	pushed = strings.TrimSpace(repo.Git("rev-parse", "HEAD"))
+	repo.PushFromClone(remoteDir, "theirs.txt", "theirs\n", "theirs")
+	repo.CommitFile("ours.txt", "ours\n", "ours")
+	return repo, remoteDir, pushed
+}
+
+func remoteHead(t *testing.T, remoteDir, ref string) string {
+	t.Helper()
+	output, err := exec.Command("git", "-C", remoteDir, "rev-parse", ref).CombinedOutput()
+	if err != nil {
+		t.Fatalf("rev-parse %s in the remote failed: %v\n%s", ref, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestPushForceWithLease(t *testing.T) {
+	repo, remoteDir, pushed := setupDivergedRemote(t)
+	defer repo.Cleanup()
+	ctx := t.Context()
+
+	remote, branch, err := repo.Repo.Upstream(ctx)
+	if err != nil || remote != "origin" || branch == "" {
+		t.Fatalf("Upstream() = %q, %q, %v", remote, branch, err)
+	}
+	if err := repo.Repo.Push(ctx); !errors.Is(err, ErrPushRejected) {
+		t.Fatalf("expected the diverged push to be rejected, got %v", err)
+	}
+
+	// The remote moved since our last look, so a lease on what we saw fails
+	expected, err := repo.Repo.RemoteBranchHead(ctx, remote, branch)
+	if err != nil || expected != pushed {
+		t.Fatalf("RemoteBranchHead() = %q, %v; want %s", expected, err, pushed)
+	}
+	lease := PushOptions{Remote: remote, Branch: branch, ForceWithLease: true, Lease: expected}
+	if err := repo.Repo.PushWith(ctx, lease); !errors.Is(err, ErrPushRejected) {
+		t.Fatalf("expected a stale lease to be rejected, got %v", err)
+	}
+
+	// After fetching, the lease holds and the push overwrites their commit
+	if err := repo.Repo.Fetch(ctx, FetchOptions{}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	lease.Lease, _ = repo.Repo.RemoteBranchHead(ctx, remote, branch)
+	if lease.Lease == pushed {
+		t.Fatal("fetch should have moved the remote-tracking branch")
+	}
+	if err := repo.Repo.PushWith(ctx, lease); err != nil {
+		t.Fatalf("PushWith failed: %v", err)
+	}
+	if got, want := remoteHead(t, remoteDir, branch), strings.TrimSpace(repo.Git("rev-parse", "HEAD")); got != want {
+		t.Errorf("remote is at %s, want %s", got, want)
+	}
+}
+
+func TestPushForceIfIncludes(t *testing.T) {
+	repo, _, _ := setupDivergedRemote(t)
+	defer repo.Cleanup()
+	ctx := t.Context()
+	_, branch, _ := repo.Repo.Upstream(ctx)
+
+	// A background fetch makes a plain lease pass without our having seen
+	// their commit; --force-if-includes catches that
+	if err := repo.Repo.Fetch(ctx, FetchOptions{}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	opts := PushOptions{Remote: "origin", Branch: branch, ForceWithLease: true, ForceIfIncludes: true}
+	if err := repo.Repo.PushWith(ctx, opts); !errors.Is(err, ErrPushRejected) {
+		t.Fatalf("expected --force-if-includes to reject the push, got %v", err)
+	}
+
+	// Once their commit is integrated the push goes through
+	repo.Git("merge", "--no-edit", "origin/"+branch)
+	if err := repo.Repo.PushWith(ctx, opts); err != nil {
+		t.Fatalf("PushWith failed: %v", err)
+	}
+}
+
+func TestPushToOtherBranchWithTags(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+	repo.CommitFile("file.txt", "content\n", "initial")
+	remoteDir := repo.SetupRemote()
+	defer os.RemoveAll(remoteDir)
+	repo.Git("tag", "v1.0")
+
+	opts := PushOptions{Remote: "origin", Branch: "review/topic", SetUpstream: true, Tags: true}
+	if err := repo.Repo.PushWith(t.Context(), opts); err != nil {
+		t.Fatalf("PushWith failed: %v", err)
+	}
+	head := strings.TrimSpace(repo.Git("rev-parse", "HEAD"))
+	if got := remoteHead(t, remoteDir, "refs/heads/review/topic"); got != head {
+		t.Errorf("review/topic is at %s, want %s", got, head)
+	}
+	if got := remoteHead(t, remoteDir, "refs/tags/v1.0"); got != head {
+		t.Errorf("v1.0 is at %s, want %s", got, head)
+	}
+	if remote, branch, _ := repo.Repo.Upstream(t.Context()); remote != "origin" || branch != "review/topic" {
+		t.Errorf("Upstream() = %q, %q; want origin, review/topic", remote, branch)
+	}
+}
+
+func TestPushNoVerifyAndOptions(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+	repo.CommitFile("file.txt", "content\n", "initial")
+	remoteDir := repo.SetupRemote()
+	defer os.RemoveAll(remoteDir)
+
+	// A pre-push hook that refuses everything
+	repo.WriteFile(".git/hooks/pre-push", "#!/bin/sh\necho 'pre-push: no' >&2\nexit 1\n")
+	if err := os.Chmod(filepath.Join(repo.Dir, ".git", "hooks", "pre-push"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The remote records the push options its hooks receive
+	optionsFile := filepath.Join(t.TempDir(), "options")
+	hook := "#!/bin/sh\ni=0\nwhile [ $i -lt \"${GIT_PUSH_OPTION_COUNT:-0}\" ]; do\n" +
+		"\teval echo \"\\$GIT_PUSH_OPTION_$i\" >> " + optionsFile + "\n\ti=$((i+1))\ndone\n"
+	if err := os.WriteFile(filepath.Join(remoteDir, "hooks", "pre-receive"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := exec.Command("git", "-C", remoteDir, "config", "receive.advertisePushOptions", "true").CombinedOutput(); err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, output)
+	}
+
+	opts := PushOptions{Remote: "origin", Branch: "main", Options: []string{"ci.skip", "reviewer=jane"}}
+	if err := repo.Repo.PushWith(t.Context(), opts); err == nil {
+		t.Fatal("expected the pre-push hook to stop the push")
+	}
+	opts.NoVerify = true
+	if err := repo.Repo.PushWith(t.Context(), opts); err != nil {
+		t.Fatalf("PushWith --no-verify failed: %v", err)
+	}
+	received, err := os.ReadFile(optionsFile)
+	if err != nil {
+		t.Fatalf("the remote hook saw no push options: %v", err)
+	}
+	if got := strings.Fields(string(received)); !slices.Equal(got, opts.Options) {
+		t.Errorf("remote received options %q, want %q", got, opts.Options)
+	}
+}
@@ -0,0 +1,197 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// RebaseAction is what an interactive rebase does with one commit
+type RebaseAction int
+
+const (
+	RebasePick RebaseAction = iota
+	RebaseReword
+	RebaseEdit
+	RebaseSquash
+	RebaseFixup
+	RebaseDrop
+)
+
+func (a RebaseAction) String() string {
+	switch a {
+	case RebaseReword:
+		return "reword"
+	case RebaseEdit:
+		return "edit"
+	case RebaseSquash:
+		return "squash"
+	case RebaseFixup:
+		return "fixup"
+	case RebaseDrop:
+		return "drop"
+	default:
+		return "pick"
+	}
+}
+
+// RebaseStep is one line of an interactive rebase todo list
+type RebaseStep struct {
+	Action  RebaseAction
+	Commit  Commit
+	Message string // new commit message for RebaseReword
+}
+
+// rebaseDir holds the todo list and reword messages handed to git. It
+// outlives a stopped rebase, since the todo refers to the message files.
+const rebaseDir = "go-on-git-rebase"
+
+// GetRebaseTodo returns the commits in base..HEAD as the todo list for
+// rebasing onto base: every commit picked, oldest first. Merge commits
+// are left out, as git rebase --interactive does.
+func (r *Repo) GetRebaseTodo(ctx context.Context, base string) ([]RebaseStep, error) {
+	output, err := r.Run(ctx, "log", "--reverse", "--topo-order", "--no-merges", logFormat, base+"..HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var steps []RebaseStep
+	for _, c := range parseLog(output) {
+		steps = append(steps, RebaseStep{Action: RebasePick, Commit: c})
+	}
+	return steps, nil
+}
+
+// ValidateRebaseTodo checks a todo list for mistakes git would only
+// report after starting the rebase
+func ValidateRebaseTodo(steps []RebaseStep) error {
+	picked := false
+	for _, step := range steps {
+		switch step.Action {
+		case RebaseDrop:
+			continue
+		case RebaseSquash, RebaseFixup:
+			if !picked {
+				return fmt.Errorf("cannot %s %s without a previous commit", step.Action, step.Commit.ShortHash())
+			}
+		case RebaseReword:
+			if strings.TrimSpace(step.Message) == "" {
+				return fmt.Errorf("empty commit message for %s", step.Commit.ShortHash())
+			}
+		}
+		picked = true
+	}
+	if !picked {
+		return fmt.Errorf("nothing to rebase")
+	}
+	return nil
+}
+
+// RunRebase rebases HEAD onto base following the todo list, which is
+// handed to git through GIT_SEQUENCE_EDITOR. Local changes are stashed
+// for the duration. Reword messages go through the commit-msg hooks
+// first, as Commit's do. A rebase that stops for an edit step or a
+// conflict is not an error: it is left in progress, as GetOperationState
+// reports, to be continued or aborted.
+func (r *Repo) RunRebase(ctx context.Context, base string, steps []RebaseStep) error {
+	if err := ValidateRebaseTodo(steps); err != nil {
+		return err
+	}
+	for _, step := range steps {
+		if step.Action != RebaseReword {
+			continue
+		}
+		if err := r.CheckCommitMessage(ctx, step.Message); err != nil {
+			return err
+		}
+	}
+	if state := r.GetOperationState(); state.InProgress() {
+		return fmt.Errorf("finish the %s in progress first", state.Op)
+	}
+
+	dir := filepath.Join(r.gitDir, rebaseDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	todo, err := writeRebaseTodo(dir, steps, r.signArgs())
+	if err != nil {
+		return err
+	}
+
+	// The sequence editor replaces git's todo with ours
+	err = r.runInteractiveRebase(ctx, "cp "+shellQuote(todo), base)
+	if !r.GetOperationState().InProgress() {
+		os.RemoveAll(dir)
+	}
+	return err
+}
+
+// Autosquash rebases the commits after target, folding the "fixup!" and
+// "squash!" commits among them into the commits they name. Like
+// RunRebase, a rebase that stops on a conflict is left in progress.
+func (r *Repo) Autosquash(ctx context.Context, target Commit) error {
+	if state := r.GetOperationState(); state.InProgress() {
+		return fmt.Errorf("finish the %s in progress first", state.Op)
+	}
+	base := target.Hash + "^"
+	if len(target.Parents) == 0 {
+		base = "--root"
+	}
+	// git's own todo is already the autosquashed one
+	return r.runInteractiveRebase(ctx, "true", "--autosquash", base)
+}
+
+// runInteractiveRebase runs git rebase --interactive with sequenceEditor
+// preparing the todo. GIT_EDITOR keeps the combined message of a squash
+// without prompting. Stopping for an edit or a conflict is not an error.
+func (r *Repo) runInteractiveRebase(ctx context.Context, sequenceEditor string, args ...string) error {
+	env := []string{
+		"GIT_SEQUENCE_EDITOR=" + sequenceEditor,
+		"GIT_EDITOR=true",
+	}
+	args = slices.Concat([]string{"rebase", "--interactive", "--autostash"}, r.signArgs(), args)
+	result, err := r.executeWithEnv(ctx, env, "", args...)
+	if err != nil && !r.GetOperationState().InProgress() {
+		return newError(args, result, err)
+	}
+	return nil
+}
+
+// writeRebaseTodo writes the todo list, and a message file for each
+// reword, into dir and returns the todo's path. A reword becomes a pick
+// followed by an amend with the new message and the sign flags, so the
+// message is applied even if the pick stops on a conflict first.
+func writeRebaseTodo(dir string, steps []RebaseStep, sign []string) (string, error) {
+	var sb strings.Builder
+	for i, step := range steps {
+		action := step.Action
+		if action == RebaseReword {
+			action = RebasePick
+		}
+		fmt.Fprintf(&sb, "%s %s %s\n", action, step.Commit.Hash, step.Commit.Subject)
+		if step.Action != RebaseReword {
+			continue
+		}
+		path := filepath.Join(dir, fmt.Sprintf("message-%d", i))
+		if err := os.WriteFile(path, []byte(step.Message), 0o644); err != nil {
+			return "", err
+		}
+		amend := slices.Concat([]string{"git", "commit", "--amend", "--allow-empty", "--cleanup=strip"}, sign)
+		fmt.Fprintf(&sb, "exec %s --file=%s\n", strings.Join(amend, " "), shellQuote(path))
+	}
+	path := filepath.Join(dir, "todo")
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// shellQuote quotes s as a single word for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,256 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// rebaseRepo returns a repo with three commits on top of a base commit,
+// and the base's hash
+func rebaseRepo(t *testing.T) (*TestRepo, string) {
+	t.Helper()
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	base := strings.TrimSpace(repo.Git("rev-parse", "HEAD"))
+	repo.CommitFile("a.txt", "a\n", "add a")
+	repo.CommitFile("b.txt", "b\n", "add b")
+	repo.CommitFile("c.txt", "c\n", "add c")
+	return repo, base
+}
+
+// subjects returns the subjects of base..HEAD, oldest first
+func subjects(repo *TestRepo, base string) string {
+	return strings.TrimSpace(repo.Git("log", "--reverse", "--format=%s", base+"..HEAD"))
+}
+
+func TestGetRebaseTodo(t *testing.T) {
+	repo, base := rebaseRepo(t)
+
+	steps, err := repo.Repo.GetRebaseTodo(t.Context(), base)
+	if err != nil {
+		t.Fatalf("GetRebaseTodo failed: %v", err)
+	}
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+	for i, want := range []string{"add a", "add b", "add c"} {
+		if steps[i].Commit.Subject != want || steps[i].Action != RebasePick {
+			t.Errorf("step %d = %s %q, want pick %q", i, steps[i].Action, steps[i].Commit.Subject, want)
+		}
+	}
+}
+
+func TestRunRebaseReorderDropFixup(t *testing.T) {
+	repo, base := rebaseRepo(t)
+	steps, err := repo.Repo.GetRebaseTodo(t.Context(), base)
+	if err != nil {
+		t.Fatalf("GetRebaseTodo failed: %v", err)
+	}
+
+	// Move c to the front and drop b
+	a, b, c := steps[0], steps[1], steps[2]
+	b.Action = RebaseDrop
+	plan := []RebaseStep{c, a, b}
+	if err := repo.Repo.RunRebase(t.Context(), base, plan); err != nil {
+		t.Fatalf("RunRebase failed: %v", err)
+	}
+	if got := subjects(repo, base); got != "add c\nadd a" {
+		t.Errorf("subjects = %q, want c then a", got)
+	}
+	if repo.FileExists("b.txt") {
+		t.Error("dropped commit's file should be gone")
+	}
+
+	steps, _ = repo.Repo.GetRebaseTodo(t.Context(), base)
+	steps[1].Action = RebaseFixup
+	if err := repo.Repo.RunRebase(t.Context(), base, steps); err != nil {
+		t.Fatalf("RunRebase failed: %v", err)
+	}
+	if got := subjects(repo, base); got != "add c" {
+		t.Errorf("subjects = %q, want a fixed up into c", got)
+	}
+	if !repo.FileExists("a.txt") || !repo.FileExists("c.txt") {
+		t.Error("fixup should keep both commits' changes")
+	}
+}
+
+func TestRunRebaseReword(t *testing.T) {
+	repo, base := rebaseRepo(t)
+	steps, _ := repo.Repo.GetRebaseTodo(t.Context(), base)
+
+	steps[1].Action = RebaseReword
+	steps[1].Message = "Add b, reworded\n\nWith a body that isn't a 'one-liner'.\n"
+	if err := repo.Repo.RunRebase(t.Context(), base, steps); err != nil {
+		t.Fatalf("RunRebase failed: %v", err)
+	}
+	if got := subjects(repo, base); got != "add a\nAdd b, reworded\nadd c" {
+		t.Errorf("subjects = %q", got)
+	}
+	body := repo.Git("log", "-1", "--format=%b", "HEAD~1")
+	if !strings.Contains(body, "isn't a 'one-liner'") {
+		t.Errorf("reworded body = %q", body)
+	}
+	if repo.Repo.gitPathExists(rebaseDir) {
+		t.Error("a finished rebase should clean up its todo files")
+	}
+}
+
+func TestRunRebaseRewordHooks(t *testing.T) {
+	repo, base := rebaseRepo(t)
+	steps, _ := repo.Repo.GetRebaseTodo(t.Context(), base)
+	head := strings.TrimSpace(repo.Git("rev-parse", "HEAD"))
+
+	repo.Repo.AddCommitMsgHook(func(_ context.Context, message string) error {
+		if strings.HasPrefix(message, "WIP") {
+			return errors.New("no WIP commits")
+		}
+		return nil
+	})
+	steps[1].Action = RebaseReword
+	steps[1].Message = "WIP b\n"
+	if err := repo.Repo.RunRebase(t.Context(), base, steps); err == nil || err.Error() != "no WIP commits" {
+		t.Fatalf("expected the commit-msg hook to refuse the reword, got %v", err)
+	}
+	if got := strings.TrimSpace(repo.Git("rev-parse", "HEAD")); got != head || repo.Repo.GetOperationState().InProgress() {
+		t.Fatal("a refused reword should not start the rebase")
+	}
+
+	// git's own commit-msg hook runs on the new message too
+	repo.WriteFile(".git/hooks/commit-msg", "#!/bin/sh\nprintf '\\nReviewed-by: hook\\n' >> \"$1\"\n")
+	if err := os.Chmod(filepath.Join(repo.Dir, ".git", "hooks", "commit-msg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	steps[1].Message = "Add b, reworded\n"
+	if err := repo.Repo.RunRebase(t.Context(), base, steps); err != nil {
+		t.Fatalf("RunRebase failed: %v", err)
+	}
+	if body := repo.Git("log", "-1", "--format=%b", "HEAD~1"); !strings.Contains(body, "Reviewed-by: hook") {
+		t.Errorf("reworded body = %q, want the hook's trailer", body)
+	}
+}
+
+func TestRunRebaseEditStops(t *testing.T) {
+	repo, base := rebaseRepo(t)
+	steps, _ := repo.Repo.GetRebaseTodo(t.Context(), base)
+
+	steps[0].Action = RebaseEdit
+	if err := repo.Repo.RunRebase(t.Context(), base, steps); err != nil {
+		t.Fatalf("RunRebase failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.Op != OpRebase {
+		t.Fatalf("expected rebase to stop in progress, got %q", state.Op)
+	}
+
+	if err := repo.Repo.ContinueOperation(t.Context(), OpRebase); err != nil {
+		t.Fatalf("ContinueOperation failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.InProgress() {
+		t.Errorf("expected rebase to finish, got %s", state.Op)
+	}
+}
+
+func TestRunRebaseConflict(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.InitialCommit()
+	base := strings.TrimSpace(repo.Git("rev-parse", "HEAD"))
+	repo.CommitFile("file.txt", "one\n", "first")
+	repo.CommitFile("file.txt", "two\n", "second")
+
+	steps, _ := repo.Repo.GetRebaseTodo(t.Context(), base)
+	steps[0], steps[1] = steps[1], steps[0]
+	steps[0].Action = RebaseReword
+	steps[0].Message = "second, reworded"
+	if err := repo.Repo.RunRebase(t.Context(), base, steps); err != nil {
+		t.Fatalf("RunRebase failed: %v", err)
+	}
+	if state := repo.Repo.GetOperationState(); state.Op != OpRebase {
+		t.Fatalf("expected rebase to stop on the conflict, got %q", state.Op)
+	}
+
+	// Another rebase can't start until this one is finished
+	if err := repo.Repo.RunRebase(t.Context(), base, steps); err == nil {
+		t.Error("expected an error while a rebase is in progress")
+	}
+
+	// The reword still applies once the conflict is resolved. Picking
+	// "first" after it conflicts in turn, which leaves it at HEAD.
+	repo.WriteFile("file.txt", "two\n")
+	repo.Git("add", "file.txt")
+	repo.Repo.ContinueOperation(t.Context(), OpRebase)
+	if got := repo.Git("log", "-1", "--format=%s", "HEAD"); strings.TrimSpace(got) != "second, reworded" {
+		t.Errorf("subject = %q, want the reworded message", got)
+	}
+
+	if err := repo.Repo.AbortOperation(t.Context(), OpRebase); err != nil {
+		t.Fatalf("AbortOperation failed: %v", err)
+	}
+	if got := subjects(repo, base); got != "first\nsecond" {
+		t.Errorf("abort should restore the original history, got %q", got)
+	}
+}
+
+func TestValidateRebaseTodo(t *testing.T) {
+	pick := RebaseStep{Action: RebasePick, Commit: Commit{Hash: "aaaaaaaaaa"}}
+	squash := RebaseStep{Action: RebaseSquash, Commit: Commit{Hash: "bbbbbbbbbb"}}
+	drop := RebaseStep{Action: RebaseDrop, Commit: Commit{Hash: "cccccccccc"}}
+	reword := RebaseStep{Action: RebaseReword, Commit: Commit{Hash: "dddddddddd"}, Message: "  \n"}
+
+	tests := []struct {
+		name    string
+		steps   []RebaseStep
+		wantErr string
+	}{
+		{"valid", []RebaseStep{pick, squash, drop}, ""},
+		{"empty", nil, "nothing to rebase"},
+		{"all dropped", []RebaseStep{drop}, "nothing to rebase"},
+		{"squash first", []RebaseStep{drop, squash, pick}, "cannot squash bbbbbbb without a previous commit"},
+		{"empty reword", []RebaseStep{reword}, "empty commit message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRebaseTodo(tt.steps)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAutosquash(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("file.txt", "one\n", "Add file")
+	repo.CommitFile("other.txt", "other\n", "Add other")
+	repo.WriteFile("file.txt", "two\n")
+	repo.Git("add", "file.txt")
+	repo.Git("commit", "--fixup=HEAD~1")
+	// Uncommitted changes are stashed and restored around the rebase
+	repo.WriteFile("other.txt", "dirty\n")
+
+	commits, err := repo.Repo.GetLog(t.Context(), LogFilter{}, 2, 1)
+	if err != nil || len(commits) != 1 {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+	// The root commit is rebased with --root
+	if err := repo.Repo.Autosquash(t.Context(), commits[0]); err != nil {
+		t.Fatalf("Autosquash failed: %v", err)
+	}
+	if got := repo.Git("log", "--format=%s"); got != "Add other\nAdd file\n" {
+		t.Errorf("log = %q, want the fixup folded into its target", got)
+	}
+	if got := repo.Git("show", "HEAD~1:file.txt"); got != "two\n" {
+		t.Errorf("target should include the fixup, got %q", got)
+	}
+	if got := repo.ReadFile("other.txt"); got != "dirty\n" {
+		t.Errorf("local changes should be restored, got %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+package git
+
+import "context"
+
+// Signing says whether the commits made through a Repo are signed
+type Signing int
+
+const (
+	SignDefault Signing = iota // as commit.gpgsign says
+	SignAlways                 // -S, with the key and format from user.signingkey and gpg.format
+	SignNever                  // --no-gpg-sign
+)
+
+// SetSigning sets whether Commit, AmendCommit, FixupCommit and rebases sign
+func (r *Repo) SetSigning(signing Signing) {
+	r.settings.Lock()
+	defer r.settings.Unlock()
+	r.signing = signing
+}
+
+// SignArgs returns the git commit flags for the signing mode, for commits
+// made by running git directly, such as with the user's editor
+func (r *Repo) SignArgs() []string {
+	return r.signArgs()
+}
+
+// signArgs returns the git commit flags for the signing mode
+func (r *Repo) signArgs() []string {
+	r.settings.Lock()
+	defer r.settings.Unlock()
+	switch r.signing {
+	case SignAlways:
+		return []string{"-S"}
+	case SignNever:
+		return []string{"--no-gpg-sign"}
+	}
+	return nil
+}
+
+// SigningFormat returns the kind of key new commits are signed with
+// ("openpgp", "ssh" or "x509"), or "" if they won't be signed
+func (r *Repo) SigningFormat(ctx context.Context) (string, error) {
+	r.settings.Lock()
+	signing := r.signing
+	r.settings.Unlock()
+	switch signing {
+	case SignNever:
+		return "", nil
+	case SignDefault:
+		sign, err := r.configBool(ctx, "commit.gpgsign")
+		if err != nil || !sign {
+			return "", err
+		}
+	}
+	format, err := r.Config(ctx, "gpg.format")
+	if format == "" {
+		format = "openpgp"
+	}
+	return format, err
+}
+
+// SignatureStatus is git's verdict on a commit's signature, as %G? prints it
+type SignatureStatus byte
+
+const (
+	SignatureNone       SignatureStatus = 'N'
+	SignatureGood       SignatureStatus = 'G'
+	SignatureUnknown    SignatureStatus = 'U' // good, but the key's validity is unknown
+	SignatureExpired    SignatureStatus = 'X' // good, but the signature has expired
+	SignatureExpiredKey SignatureStatus = 'Y' // good, made by a key that has since expired
+	SignatureRevokedKey SignatureStatus = 'R' // good, made by a key that has since been revoked
+	SignatureUnchecked  SignatureStatus = 'E' // can't be checked, e.g. the key is missing
+	SignatureBad        SignatureStatus = 'B'
+)
+
+// Signature describes a commit's signature. Verifying an SSH signature
+// needs gpg.ssh.allowedSignersFile; without it git reports none.
+type Signature struct {
+	Status SignatureStatus
+	Signer string // %GS
+	Key    string // %GK
+}
+
+// Signed reports whether the commit carries a signature git could see
+func (s Signature) Signed() bool {
+	return s.Status != SignatureNone && s.Status != 0
+}
+
+// Verified reports whether the signature is good and made by a trusted key
+func (s Signature) Verified() bool {
+	return s.Status == SignatureGood
+}
+
+// parseSignatureStatus parses a %G? field
+func parseSignatureStatus(s string) SignatureStatus {
+	if s == "" {
+		return SignatureNone
+	}
+	return SignatureStatus(s[0])
+}
@@ -0,0 +1,187 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupSSHSigning creates a throwaway SSH key and configures the repo to
+// sign with it and to trust it when verifying
+func setupSSHSigning(t *testing.T, repo *TestRepo) {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	key := filepath.Join(t.TempDir(), "signing")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "test", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen failed: %v\n%s", err, output)
+	}
+	public, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatalf("reading public key: %v", err)
+	}
+	repo.WriteFile(".git/allowed_signers", "test@example.com "+string(public))
+	repo.Git("config", "gpg.format", "ssh")
+	repo.Git("config", "user.signingkey", key+".pub")
+	repo.Git("config", "gpg.ssh.allowedSignersFile", filepath.Join(repo.Dir, ".git", "allowed_signers"))
+}
+
+func TestSignedCommit(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+	setupSSHSigning(t, repo)
+	repo.Repo.SetSigning(SignAlways)
+
+	format, err := repo.Repo.SigningFormat(t.Context())
+	if err != nil || format != "ssh" {
+		t.Fatalf("SigningFormat() = %q, %v; want ssh", format, err)
+	}
+
+	repo.WriteFile("file.txt", "content\n")
+	repo.Git("add", "file.txt")
+	if err := repo.Repo.Commit(t.Context(), "Signed"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := repo.Repo.AmendCommit(t.Context(), ""); err != nil {
+		t.Fatalf("AmendCommit failed: %v", err)
+	}
+
+	commits, err := repo.Repo.GetSignedLog(t.Context(), LogFilter{}, 0, 10)
+	if err != nil {
+		t.Fatalf("GetSignedLog failed: %v", err)
+	}
+	sig := commits[0].Signature
+	if !sig.Signed() || !sig.Verified() {
+		t.Errorf("signature = %+v, want a verified one", sig)
+	}
+	if sig.Signer != "test@example.com" || !strings.HasPrefix(sig.Key, "SHA256:") {
+		t.Errorf("signer = %q, key = %q", sig.Signer, sig.Key)
+	}
+
+	// GetLog leaves signatures unchecked
+	commits, err = repo.Repo.GetLog(t.Context(), LogFilter{}, 0, 10)
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+	if sig := commits[0].Signature; sig.Status != SignatureNone || sig.Signer != "" {
+		t.Errorf("GetLog signature = %+v, want none", sig)
+	}
+}
+
+func TestSigningModes(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+	setupSSHSigning(t, repo)
+
+	if format, _ := repo.Repo.SigningFormat(t.Context()); format != "" {
+		t.Errorf("without commit.gpgsign SigningFormat() = %q, want none", format)
+	}
+	repo.Git("config", "commit.gpgsign", "true")
+	if format, _ := repo.Repo.SigningFormat(t.Context()); format != "ssh" {
+		t.Errorf("with commit.gpgsign SigningFormat() = %q, want ssh", format)
+	}
+
+	repo.CommitFile("a.txt", "a\n", "Signed by config")
+	repo.Repo.SetSigning(SignNever)
+	if format, _ := repo.Repo.SigningFormat(t.Context()); format != "" {
+		t.Errorf("with SignNever SigningFormat() = %q, want none", format)
+	}
+	repo.WriteFile("b.txt", "b\n")
+	repo.Git("add", "b.txt")
+	if err := repo.Repo.Commit(t.Context(), "Unsigned"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	commits, err := repo.Repo.GetSignedLog(t.Context(), LogFilter{}, 0, 10)
+	if err != nil {
+		t.Fatalf("GetSignedLog failed: %v", err)
+	}
+	if commits[0].Signature.Signed() {
+		t.Errorf("SignNever commit has signature %+v", commits[0].Signature)
+	}
+	if !commits[1].Signature.Verified() {
+		t.Errorf("commit.gpgsign commit has signature %+v", commits[1].Signature)
+	}
+}
+
+func TestRebaseSigns(t *testing.T) {
+	repo, base := rebaseRepo(t)
+	defer repo.Cleanup()
+	setupSSHSigning(t, repo)
+	repo.Repo.SetSigning(SignAlways)
+
+	steps, _ := repo.Repo.GetRebaseTodo(t.Context(), base)
+	steps[0].Action = RebaseDrop
+	steps[1].Action = RebaseReword
+	steps[1].Message = "Add b, reworded\n"
+	if err := repo.Repo.RunRebase(t.Context(), base, steps); err != nil {
+		t.Fatalf("RunRebase failed: %v", err)
+	}
+	commits, err := repo.Repo.GetSignedLog(t.Context(), LogFilter{}, 0, 2)
+	if err != nil {
+		t.Fatalf("GetSignedLog failed: %v", err)
+	}
+	for _, c := range commits {
+		if !c.Signature.Verified() {
+			t.Errorf("%q has signature %+v, want a verified one", c.Subject, c.Signature)
+		}
+	}
+}
+
+func TestSettingsDontWaitForCommands(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	// Hold the command lock, as a long push does
+	repo.Repo.lock <- struct{}{}
+	defer func() { <-repo.Repo.lock }()
+
+	done := make(chan error)
+	go func() {
+		repo.Repo.SetSigning(SignNever)
+		repo.Repo.AddCommitMsgHook(func(context.Context, string) error { return nil })
+		if got := repo.Repo.SignArgs(); !slices.Equal(got, []string{"--no-gpg-sign"}) {
+			done <- fmt.Errorf("SignArgs() = %q", got)
+			return
+		}
+		done <- repo.Repo.CheckCommitMessage(t.Context(), "message")
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("signing and hook settings waited for the command lock")
+	}
+}
+
+func TestSigningFailure(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+	repo.Git("config", "gpg.format", "ssh")
+	repo.Git("config", "user.signingkey", filepath.Join(repo.Dir, "missing.pub"))
+	repo.Repo.SetSigning(SignAlways)
+
+	repo.WriteFile("file.txt", "content\n")
+	repo.Git("add", "file.txt")
+	err := repo.Repo.Commit(t.Context(), "Signed")
+	if !errors.Is(err, ErrSigningFailed) {
+		t.Fatalf("expected ErrSigningFailed, got %v", err)
+	}
+	var gitErr *Error
+	if !errors.As(err, &gitErr) || strings.HasPrefix(gitErr.Message(), "error:") || gitErr.Message() == "" {
+		t.Errorf("Message() = %q, want git's complaint without its prefix", gitErr.Message())
+	}
+	if _, err := repo.GitAllowFailure("rev-parse", "HEAD"); err == nil {
+		t.Error("a failed signature should not leave a commit")
+	}
+}
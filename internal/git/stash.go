@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -14,10 +15,10 @@ type Stash struct {
 }
 
 // GetStashes returns all stash entries
-func GetStashes() ([]Stash, error) {
+func (r *Repo) GetStashes(ctx context.Context) ([]Stash, error) {
 	// Format: stash@{0}: On branch_name: message
 	// or: stash@{0}: WIP on branch_name: hash message
-	output, err := Run("stash", "list", "--format=%gd|%s")
+	output, err := r.Run(ctx, "stash", "list", "--format=%gd|%s")
 	if err != nil {
 		return nil, err
 	}
@@ -81,15 +82,15 @@ func GetStashes() ([]Stash, error) {
 }
 
 // GetStashDiff returns the diff for a specific stash
-func GetStashDiff(index int) (*CombinedDiffResult, error) {
+func (r *Repo) GetStashDiff(ctx context.Context, index int) (*CombinedDiffResult, error) {
 	stashRef := fmt.Sprintf("stash@{%d}", index)
-	output, err := Run("stash", "show", "-p", stashRef)
+	output, err := r.Run(ctx, "stash", "show", "-p", stashRef)
 	if err != nil {
 		return nil, err
 	}
 
 	// Parse as unstaged diff (stash shows what would be applied)
-	diff := parseDiff(output)
+	diff := r.parseDiff(output)
 
 	return &CombinedDiffResult{
 		StagedDiff:   &DiffResult{},
@@ -98,22 +99,22 @@ func GetStashDiff(index int) (*CombinedDiffResult, error) {
 }
 
 // ApplyStash applies a stash without removing it
-func ApplyStash(index int) error {
+func (r *Repo) ApplyStash(ctx context.Context, index int) error {
 	stashRef := fmt.Sprintf("stash@{%d}", index)
-	_, err := Run("stash", "apply", stashRef)
+	_, err := r.Run(ctx, "stash", "apply", stashRef)
 	return err
 }
 
 // PopStash applies and removes a stash
-func PopStash(index int) error {
+func (r *Repo) PopStash(ctx context.Context, index int) error {
 	stashRef := fmt.Sprintf("stash@{%d}", index)
-	_, err := Run("stash", "pop", stashRef)
+	_, err := r.Run(ctx, "stash", "pop", stashRef)
 	return err
 }
 
 // DropStash removes a stash without applying
-func DropStash(index int) error {
+func (r *Repo) DropStash(ctx context.Context, index int) error {
 	stashRef := fmt.Sprintf("stash@{%d}", index)
-	_, err := Run("stash", "drop", stashRef)
+	_, err := r.Run(ctx, "stash", "drop", stashRef)
 	return err
 }
@@ -11,7 +11,7 @@ func TestGetStashes_NoStashes(t *testing.T) {
 
 	repo.InitialCommit()
 
-	stashes, err := GetStashes()
+	stashes, err := repo.Repo.GetStashes(t.Context())
 	if err != nil {
 		t.Fatalf("GetStashes failed: %v", err)
 	}
@@ -29,7 +29,7 @@ func TestGetStashes_SingleStash(t *testing.T) {
 	repo.WriteFile("test.txt", "modified")
 	repo.Git("stash", "push", "-m", "Test stash message")
 
-	stashes, err := GetStashes()
+	stashes, err := repo.Repo.GetStashes(t.Context())
 	if err != nil {
 		t.Fatalf("GetStashes failed: %v", err)
 	}
@@ -64,7 +64,7 @@ func TestGetStashes_MultipleStashes(t *testing.T) {
 	repo.WriteFile("test.txt", "change3")
 	repo.Git("stash", "push", "-m", "Third stash")
 
-	stashes, err := GetStashes()
+	stashes, err := repo.Repo.GetStashes(t.Context())
 	if err != nil {
 		t.Fatalf("GetStashes failed: %v", err)
 	}
@@ -97,7 +97,7 @@ func TestGetStashes_Branch(t *testing.T) {
 	repo.WriteFile("test.txt", "modified")
 	repo.Git("stash", "push", "-m", "Stash on master")
 
-	stashes, err := GetStashes()
+	stashes, err := repo.Repo.GetStashes(t.Context())
 	if err != nil {
 		t.Fatalf("GetStashes failed: %v", err)
 	}
@@ -122,7 +122,7 @@ func TestGetStashes_WIPStash(t *testing.T) {
 	// Stash without message creates a WIP stash
 	repo.Git("stash", "push")
 
-	stashes, err := GetStashes()
+	stashes, err := repo.Repo.GetStashes(t.Context())
 	if err != nil {
 		t.Fatalf("GetStashes failed: %v", err)
 	}
@@ -145,7 +145,7 @@ func TestGetStashDiff(t *testing.T) {
 	repo.WriteFile("test.txt", "modified\n")
 	repo.Git("stash", "push", "-m", "Test stash")
 
-	diff, err := GetStashDiff(0)
+	diff, err := repo.Repo.GetStashDiff(t.Context(), 0)
 	if err != nil {
 		t.Fatalf("GetStashDiff failed: %v", err)
 	}
@@ -171,7 +171,7 @@ func TestGetStashDiff_MultipleFiles(t *testing.T) {
 	repo.WriteFile("file2.txt", "modified2\n")
 	repo.Git("stash", "push", "-m", "Multi-file stash")
 
-	diff, err := GetStashDiff(0)
+	diff, err := repo.Repo.GetStashDiff(t.Context(), 0)
 	if err != nil {
 		t.Fatalf("GetStashDiff failed: %v", err)
 	}
@@ -195,7 +195,7 @@ func TestApplyStash(t *testing.T) {
 		t.Fatalf("expected file to be restored to 'original', got %q", content)
 	}
 
-	err := ApplyStash(0)
+	err := repo.Repo.ApplyStash(t.Context(), 0)
 	if err != nil {
 		t.Fatalf("ApplyStash failed: %v", err)
 	}
@@ -207,7 +207,7 @@ func TestApplyStash(t *testing.T) {
 	}
 
 	// Verify stash is still present
-	stashes, _ := GetStashes()
+	stashes, _ := repo.Repo.GetStashes(t.Context())
 	if len(stashes) != 1 {
 		t.Errorf("expected stash to still exist after apply, got %d stashes", len(stashes))
 	}
@@ -227,7 +227,7 @@ func TestApplyStash_SpecificIndex(t *testing.T) {
 	repo.Git("stash", "push", "-m", "Second")
 
 	// Apply stash@{1} (the first/older stash)
-	err := ApplyStash(1)
+	err := repo.Repo.ApplyStash(t.Context(), 1)
 	if err != nil {
 		t.Fatalf("ApplyStash(1) failed: %v", err)
 	}
@@ -247,12 +247,12 @@ func TestPopStash(t *testing.T) {
 	repo.Git("stash", "push", "-m", "Test stash")
 
 	// Verify we have a stash
-	stashes, _ := GetStashes()
+	stashes, _ := repo.Repo.GetStashes(t.Context())
 	if len(stashes) != 1 {
 		t.Fatalf("expected 1 stash before pop, got %d", len(stashes))
 	}
 
-	err := PopStash(0)
+	err := repo.Repo.PopStash(t.Context(), 0)
 	if err != nil {
 		t.Fatalf("PopStash failed: %v", err)
 	}
@@ -264,7 +264,7 @@ func TestPopStash(t *testing.T) {
 	}
 
 	// Verify stash is removed
-	stashes, _ = GetStashes()
+	stashes, _ = repo.Repo.GetStashes(t.Context())
 	if len(stashes) != 0 {
 		t.Errorf("expected stash to be removed after pop, got %d stashes", len(stashes))
 	}
@@ -287,7 +287,7 @@ func TestPopStash_MiddleStash(t *testing.T) {
 	repo.Git("stash", "push", "-m", "Third")
 
 	// Pop the middle stash (index 1 = "Second")
-	err := PopStash(1)
+	err := repo.Repo.PopStash(t.Context(), 1)
 	if err != nil {
 		t.Fatalf("PopStash(1) failed: %v", err)
 	}
@@ -299,7 +299,7 @@ func TestPopStash_MiddleStash(t *testing.T) {
 	}
 
 	// Verify we have 2 stashes remaining
-	stashes, _ := GetStashes()
+	stashes, _ := repo.Repo.GetStashes(t.Context())
 	if len(stashes) != 2 {
 		t.Errorf("expected 2 stashes remaining, got %d", len(stashes))
 	}
@@ -314,18 +314,18 @@ func TestDropStash(t *testing.T) {
 	repo.Git("stash", "push", "-m", "Test stash")
 
 	// Verify we have a stash
-	stashes, _ := GetStashes()
+	stashes, _ := repo.Repo.GetStashes(t.Context())
 	if len(stashes) != 1 {
 		t.Fatalf("expected 1 stash before drop, got %d", len(stashes))
 	}
 
-	err := DropStash(0)
+	err := repo.Repo.DropStash(t.Context(), 0)
 	if err != nil {
 		t.Fatalf("DropStash failed: %v", err)
 	}
 
 	// Verify stash is removed
-	stashes, _ = GetStashes()
+	stashes, _ = repo.Repo.GetStashes(t.Context())
 	if len(stashes) != 0 {
 		t.Errorf("expected stash to be removed after drop, got %d stashes", len(stashes))
 	}
@@ -351,12 +351,12 @@ func TestDropStash_SpecificIndex(t *testing.T) {
 	repo.Git("stash", "push", "-m", "Second")
 
 	// Drop stash@{1} (the "First" stash)
-	err := DropStash(1)
+	err := repo.Repo.DropStash(t.Context(), 1)
 	if err != nil {
 		t.Fatalf("DropStash(1) failed: %v", err)
 	}
 
-	stashes, _ := GetStashes()
+	stashes, _ := repo.Repo.GetStashes(t.Context())
 	if len(stashes) != 1 {
 		t.Errorf("expected 1 stash remaining, got %d", len(stashes))
 	}
@@ -376,7 +376,7 @@ func TestApplyStash_InvalidIndex(t *testing.T) {
 	repo.Git("stash", "push", "-m", "Only stash")
 
 	// Try to apply a non-existent stash
-	err := ApplyStash(99)
+	err := repo.Repo.ApplyStash(t.Context(), 99)
 	if err == nil {
 		t.Error("expected error for invalid stash index")
 	}
@@ -390,7 +390,7 @@ func TestPopStash_InvalidIndex(t *testing.T) {
 	repo.WriteFile("test.txt", "stashed")
 	repo.Git("stash", "push", "-m", "Only stash")
 
-	err := PopStash(99)
+	err := repo.Repo.PopStash(t.Context(), 99)
 	if err == nil {
 		t.Error("expected error for invalid stash index")
 	}
@@ -404,7 +404,7 @@ func TestDropStash_InvalidIndex(t *testing.T) {
 	repo.WriteFile("test.txt", "stashed")
 	repo.Git("stash", "push", "-m", "Only stash")
 
-	err := DropStash(99)
+	err := repo.Repo.DropStash(t.Context(), 99)
 	if err == nil {
 		t.Error("expected error for invalid stash index")
 	}
@@ -421,7 +421,7 @@ func TestApplyStash_WithConflict(t *testing.T) {
 	// Make a conflicting change
 	repo.WriteFile("test.txt", "conflicting content")
 
-	err := ApplyStash(0)
+	err := repo.Repo.ApplyStash(t.Context(), 0)
 	// This may or may not produce an error depending on git's merge behavior
 	// Git may auto-merge or report a conflict
 	_ = err // We're just testing that it doesn't crash
@@ -436,7 +436,7 @@ func TestStash_OnDifferentBranch(t *testing.T) {
 	repo.WriteFile("test.txt", "feature changes")
 	repo.Git("stash", "push", "-m", "Feature stash")
 
-	stashes, _ := GetStashes()
+	stashes, _ := repo.Repo.GetStashes(t.Context())
 	if len(stashes) == 0 {
 		t.Fatal("expected at least one stash")
 	}
@@ -458,7 +458,7 @@ func TestApplyStash_OnDifferentBranch(t *testing.T) {
 	repo.Git("checkout", "-b", "other-branch")
 
 	// Apply stash on different branch
-	err := ApplyStash(0)
+	err := repo.Repo.ApplyStash(t.Context(), 0)
 	if err != nil {
 		t.Fatalf("ApplyStash on different branch failed: %v", err)
 	}
@@ -484,7 +484,7 @@ func TestStash_NewFile(t *testing.T) {
 	}
 
 	// Apply stash
-	ApplyStash(0)
+	repo.Repo.ApplyStash(t.Context(), 0)
 
 	// Verify new file is back
 	if !repo.FileExists("new-file.txt") {
@@ -503,7 +503,7 @@ func TestGetStashDiff_InvalidIndex(t *testing.T) {
 
 	repo.InitialCommit()
 
-	_, err := GetStashDiff(0)
+	_, err := repo.Repo.GetStashDiff(t.Context(), 0)
 	if err == nil {
 		t.Error("expected error for stash diff with no stashes")
 	}
@@ -519,7 +519,7 @@ func TestStash_MessageParsing(t *testing.T) {
 	// Test various message formats
 	repo.Git("stash", "push", "-m", "Message with: colons: in: it")
 
-	stashes, err := GetStashes()
+	stashes, err := repo.Repo.GetStashes(t.Context())
 	if err != nil {
 		t.Fatalf("GetStashes failed: %v", err)
 	}
@@ -1,17 +1,44 @@
 package git
 
 import (
+	"context"
+	"fmt"
+	"strconv"
 	"strings"
 )
 
 // FileStatus represents the status of a single file
 type FileStatus struct {
-	Path                string // Path relative to repo root (for git commands)
-	DisplayPath         string // Path relative to cwd (for display)
-	IndexStatus         byte   // Status in the index (staged)
-	WorkStatus          byte   // Status in the working tree
-	OriginalPath        string // For renamed files (repo-relative)
-	OriginalDisplayPath string // For renamed files (cwd-relative)
+	Path                string         // Path relative to repo root (for git commands)
+	DisplayPath         string         // Path relative to cwd (for display)
+	IndexStatus         byte           // Status in the index (staged)
+	WorkStatus          byte           // Status in the working tree
+	OriginalPath        string         // For renamed files (repo-relative)
+	OriginalDisplayPath string         // For renamed files (cwd-relative)
+	Submodule           SubmoduleState // Submodule state; zero for regular files
+	ModeHead            string         // Octal file mode in HEAD
+	ModeIndex           string         // Octal file mode in the index
+	ModeWorktree        string         // Octal file mode in the working tree
+	HeadOID             string         // Object ID in HEAD
+	IndexOID            string         // Object ID in the index
+	RenameScore         int            // Similarity percentage for renames and copies
+	Stages              [3]IndexStage  // base, ours and theirs of an unmerged file
+}
+
+// IndexStage is one of the index stages of an unmerged file: the merge
+// base (stage 1), ours (stage 2) or theirs (stage 3). Mode is "000000" and
+// OID all zeros when the file is missing from that side.
+type IndexStage struct {
+	Mode string
+	OID  string
+}
+
+// SubmoduleState describes how a submodule differs from the recorded commit
+type SubmoduleState struct {
+	IsSubmodule      bool
+	CommitChanged    bool // checked-out commit differs from the recorded one
+	TrackedChanges   bool // submodule has modified tracked files
+	UntrackedChanges bool // submodule has untracked files
 }
 
 // IsStaged returns true if the file has staged changes
@@ -24,6 +51,36 @@ func (f FileStatus) IsUnstaged() bool {
 	return f.WorkStatus != ' ' && f.WorkStatus != '?'
 }
 
+// IsConflicted returns true if the file has unresolved merge conflicts
+// (XY is one of DD, AU, UD, UA, DU, AA, UU)
+func (f FileStatus) IsConflicted() bool {
+	return f.IndexStatus == 'U' || f.WorkStatus == 'U' ||
+		(f.IndexStatus == 'A' && f.WorkStatus == 'A') ||
+		(f.IndexStatus == 'D' && f.WorkStatus == 'D')
+}
+
+// ConflictDescription describes an unmerged file the way git status does
+func (f FileStatus) ConflictDescription() string {
+	switch string([]byte{f.IndexStatus, f.WorkStatus}) {
+	case "UU":
+		return "both modified"
+	case "AA":
+		return "both added"
+	case "DD":
+		return "both deleted"
+	case "AU":
+		return "added by us"
+	case "UA":
+		return "added by them"
+	case "DU":
+		return "deleted by us"
+	case "UD":
+		return "deleted by them"
+	default:
+		return ""
+	}
+}
+
 // IsUntracked returns true if the file is untracked
 func (f FileStatus) IsUntracked() bool {
 	return f.IndexStatus == '?' && f.WorkStatus == '?'
@@ -34,6 +91,9 @@ func (f FileStatus) StatusDescription() string {
 	if f.IsUntracked() {
 		return "untracked"
 	}
+	if f.IsConflicted() {
+		return "conflict: " + f.ConflictDescription()
+	}
 
 	var parts []string
 
@@ -64,57 +124,94 @@ func (f FileStatus) StatusDescription() string {
 
 // StatusResult holds all file statuses grouped by type
 type StatusResult struct {
-	Staged    []FileStatus
-	Unstaged  []FileStatus
-	Untracked []FileStatus
+	Staged     []FileStatus
+	Unstaged   []FileStatus
+	Untracked  []FileStatus
+	Conflicted []FileStatus   // unmerged paths; not repeated in Staged or Unstaged
+	Branch     BranchStatus   // from the porcelain branch header
+	Operation  OperationState // merge, rebase, etc. stopped part-way through
 }
 
 // GetStatus returns the current git status
-func GetStatus() (*StatusResult, error) {
-	output, err := Run("status", "--porcelain=v1")
+func (r *Repo) GetStatus(ctx context.Context) (*StatusResult, error) {
+	output, err := r.Run(ctx, "status", "--porcelain=v2", "-z", "--branch")
 	if err != nil {
 		return nil, err
 	}
+	result := r.parseStatus(output)
+	result.Operation = r.GetOperationState()
+	return result, nil
+}
 
+// parseStatus parses `git status --porcelain=v2 -z --branch` output
+func (r *Repo) parseStatus(output string) *StatusResult {
 	result := &StatusResult{}
-	lines := strings.Split(output, "\n")
+	records := strings.Split(output, "\x00")
 
-	for _, line := range lines {
-		if len(line) < 3 {
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 2 {
 			continue
 		}
 
-		indexStatus := line[0]
-		workStatus := line[1]
-		path := line[3:]
-
-		// Handle renamed files (format: "R  old -> new")
-		var origPath string
-		if indexStatus == 'R' || indexStatus == 'C' {
-			parts := strings.Split(path, " -> ")
-			if len(parts) == 2 {
-				origPath = parts[0]
-				path = parts[1]
+		var fs FileStatus
+		switch record[0] {
+		case '#':
+			parseBranchHeader(record, &result.Branch)
+			continue
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) < 9 {
+				continue
 			}
+			fs = parseEntry(fields[1:8])
+			fs.Path = fields[8]
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path> NUL <origPath>
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) < 10 || i+1 >= len(records) {
+				continue
+			}
+			fs = parseEntry(fields[1:8])
+			fs.RenameScore, _ = strconv.Atoi(fields[8][1:])
+			fs.Path = fields[9]
+			i++
+			fs.OriginalPath = records[i]
+			fs.OriginalDisplayPath = r.ToDisplayPath(fs.OriginalPath)
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) < 11 {
+				continue
+			}
+			fs = FileStatus{
+				IndexStatus:  statusCode(fields[1][0]),
+				WorkStatus:   statusCode(fields[1][1]),
+				Submodule:    parseSubmoduleState(fields[2]),
+				ModeWorktree: fields[6],
+				Stages: [3]IndexStage{
+					{Mode: fields[3], OID: fields[7]},
+					{Mode: fields[4], OID: fields[8]},
+					{Mode: fields[5], OID: fields[9]},
+				},
+			}
+			// Stage 2 is HEAD's side of the merge
+			fs.ModeHead, fs.HeadOID = fields[4], fields[8]
+			fs.Path = fields[10]
+		case '?':
+			fs = FileStatus{Path: record[2:], IndexStatus: '?', WorkStatus: '?'}
+		default:
+			// '!' ignored entries are not requested
+			continue
 		}
-
-		var origDisplayPath string
-		if origPath != "" {
-			origDisplayPath = ToDisplayPath(origPath)
-		}
-
-		fs := FileStatus{
-			Path:                path,
-			DisplayPath:         ToDisplayPath(path),
-			IndexStatus:         indexStatus,
-			WorkStatus:          workStatus,
-			OriginalPath:        origPath,
-			OriginalDisplayPath: origDisplayPath,
-		}
+		fs.DisplayPath = r.ToDisplayPath(fs.Path)
 
 		// Categorize the file
 		if fs.IsUntracked() {
 			result.Untracked = append(result.Untracked, fs)
+		} else if fs.IsConflicted() {
+			result.Conflicted = append(result.Conflicted, fs)
 		} else {
 			if fs.IsStaged() {
 				result.Staged = append(result.Staged, fs)
@@ -125,7 +222,66 @@ func GetStatus() (*StatusResult, error) {
 		}
 	}
 
-	return result, nil
+	return result
+}
+
+// parseEntry fills the fields shared by changed-entry records:
+// <XY> <sub> <mH> <mI> <mW> <hH> <hI>
+func parseEntry(fields []string) FileStatus {
+	return FileStatus{
+		IndexStatus:  statusCode(fields[0][0]),
+		WorkStatus:   statusCode(fields[0][1]),
+		Submodule:    parseSubmoduleState(fields[1]),
+		ModeHead:     fields[2],
+		ModeIndex:    fields[3],
+		ModeWorktree: fields[4],
+		HeadOID:      fields[5],
+		IndexOID:     fields[6],
+	}
+}
+
+// statusCode maps porcelain v2's '.' (unmodified) to the v1 space
+func statusCode(c byte) byte {
+	if c == '.' {
+		return ' '
+	}
+	return c
+}
+
+// parseSubmoduleState parses the <sub> field: "N..." or "S<c><m><u>"
+func parseSubmoduleState(field string) SubmoduleState {
+	if len(field) != 4 || field[0] != 'S' {
+		return SubmoduleState{}
+	}
+	return SubmoduleState{
+		IsSubmodule:      true,
+		CommitChanged:    field[1] == 'C',
+		TrackedChanges:   field[2] == 'M',
+		UntrackedChanges: field[3] == 'U',
+	}
+}
+
+// parseBranchHeader applies a "# branch.*" header line to status
+func parseBranchHeader(line string, status *BranchStatus) {
+	key, value, _ := strings.Cut(strings.TrimPrefix(line, "# "), " ")
+	switch key {
+	case "branch.oid":
+		if value != "(initial)" {
+			status.Head = value
+		}
+	case "branch.head":
+		if value == "(detached)" {
+			status.Detached = true
+		} else {
+			status.Name = value
+		}
+	case "branch.upstream":
+		status.Upstream = value
+	case "branch.ab":
+		// Only present when the upstream exists
+		status.Remote = status.Upstream
+		fmt.Sscanf(value, "+%d -%d", &status.Ahead, &status.Behind)
+	}
 }
 
 // TotalFiles returns the total number of files with changes
@@ -140,10 +296,13 @@ func (s *StatusResult) TotalFiles() int {
 	for _, f := range s.Untracked {
 		seen[f.Path] = true
 	}
+	for _, f := range s.Conflicted {
+		seen[f.Path] = true
+	}
 	return len(seen)
 }
 
 // IsEmpty returns true if there are no changes
 func (s *StatusResult) IsEmpty() bool {
-	return len(s.Staged) == 0 && len(s.Unstaged) == 0 && len(s.Untracked) == 0
+	return len(s.Staged) == 0 && len(s.Unstaged) == 0 && len(s.Untracked) == 0 && len(s.Conflicted) == 0
 }
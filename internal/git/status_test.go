@@ -10,7 +10,7 @@ func TestGetStatus_EmptyRepo(t *testing.T) {
 
 	repo.InitialCommit()
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -32,7 +32,7 @@ func TestGetStatus_UntrackedFiles(t *testing.T) {
 	repo.WriteFile("untracked1.txt", "content1")
 	repo.WriteFile("untracked2.txt", "content2")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -71,7 +71,7 @@ func TestGetStatus_StagedFiles(t *testing.T) {
 	repo.WriteFile("staged.txt", "content")
 	repo.Git("add", "staged.txt")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -104,7 +104,7 @@ func TestGetStatus_ModifiedUnstaged(t *testing.T) {
 	repo.CommitFile("test.txt", "original", "initial")
 	repo.WriteFile("test.txt", "modified")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -134,7 +134,7 @@ func TestGetStatus_ModifiedStaged(t *testing.T) {
 	repo.WriteFile("test.txt", "modified")
 	repo.Git("add", "test.txt")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -161,7 +161,7 @@ func TestGetStatus_MixedStagedAndUnstaged(t *testing.T) {
 	// Make another change (unstaged)
 	repo.WriteFile("test.txt", "staged change\nwith unstaged addition")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -193,7 +193,7 @@ func TestGetStatus_DeletedFile(t *testing.T) {
 	repo.CommitFile("test.txt", "content", "initial")
 	repo.DeleteFile("test.txt")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -214,7 +214,7 @@ func TestGetStatus_StagedDeleted(t *testing.T) {
 	repo.CommitFile("test.txt", "content", "initial")
 	repo.Git("rm", "test.txt")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -235,7 +235,7 @@ func TestGetStatus_RenamedFile(t *testing.T) {
 	repo.CommitFile("old-name.txt", "content", "initial")
 	repo.Git("mv", "old-name.txt", "new-name.txt")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -269,7 +269,7 @@ func TestGetStatus_MultipleFileTypes(t *testing.T) {
 	repo.Git("add", "staged-new.txt")
 	repo.WriteFile("existing.txt", "modified content")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -293,9 +293,9 @@ func TestGetStatus_MultipleFileTypes(t *testing.T) {
 
 func TestFileStatus_StatusDescription(t *testing.T) {
 	tests := []struct {
-		name        string
-		status      FileStatus
-		expected    string
+		name     string
+		status   FileStatus
+		expected string
 	}{
 		{
 			name: "untracked",
@@ -305,6 +305,22 @@ func TestFileStatus_StatusDescription(t *testing.T) {
 			},
 			expected: "untracked",
 		},
+		{
+			name: "both modified",
+			status: FileStatus{
+				IndexStatus: 'U',
+				WorkStatus:  'U',
+			},
+			expected: "conflict: both modified",
+		},
+		{
+			name: "deleted by us",
+			status: FileStatus{
+				IndexStatus: 'D',
+				WorkStatus:  'U',
+			},
+			expected: "conflict: deleted by us",
+		},
 		{
 			name: "staged modified",
 			status: FileStatus{
@@ -426,7 +442,7 @@ func TestGetStatus_Subdirectories(t *testing.T) {
 	repo.WriteFile("dir1/dir2/file2.txt", "content2")
 	repo.Git("add", "-A")
 
-	status, err := GetStatus()
+	status, err := repo.Repo.GetStatus(t.Context())
 	if err != nil {
 		t.Fatalf("GetStatus failed: %v", err)
 	}
@@ -454,3 +470,170 @@ func TestGetStatus_Subdirectories(t *testing.T) {
 		t.Error("expected to find dir1/dir2/file2.txt")
 	}
 }
+
+func TestGetStatus_RenameWithArrowInName(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("a -> b.txt", "content\n", "initial")
+	repo.Git("mv", "a -> b.txt", "c -> d.txt")
+
+	status, err := repo.Repo.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	if len(status.Staged) != 1 {
+		t.Fatalf("expected 1 staged file, got %d", len(status.Staged))
+	}
+	f := status.Staged[0]
+	if f.IndexStatus != 'R' {
+		t.Errorf("expected rename, got %c", f.IndexStatus)
+	}
+	if f.Path != "c -> d.txt" || f.OriginalPath != "a -> b.txt" {
+		t.Errorf("unexpected paths %q <- %q", f.Path, f.OriginalPath)
+	}
+	if f.RenameScore != 100 {
+		t.Errorf("expected rename score 100, got %d", f.RenameScore)
+	}
+}
+
+func TestGetStatus_UnusualFilenames(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	names := []string{"new\nline.txt", "ünïcødé.txt", `quote"d.txt`}
+	for _, name := range names {
+		repo.WriteFile(name, "content")
+	}
+
+	status, err := repo.Repo.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, f := range status.Untracked {
+		got[f.Path] = true
+	}
+	for _, name := range names {
+		if !got[name] {
+			t.Errorf("expected untracked %q, got %v", name, status.Untracked)
+		}
+	}
+}
+
+func TestGetStatus_EntryMetadata(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.CommitFile("file.txt", "v1\n", "initial")
+	repo.WriteFile("file.txt", "v2\n")
+	repo.Git("add", "file.txt")
+
+	status, err := repo.Repo.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	f := status.Staged[0]
+	if f.ModeHead != "100644" || f.ModeIndex != "100644" || f.ModeWorktree != "100644" {
+		t.Errorf("unexpected modes %s %s %s", f.ModeHead, f.ModeIndex, f.ModeWorktree)
+	}
+	headOID := repo.Git("rev-parse", "HEAD:file.txt")
+	if f.HeadOID != headOID[:len(headOID)-1] {
+		t.Errorf("HeadOID = %s, want %s", f.HeadOID, headOID)
+	}
+	if f.IndexOID == "" || f.IndexOID == f.HeadOID {
+		t.Errorf("expected a new index object ID, got %s", f.IndexOID)
+	}
+	if f.Submodule.IsSubmodule {
+		t.Error("regular file should not be reported as a submodule")
+	}
+}
+
+func TestGetStatus_BranchHeader(t *testing.T) {
+	repo := NewTestRepo(t)
+	defer repo.Cleanup()
+
+	repo.InitialCommit()
+	repo.Git("checkout", "-b", "topic")
+
+	status, err := repo.Repo.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	head := repo.Git("rev-parse", "HEAD")
+	if status.Branch.Name != "topic" {
+		t.Errorf("Name = %q, want topic", status.Branch.Name)
+	}
+	if status.Branch.Head != head[:len(head)-1] {
+		t.Errorf("Head = %q, want %q", status.Branch.Head, head)
+	}
+	if status.Branch.Remote != "" || status.Branch.Detached {
+		t.Errorf("unexpected branch status %+v", status.Branch)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	r := &Repo{root: "/repo", workDir: "/repo"}
+	output := "# branch.oid (initial)\x00" +
+		"# branch.head (detached)\x00" +
+		"# branch.upstream origin/gone\x00" +
+		"1 .M SCMU 160000 160000 160000 1111111111111111111111111111111111111111 1111111111111111111111111111111111111111 sub\x00" +
+		"2 RM N... 100644 100644 100644 2222222222222222222222222222222222222222 2222222222222222222222222222222222222222 C87 new name\x00old name\x00" +
+		"u UU N... 100644 100644 100644 100644 3333333333333333333333333333333333333333 4444444444444444444444444444444444444444 5555555555555555555555555555555555555555 conflict.txt\x00" +
+		"? untracked file\x00"
+
+	status := r.parseStatus(output)
+
+	if !status.Branch.Detached || status.Branch.Head != "" {
+		t.Errorf("expected detached HEAD before first commit, got %+v", status.Branch)
+	}
+	if status.Branch.Upstream != "origin/gone" || status.Branch.Remote != "" {
+		t.Errorf("a missing upstream should not be usable as Remote: %+v", status.Branch)
+	}
+
+	if len(status.Unstaged) != 2 {
+		t.Fatalf("expected 3 unstaged entries, got %+v", status.Unstaged)
+	}
+	sub := status.Unstaged[0]
+	if sub.Path != "sub" || sub.IndexStatus != ' ' || sub.WorkStatus != 'M' {
+		t.Errorf("unexpected submodule entry %+v", sub)
+	}
+	if sub.Submodule != (SubmoduleState{IsSubmodule: true, CommitChanged: true, TrackedChanges: true, UntrackedChanges: true}) {
+		t.Errorf("unexpected submodule state %+v", sub.Submodule)
+	}
+
+	if len(status.Staged) != 1 {
+		t.Fatalf("expected 1 staged entry, got %+v", status.Staged)
+	}
+	renamed := status.Staged[0]
+	if renamed.Path != "new name" || renamed.OriginalPath != "old name" || renamed.RenameScore != 87 {
+		t.Errorf("unexpected rename entry %+v", renamed)
+	}
+	if len(status.Conflicted) != 1 {
+		t.Fatalf("expected 1 conflicted entry, got %+v", status.Conflicted)
+	}
+	conflict := status.Conflicted[0]
+	if conflict.Path != "conflict.txt" || conflict.IndexStatus != 'U' || conflict.WorkStatus != 'U' {
+		t.Errorf("unexpected unmerged entry %+v", conflict)
+	}
+	wantStages := [3]IndexStage{
+		{Mode: "100644", OID: "3333333333333333333333333333333333333333"},
+		{Mode: "100644", OID: "4444444444444444444444444444444444444444"},
+		{Mode: "100644", OID: "5555555555555555555555555555555555555555"},
+	}
+	if conflict.Stages != wantStages {
+		t.Errorf("Stages = %+v, want %+v", conflict.Stages, wantStages)
+	}
+	if conflict.HeadOID != wantStages[1].OID || conflict.IndexOID != "" {
+		t.Errorf("HeadOID = %q, IndexOID = %q; want ours and none", conflict.HeadOID, conflict.IndexOID)
+	}
+
+	if len(status.Untracked) != 1 || status.Untracked[0].Path != "untracked file" {
+		t.Errorf("unexpected untracked entries %+v", status.Untracked)
+	}
+}
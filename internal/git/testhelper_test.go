@@ -4,26 +4,21 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
 // TestRepo provides a temporary git repository for testing
 type TestRepo struct {
-	Dir     string
-	T       *testing.T
-	origDir string
+	Dir  string
+	T    *testing.T
+	Repo *Repo // handle used to call the git package under test
 }
 
 // NewTestRepo creates a new temporary git repository
 func NewTestRepo(t *testing.T) *TestRepo {
 	t.Helper()
 
-	// Get the original working directory
-	origDir, err := os.Getwd()
-	if err != nil {
-		t.Fatalf("failed to get working directory: %v", err)
-	}
-
 	// Create a temp directory
 	dir, err := os.MkdirTemp("", "go-on-git-test-*")
 	if err != nil {
@@ -31,35 +26,28 @@ func NewTestRepo(t *testing.T) *TestRepo {
 	}
 
 	repo := &TestRepo{
-		Dir:     dir,
-		T:       t,
-		origDir: origDir,
-	}
-
-	// Change to the temp directory
-	if err := os.Chdir(dir); err != nil {
-		os.RemoveAll(dir)
-		t.Fatalf("failed to change to temp dir: %v", err)
+		Dir: dir,
+		T:   t,
 	}
 
-	// Reset cached repo root for the new test directory
-	ResetRepoRoot()
-
 	// Initialize git repo
 	repo.Git("init")
 	repo.Git("config", "user.email", "test@example.com")
 	repo.Git("config", "user.name", "Test User")
 
+	repo.Repo, err = Open(t.Context(), dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("failed to open repo: %v", err)
+	}
+
 	return repo
 }
 
-// Cleanup removes the temp directory and restores the original working directory
+// Cleanup removes the temp directory
 func (r *TestRepo) Cleanup() {
 	r.T.Helper()
-	os.Chdir(r.origDir)
 	os.RemoveAll(r.Dir)
-	// Reset cached repo root when switching back
-	ResetRepoRoot()
 }
 
 // Git runs a git command in the test repo
@@ -172,3 +160,47 @@ func (r *TestRepo) PushToRemote() {
 	r.T.Helper()
 	r.Git("push", "-u", "origin", "HEAD")
 }
+
+// PushFromClone commits a file in a fresh clone of remoteDir and pushes
+// it, as someone else working on the same remote would
+func (r *TestRepo) PushFromClone(remoteDir, name, content, message string) {
+	r.T.Helper()
+	dir := r.T.TempDir()
+	for _, args := range [][]string{
+		{"clone", remoteDir, dir},
+		{"-C", dir, "config", "user.email", "other@example.com"},
+		{"-C", dir, "config", "user.name", "Other"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			r.T.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		r.T.Fatalf("failed to write %s: %v", name, err)
+	}
+	for _, args := range [][]string{
+		{"-C", dir, "add", name},
+		{"-C", dir, "commit", "-m", message},
+		{"-C", dir, "push"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			r.T.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+}
+
+// CreateConflict merges a branch that changed name differently from the
+// current branch, leaving the merge stopped with name conflicted.
+// base, ours and theirs are the three versions of the file.
+func (r *TestRepo) CreateConflict(name, base, ours, theirs string) {
+	r.T.Helper()
+	r.CommitFile(name, base, "base "+name)
+	current := strings.TrimSpace(r.Git("branch", "--show-current"))
+	r.Git("checkout", "-b", "theirs-"+name)
+	r.CommitFile(name, theirs, "theirs "+name)
+	r.Git("checkout", current)
+	r.CommitFile(name, ours, "ours "+name)
+	if _, err := r.GitAllowFailure("merge", "theirs-"+name); err == nil {
+		r.T.Fatalf("expected merge of %s to conflict", name)
+	}
+}
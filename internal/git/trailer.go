@@ -0,0 +1,104 @@
+package git
+
+import (
+	"context"
+	"strings"
+)
+
+// Trailer is a "Key: value" line at the end of a commit message, such as
+// Co-authored-by or Signed-off-by
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// String formats the trailer as git writes it
+func (t Trailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// ParseTrailer parses a "Key: value" line. The value may be empty, for a
+// trailer whose value is filled in when it is used.
+func ParseTrailer(s string) (Trailer, bool) {
+	key, value, found := strings.Cut(s, ":")
+	key = strings.TrimSpace(key)
+	if !found || key == "" || strings.ContainsAny(key, " \t") {
+		return Trailer{}, false
+	}
+	return Trailer{Key: key, Value: strings.TrimSpace(value)}, true
+}
+
+// Authors returns the "Name <email>" of everyone who authored a commit
+// reachable from HEAD, most commits first
+func (r *Repo) Authors(ctx context.Context) ([]string, error) {
+	if !r.hasCommits(ctx) {
+		return nil, nil
+	}
+	output, err := r.Run(ctx, "shortlog", "-sne", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var authors []string
+	for _, line := range strings.Split(output, "\n") {
+		if _, author, found := strings.Cut(line, "\t"); found {
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
+
+// TrailerSuggestions returns the trailers to offer for a new commit:
+// Signed-off-by for the configured user, the trailers listed in
+// go-on-git.trailer (repeated, as "Key: value" or just "Key:"), and
+// Co-authored-by for every other author in the history.
+func (r *Repo) TrailerSuggestions(ctx context.Context) ([]Trailer, error) {
+	var trailers []Trailer
+	name, err := r.Config(ctx, "user.name")
+	if err != nil {
+		return nil, err
+	}
+	email, err := r.Config(ctx, "user.email")
+	if err != nil {
+		return nil, err
+	}
+	if name != "" && email != "" {
+		trailers = append(trailers, Trailer{"Signed-off-by", name + " <" + email + ">"})
+	}
+
+	configured, err := r.config(ctx, "--get-all", "go-on-git.trailer")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(configured, "\n") {
+		if trailer, ok := ParseTrailer(line); ok {
+			trailers = append(trailers, trailer)
+		}
+	}
+
+	authors, err := r.Authors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, author := range authors {
+		if email != "" && strings.HasSuffix(author, "<"+email+">") {
+			continue
+		}
+		trailers = append(trailers, Trailer{"Co-authored-by", author})
+	}
+	return trailers, nil
+}
+
+// AddTrailers appends trailers to a commit message with git
+// interpret-trailers, which keeps them in one block after any trailers
+// already there and leaves out exact duplicates
+func (r *Repo) AddTrailers(ctx context.Context, message string, trailers []Trailer) (string, error) {
+	args := []string{"interpret-trailers", "--if-exists=addIfDifferent", "--no-divider"}
+	for _, trailer := range trailers {
+		args = append(args, "--trailer", trailer.String())
+	}
+	result, err := r.execute(ctx, message+"\n", args...)
+	if err != nil {
+		return "", newError(args, result, err)
+	}
+	return strings.TrimRight(result.Stdout, "\n"), nil
+}
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTrailer(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Trailer
+		ok   bool
+	}{
+		{"Reviewed-by: Jane <jane@example.com>", Trailer{"Reviewed-by", "Jane <jane@example.com>"}, true},
+		{"Refs:", Trailer{"Refs", ""}, true},
+		{"not a trailer", Trailer{}, false},
+		{"Two words: value", Trailer{}, false},
+		{": value", Trailer{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseTrailer(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseTrailer(%q) = %+v, %v; want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTrailerSuggestions(t *testing.T) {
+	repo := NewTestRepo(t)
+
+	trailers, err := repo.Repo.TrailerSuggestions(t.Context())
+	if err != nil {
+		t.Fatalf("TrailerSuggestions failed: %v", err)
+	}
+	if !slices.Equal(trailers, []Trailer{{"Signed-off-by", "Test User <test@example.com>"}}) {
+		t.Errorf("without history trailers = %+v", trailers)
+	}
+
+	repo.CommitFile("a.txt", "a\n", "Add a")
+	repo.Git("commit", "--allow-empty", "--author=Alice <alice@example.com>", "-m", "One")
+	repo.Git("commit", "--allow-empty", "--author=Bob <bob@example.com>", "-m", "Two")
+	repo.Git("commit", "--allow-empty", "--author=Bob <bob@example.com>", "-m", "Three")
+	repo.Git("config", "go-on-git.trailer", "Refs:")
+	repo.Git("config", "--add", "go-on-git.trailer", "Reviewed-by: Carol <carol@example.com>")
+
+	authors, err := repo.Repo.Authors(t.Context())
+	if err != nil {
+		t.Fatalf("Authors failed: %v", err)
+	}
+	if len(authors) != 3 || authors[0] != "Bob <bob@example.com>" {
+		t.Errorf("authors = %q, want the most frequent first", authors)
+	}
+
+	trailers, err = repo.Repo.TrailerSuggestions(t.Context())
+	if err != nil {
+		t.Fatalf("TrailerSuggestions failed: %v", err)
+	}
+	want := []Trailer{
+		{"Signed-off-by", "Test User <test@example.com>"},
+		{"Refs", ""},
+		{"Reviewed-by", "Carol <carol@example.com>"},
+		{"Co-authored-by", "Bob <bob@example.com>"},
+		{"Co-authored-by", "Alice <alice@example.com>"},
+	}
+	if !slices.Equal(trailers, want) {
+		t.Errorf("trailers = %+v\nwant %+v", trailers, want)
+	}
+}
+
+func TestAddTrailers(t *testing.T) {
+	repo := NewTestRepo(t)
+	coAuthor := Trailer{"Co-authored-by", "Alice <alice@example.com>"}
+
+	message, err := repo.Repo.AddTrailers(t.Context(), "Subject\n\nBody\n---\nnot a divider", []Trailer{coAuthor})
+	if err != nil {
+		t.Fatalf("AddTrailers failed: %v", err)
+	}
+	if message != "Subject\n\nBody\n---\nnot a divider\n\nCo-authored-by: Alice <alice@example.com>" {
+		t.Errorf("message = %q", message)
+	}
+
+	// Added to the existing block, without repeating one already there
+	message, err = repo.Repo.AddTrailers(t.Context(), message, []Trailer{coAuthor, {"Refs", "PROJ-1"}})
+	if err != nil {
+		t.Fatalf("AddTrailers failed: %v", err)
+	}
+	if message != "Subject\n\nBody\n---\nnot a divider\n\nCo-authored-by: Alice <alice@example.com>\nRefs: PROJ-1" {
+		t.Errorf("message = %q", message)
+	}
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"go-on-git/internal/git"
 	"time"
 
@@ -21,6 +22,9 @@ const (
 	viewStashes
 	viewStashDiff // drill-down from stashes to stash diff
 	viewLog
+	viewConflict // drill-down from a conflicted file in status
+	viewCommit   // drill-down from log to a single commit
+	viewRebase   // interactive rebase planner, launched from the log
 )
 
 // FileFilter specifies which hunks to show for a file
@@ -32,29 +36,34 @@ type FileFilter struct {
 
 // AppModel is the root model that manages views
 type AppModel struct {
+	repo         *git.Repo
 	mode         viewMode
 	status       StatusModel
 	diff         DiffModel
 	branches     BranchesModel
 	stashes      StashesModel
 	log          LogModel
+	conflict     ConflictModel
+	commit       CommitModel
+	rebase       RebaseModel
 	currentFiles []FileFilter // files being viewed in diff mode
 	width        int
 	height       int
 }
 
 // NewAppModel creates a new app model starting in status view
-func NewAppModel() AppModel {
-	return NewAppModelWithOptions(false)
+func NewAppModel(repo *git.Repo) AppModel {
+	return NewAppModelWithOptions(repo, false)
 }
 
 // NewAppModelWithOptions creates a new app model with options
-func NewAppModelWithOptions(showHelp bool) AppModel {
+func NewAppModelWithOptions(repo *git.Repo, showHelp bool) AppModel {
 	return AppModel{
+		repo:     repo,
 		mode:     viewStatus,
-		status:   NewStatusModelWithHelp(showHelp),
-		branches: NewBranchesModel(),
-		stashes:  NewStashesModel(),
+		status:   NewStatusModelWithHelp(repo, showHelp),
+		branches: NewBranchesModel(repo),
+		stashes:  NewStashesModel(repo),
 	}
 }
 
@@ -84,11 +93,45 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.stashes.height = msg.Height
 		m.log.width = msg.Width
 		m.log.height = msg.Height
+		m.conflict.width = msg.Width
+		m.conflict.height = msg.Height
+		m.commit.setSize(msg.Width, msg.Height)
+		m.rebase.width = msg.Width
+		m.rebase.height = msg.Height
+
+	case conflictWrittenMsg:
+		if m.mode != viewConflict {
+			return m, nil
+		}
+		newConflict, cmd := m.conflict.Update(msg)
+		m.conflict = newConflict.(ConflictModel)
+		// Every block is resolved and the file staged: back to status,
+		// unless choices made meanwhile are still to be written
+		if msg.resolved && !m.conflict.writing {
+			m.mode = viewStatus
+			return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
+		}
+		return m, cmd
+
+	case rebaseDoneMsg:
+		// Finished, or stopped for an edit or a conflict: status shows which,
+		// with continue and abort at hand. Failures stay in the planner.
+		if m.mode == viewRebase && msg.err == nil {
+			m.mode = viewStatus
+			return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
+		}
+
+	case operationDoneMsg, syncDoneMsg, transferDoneMsg, progressMsg:
+		// Always deliver to status, which started the operation, even if
+		// the user has since switched views
+		newStatus, cmd := m.status.Update(msg)
+		m.status = newStatus.(StatusModel)
+		return m, cmd
 
 	case tickMsg:
 		// Only auto-refresh in status view when not in a blocking mode and git isn't locked
-		if m.mode == viewStatus && !m.status.isBlocking() && !git.IsLocked() {
-			return m, tea.Batch(refreshStatus, tickCmd())
+		if m.mode == viewStatus && !m.status.isBlocking() && !m.repo.IsLocked() {
+			return m, tea.Batch(m.status.refreshStatus, tickCmd())
 		}
 		return m, tickCmd()
 
@@ -103,13 +146,19 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch m.mode {
 		case viewStatus:
 			// Skip navigation when in input modes
-			if m.status.commitMode || m.status.stashMode != stashNone || m.status.confirmMode != confirmNone {
+			if m.status.commitMode || m.status.fixupMode || m.status.stashMode != stashNone || m.status.confirmMode != confirmNone {
 				break
 			}
 			// Handle navigation keys from status
 			if key == Keys.FileDiff || key == Keys.Right || key == "right" || key == "enter" {
 				// Enter file diff view for selected file(s)
 				items := m.status.getSelectedItems()
+				if len(items) == 1 && items[0].Section == "conflicted" {
+					// Conflicted files open in the resolution view instead
+					m.conflict = NewConflictModel(m.repo, items[0].File.Path, m.width, m.height)
+					m.mode = viewConflict
+					return m, tea.Batch(tea.EnterAltScreen, m.conflict.Init())
+				}
 				if len(items) > 0 {
 					m.currentFiles = make([]FileFilter, len(items))
 					for i, item := range items {
@@ -119,54 +168,64 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							Untracked:  item.Section == "untracked",
 						}
 					}
-					m.diff = NewDiffModelWithFilters(m.currentFiles, m.width, m.height)
+					m.diff = NewDiffModelWithFilters(m.repo, m.currentFiles, m.width, m.height)
 					m.mode = viewFileDiff
 					return m, tea.Batch(tea.EnterAltScreen, m.diff.Init())
 				}
 				return m, nil
 			} else if key == Keys.AllDiffs {
 				// Enter full diff view
-				m.diff = NewDiffModelWithSize(nil, m.width, m.height)
+				m.diff = NewDiffModelWithSize(m.repo, nil, m.width, m.height)
 				m.mode = viewFullDiff
 				return m, tea.Batch(tea.EnterAltScreen, m.diff.Init())
 			} else if key == Keys.Branches {
 				// Enter branches view
-				m.branches = NewBranchesModelWithOptions(m.status.showVerboseHelp)
+				m.branches = NewBranchesModelWithOptions(m.repo, m.status.showVerboseHelp)
 				m.branches.width = m.width
 				m.branches.height = m.height
 				m.mode = viewBranches
 				return m, tea.Batch(tea.EnterAltScreen, m.branches.Init())
 			} else if key == Keys.Stashes {
 				// Enter stashes view
-				m.stashes = NewStashesModelWithOptions(m.status.showVerboseHelp)
+				m.stashes = NewStashesModelWithOptions(m.repo, m.status.showVerboseHelp)
 				m.stashes.width = m.width
 				m.stashes.height = m.height
 				m.mode = viewStashes
 				return m, tea.Batch(tea.EnterAltScreen, m.stashes.Init())
 			} else if key == Keys.Log {
 				// Enter log view
-				m.log = NewLogModelWithOptions(m.width, m.height, m.status.showVerboseHelp)
+				m.log = NewLogModelWithOptions(m.repo, m.width, m.height, m.status.showVerboseHelp)
+				m.mode = viewLog
+				return m, tea.Batch(tea.EnterAltScreen, m.log.Init())
+			} else if key == Keys.FileLog {
+				// Enter log view filtered to the selected file's history
+				items := m.status.getSelectedItems()
+				if len(items) != 1 {
+					return m, nil
+				}
+				filter := git.LogFilter{Paths: []string{items[0].File.Path}, Follow: true}
+				m.log = NewLogModelWithFilter(m.repo, m.width, m.height, m.status.showVerboseHelp, filter)
 				m.mode = viewLog
 				return m, tea.Batch(tea.EnterAltScreen, m.log.Init())
 			}
 
 		case viewFileDiff:
 			// Handle back navigation from file diff
-			if key == Keys.Left || key == "left" || key == "esc" {
+			if (key == Keys.Left || key == "left" || key == "esc") && m.diff.editRetryPath == "" {
 				inHunkDetail := m.diff.IsViewingHunk()
 				if !inHunkDetail || (len(m.diff.hunks) == 1 && !m.diff.showHelp && !m.diff.confirmMode) {
 					m.mode = viewStatus
-					return m, tea.Batch(tea.ExitAltScreen, refreshStatus)
+					return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
 				}
 			}
 
 		case viewFullDiff:
 			// Handle back navigation from full diff
-			if key == Keys.Left || key == "left" || key == "esc" {
+			if (key == Keys.Left || key == "left" || key == "esc") && m.diff.editRetryPath == "" {
 				inHunkDetail := m.diff.IsViewingHunk()
 				if !inHunkDetail || (len(m.diff.hunks) == 1 && !m.diff.showHelp && !m.diff.confirmMode) {
 					m.mode = viewStatus
-					return m, tea.Batch(tea.ExitAltScreen, refreshStatus)
+					return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
 				}
 			}
 
@@ -175,14 +234,14 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if key == Keys.Left || key == "left" || key == "esc" {
 				if !m.branches.showHelp && !m.branches.deleteConfirmMode && !m.branches.inputMode && !m.branches.forceDeleteMode {
 					m.mode = viewStatus
-					return m, tea.Batch(tea.ExitAltScreen, refreshStatus)
+					return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
 				}
 			}
 			// Override quit to go back instead
 			if key == Keys.Quit {
 				if !m.branches.showHelp && !m.branches.deleteConfirmMode && !m.branches.inputMode && !m.branches.forceDeleteMode {
 					m.mode = viewStatus
-					return m, tea.Batch(tea.ExitAltScreen, refreshStatus)
+					return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
 				}
 			}
 
@@ -195,7 +254,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.stashes.diffModel = NewStashDiffModel(m.width, m.height)
 						m.mode = viewStashDiff
 						return m, func() tea.Msg {
-							diff, err := git.GetStashDiff(stash.Index)
+							diff, err := m.repo.GetStashDiff(context.Background(), stash.Index)
 							if err != nil {
 								return errMsg{err}
 							}
@@ -209,14 +268,14 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if key == Keys.Left || key == "left" || key == "esc" {
 				if !m.stashes.showHelp && !m.stashes.confirmMode {
 					m.mode = viewStatus
-					return m, tea.Batch(tea.ExitAltScreen, refreshStatus)
+					return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
 				}
 			}
 			// Override quit to go back instead
 			if key == Keys.Quit {
 				if !m.stashes.showHelp && !m.stashes.confirmMode {
 					m.mode = viewStatus
-					return m, tea.Batch(tea.ExitAltScreen, refreshStatus)
+					return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
 				}
 			}
 
@@ -242,12 +301,64 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case viewConflict:
+			// Handle back navigation from conflict view
+			if key == Keys.Left || key == "left" || key == "esc" {
+				if !m.conflict.showHelp {
+					m.mode = viewStatus
+					return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
+				}
+			}
+
 		case viewLog:
+			// Handle drill-down to commit detail
+			if key == Keys.Right || key == "right" || key == "enter" {
+				if commit, ok := m.log.SelectedCommit(); ok && !m.log.showHelp && !m.log.filterMode {
+					m.commit = NewCommitModel(m.repo, commit, m.log.upstream, m.width, m.height)
+					m.mode = viewCommit
+					return m, m.commit.Init()
+				}
+				return m, nil
+			}
+			// Plan an interactive rebase onto the selected commit
+			if key == Keys.Rebase && !m.log.showHelp && !m.log.filterMode {
+				if commit, ok := m.log.SelectedCommit(); ok {
+					m.rebase = NewRebaseModel(m.repo, commit, m.width, m.height, m.log.showVerboseHelp)
+					m.mode = viewRebase
+					return m, m.rebase.Init()
+				}
+				return m, nil
+			}
 			// Handle back navigation from log
 			if key == Keys.Left || key == "left" || key == "esc" || key == Keys.Quit || key == Keys.Log {
-				if !m.log.showHelp {
+				if !m.log.showHelp && !m.log.filterMode {
 					m.mode = viewStatus
-					return m, tea.Batch(tea.ExitAltScreen, refreshStatus)
+					return m, tea.Batch(tea.ExitAltScreen, m.status.refreshStatus)
+				}
+			}
+
+		case viewCommit:
+			// Handle back navigation from commit detail; inside a hunk or
+			// the full diff, the diff model steps back itself
+			if key == Keys.Left || key == "left" || key == "esc" {
+				if m.commit.AtTop() {
+					m.mode = viewLog
+					return m, nil
+				}
+			}
+			// Override quit to go back
+			if key == Keys.Quit && !m.commit.diff.showHelp {
+				m.mode = viewLog
+				return m, nil
+			}
+
+		case viewRebase:
+			// Handle back navigation from the planner, unless editing a
+			// message, confirming or running
+			if key == Keys.Left || key == "left" || key == "esc" || key == Keys.Quit {
+				if m.rebase.AtTop() {
+					m.mode = viewLog
+					return m, nil
 				}
 			}
 		}
@@ -279,6 +390,18 @@ func (m AppModel) updateCurrentView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		newLog, cmd := m.log.Update(msg)
 		m.log = newLog.(LogModel)
 		return m, cmd
+	case viewConflict:
+		newConflict, cmd := m.conflict.Update(msg)
+		m.conflict = newConflict.(ConflictModel)
+		return m, cmd
+	case viewCommit:
+		newCommit, cmd := m.commit.Update(msg)
+		m.commit = newCommit.(CommitModel)
+		return m, cmd
+	case viewRebase:
+		newRebase, cmd := m.rebase.Update(msg)
+		m.rebase = newRebase.(RebaseModel)
+		return m, cmd
 	default:
 		newStatus, cmd := m.status.Update(msg)
 		m.status = newStatus.(StatusModel)
@@ -298,6 +421,12 @@ func (m AppModel) View() string {
 		return m.stashes.diffModel.View()
 	case viewLog:
 		return m.log.View()
+	case viewConflict:
+		return m.conflict.View()
+	case viewCommit:
+		return m.commit.View()
+	case viewRebase:
+		return m.rebase.View()
 	default:
 		return m.status.View()
 	}
@@ -338,11 +467,56 @@ type stashDiffMsg struct {
 	diff *git.CombinedDiffResult
 }
 
-func refreshStatus() tea.Msg {
-	status, err := git.GetStatus()
+type conflictMsg struct {
+	file *git.ConflictFile
+}
+
+// conflictWrittenMsg reports that a conflict resolution was written to disk
+type conflictWrittenMsg struct {
+	resolved bool   // every block resolved and the file staged
+	written  string // content written
+	err      error
+}
+
+// hunkEditedMsg reports that the editor opened on a hunk patch has exited
+type hunkEditedMsg struct {
+	path string // temp file holding the edited patch
+	err  error
+}
+
+// hunkEditFailedMsg reports an edited hunk patch that doesn't apply
+type hunkEditFailedMsg struct {
+	path string
+	err  error
+}
+
+// fixupDoneMsg reports that a fixup! commit for target was created
+type fixupDoneMsg struct {
+	target git.Commit
+}
+
+// commitDoneMsg reports that git commit, run in the user's editor, has
+// exited
+type commitDoneMsg struct {
+	err error
+}
+
+// rebaseDoneMsg reports that an interactive rebase has run. A rebase that
+// stopped for an edit or a conflict is still in progress, not an error.
+type rebaseDoneMsg struct {
+	err error
+}
+
+// operationDoneMsg reports the end of a cancellable operation such as push
+type operationDoneMsg struct {
+	op  string
+	err error
+}
+
+func (m StatusModel) refreshStatus() tea.Msg {
+	status, err := m.repo.GetStatus(context.Background())
 	if err != nil {
 		return errMsg{err}
 	}
-	branchStatus := git.GetBranchStatus()
-	return statusMsg{status, branchStatus}
+	return statusMsg{status, status.Branch}
 }
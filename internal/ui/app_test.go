@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -10,7 +11,7 @@ import (
 )
 
 func TestNewAppModel(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 
 	if m.mode != viewStatus {
 		t.Errorf("mode = %v, want viewStatus", m.mode)
@@ -18,15 +19,33 @@ func TestNewAppModel(t *testing.T) {
 }
 
 func TestNewAppModelWithOptions(t *testing.T) {
-	m := NewAppModelWithOptions(true)
+	m := NewAppModelWithOptions(nil, true)
 
 	if !m.status.showVerboseHelp {
 		t.Error("status.showVerboseHelp should be true when showHelp=true")
 	}
 }
 
+func TestNewAppModelSharesRepo(t *testing.T) {
+	repo := &git.Repo{}
+	m := NewAppModel(repo)
+
+	if m.repo != repo {
+		t.Error("app model should hold the repo it was created with")
+	}
+	if m.status.repo != repo || m.branches.repo != repo || m.stashes.repo != repo {
+		t.Error("sub-models should share the app's repo")
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = newModel.(AppModel)
+	if m.log.repo != repo {
+		t.Error("log model should share the app's repo")
+	}
+}
+
 func TestAppModelInit(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	cmd := m.Init()
 
 	if cmd == nil {
@@ -35,7 +54,7 @@ func TestAppModelInit(t *testing.T) {
 }
 
 func TestAppModelWindowResize(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
 	m = newModel.(AppModel)
@@ -64,7 +83,7 @@ func TestAppModelWindowResize(t *testing.T) {
 }
 
 func TestAppModelCtrlCQuits(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
 
@@ -74,7 +93,7 @@ func TestAppModelCtrlCQuits(t *testing.T) {
 }
 
 func TestAppModelNavigateToBranches(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
@@ -89,7 +108,7 @@ func TestAppModelNavigateToBranches(t *testing.T) {
 }
 
 func TestAppModelNavigateToStashes(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
@@ -104,7 +123,7 @@ func TestAppModelNavigateToStashes(t *testing.T) {
 }
 
 func TestAppModelNavigateToLog(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
@@ -118,8 +137,45 @@ func TestAppModelNavigateToLog(t *testing.T) {
 	}
 }
 
+func TestAppModelSyncDoneInLogView(t *testing.T) {
+	m := NewAppModel(nil)
+	m.status.startOperation("fetch")
+	m.mode = viewLog
+
+	newModel, _ := m.Update(syncDoneMsg{op: "fetch", done: "Fetched"})
+	m = newModel.(AppModel)
+	if m.status.runningOp != "" {
+		t.Errorf("status should finish the fetch while the log is open, runningOp = %q", m.status.runningOp)
+	}
+	if m.mode != viewLog {
+		t.Errorf("mode = %v, want viewLog", m.mode)
+	}
+}
+
+func TestAppModelTransferDoneInLogView(t *testing.T) {
+	m := NewAppModel(nil)
+	m.status.startOperation("push")
+	m.mode = viewLog
+
+	updates := make(chan git.Progress)
+	newModel, cmd := m.Update(progressMsg{progress: git.Progress{Phase: "Writing objects", Current: 1, Total: 2}, updates: updates})
+	m = newModel.(AppModel)
+	if cmd == nil || !m.status.progress.seen {
+		t.Fatal("status should take progress updates while the log is open")
+	}
+
+	newModel, _ = m.Update(transferDoneMsg{result: operationDoneMsg{"push", nil}})
+	m = newModel.(AppModel)
+	if m.status.runningOp != "" {
+		t.Errorf("status should finish the push while the log is open, runningOp = %q", m.status.runningOp)
+	}
+	if m.mode != viewLog {
+		t.Errorf("mode = %v, want viewLog", m.mode)
+	}
+}
+
 func TestAppModelNavigateToAllDiffs(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
@@ -134,7 +190,7 @@ func TestAppModelNavigateToAllDiffs(t *testing.T) {
 }
 
 func TestAppModelNavigateToFileDiff(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 	m.status.items = []StatusItem{
 		{File: git.FileStatus{Path: "test.txt"}, Section: "unstaged"},
@@ -154,8 +210,72 @@ func TestAppModelNavigateToFileDiff(t *testing.T) {
 	}
 }
 
+func TestAppModelNavigateToConflict(t *testing.T) {
+	m := NewAppModel(nil)
+	m.mode = viewStatus
+	m.status.items = []StatusItem{
+		{File: git.FileStatus{Path: "conflict.txt", IndexStatus: 'U', WorkStatus: 'U'}, Section: "conflicted"},
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m = newModel.(AppModel)
+
+	if m.mode != viewConflict {
+		t.Errorf("mode = %v, want viewConflict", m.mode)
+	}
+	if m.conflict.path != "conflict.txt" {
+		t.Errorf("conflict path = %q, want conflict.txt", m.conflict.path)
+	}
+	if cmd == nil {
+		t.Error("should return commands for entering conflict view")
+	}
+}
+
+func TestAppModelBackFromConflict(t *testing.T) {
+	m := NewAppModel(nil)
+	m.mode = viewConflict
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	m = newModel.(AppModel)
+
+	if m.mode != viewStatus {
+		t.Errorf("mode = %v, want viewStatus", m.mode)
+	}
+	if cmd == nil {
+		t.Error("should return commands for exiting to status")
+	}
+}
+
+func TestAppModelConflictResolvedReturnsToStatus(t *testing.T) {
+	m := NewAppModel(nil)
+	m.mode = viewConflict
+
+	newModel, _ := m.Update(conflictWrittenMsg{resolved: false})
+	m = newModel.(AppModel)
+	if m.mode != viewConflict {
+		t.Fatal("a partial resolution should stay in the conflict view")
+	}
+
+	// A choice made during the write is written before leaving
+	m.conflict = loadedConflictModel()
+	m.conflict.writing = true
+	m.conflict.dirty = true
+	newModel, _ = m.Update(conflictWrittenMsg{resolved: true})
+	m = newModel.(AppModel)
+	if m.mode != viewConflict {
+		t.Fatal("pending choices should be written before returning to status")
+	}
+
+	m.conflict = ConflictModel{}
+	newModel, cmd := m.Update(conflictWrittenMsg{resolved: true})
+	m = newModel.(AppModel)
+	if m.mode != viewStatus || cmd == nil {
+		t.Error("a fully resolved file should return to status")
+	}
+}
+
 func TestAppModelNavigateToFileDiffNoFiles(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 	m.status.items = nil
 
@@ -171,7 +291,7 @@ func TestAppModelNavigateToFileDiffNoFiles(t *testing.T) {
 }
 
 func TestAppModelBackFromFileDiff(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewFileDiff
 	m.diff.viewingHunk = false
 
@@ -187,7 +307,7 @@ func TestAppModelBackFromFileDiff(t *testing.T) {
 }
 
 func TestAppModelBackFromFullDiff(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewFullDiff
 	m.diff.viewingHunk = false
 
@@ -203,7 +323,7 @@ func TestAppModelBackFromFullDiff(t *testing.T) {
 }
 
 func TestAppModelBackFromBranches(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewBranches
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
@@ -218,7 +338,7 @@ func TestAppModelBackFromBranches(t *testing.T) {
 }
 
 func TestAppModelQuitFromBranchesGoesBack(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewBranches
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
@@ -233,7 +353,7 @@ func TestAppModelQuitFromBranchesGoesBack(t *testing.T) {
 }
 
 func TestAppModelBackFromStashes(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStashes
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
@@ -248,7 +368,7 @@ func TestAppModelBackFromStashes(t *testing.T) {
 }
 
 func TestAppModelQuitFromStashesGoesBack(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStashes
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
@@ -263,7 +383,7 @@ func TestAppModelQuitFromStashesGoesBack(t *testing.T) {
 }
 
 func TestAppModelBackFromLog(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewLog
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
@@ -278,7 +398,7 @@ func TestAppModelBackFromLog(t *testing.T) {
 }
 
 func TestAppModelQuitFromLogGoesBack(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewLog
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
@@ -293,7 +413,7 @@ func TestAppModelQuitFromLogGoesBack(t *testing.T) {
 }
 
 func TestAppModelLogKeyToggle(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewLog
 
 	// Pressing 'o' again should go back
@@ -309,7 +429,7 @@ func TestAppModelLogKeyToggle(t *testing.T) {
 }
 
 func TestAppModelNavigationBlockedInInputModes(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 	m.status.commitMode = true
 
@@ -322,7 +442,7 @@ func TestAppModelNavigationBlockedInInputModes(t *testing.T) {
 }
 
 func TestAppModelNavigationBlockedInStashMode(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 	m.status.stashMode = stashFiles
 
@@ -335,7 +455,7 @@ func TestAppModelNavigationBlockedInStashMode(t *testing.T) {
 }
 
 func TestAppModelNavigationBlockedInConfirmMode(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 	m.status.confirmMode = confirmDiscard
 
@@ -348,7 +468,7 @@ func TestAppModelNavigationBlockedInConfirmMode(t *testing.T) {
 }
 
 func TestAppModelDrillDownToStashDiff(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStashes
 	m.stashes.stashes = []git.Stash{
 		{Index: 0, Message: "test stash"},
@@ -366,7 +486,7 @@ func TestAppModelDrillDownToStashDiff(t *testing.T) {
 }
 
 func TestAppModelBackFromStashDiff(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStashDiff
 	m.stashes.diffModel.viewingHunk = false
 
@@ -379,7 +499,7 @@ func TestAppModelBackFromStashDiff(t *testing.T) {
 }
 
 func TestAppModelBackFromStashDiffHunkDetail(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStashDiff
 	m.stashes.diffModel.viewingHunk = true
 
@@ -396,7 +516,7 @@ func TestAppModelBackFromStashDiffHunkDetail(t *testing.T) {
 }
 
 func TestAppModelQuitFromStashDiff(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStashDiff
 	m.stashes.diffModel.viewingHunk = false
 
@@ -409,7 +529,7 @@ func TestAppModelQuitFromStashDiff(t *testing.T) {
 }
 
 func TestAppModelDiffViewHunkDetailBack(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewFileDiff
 	m.diff.viewingHunk = true
 	m.diff.hunks = []git.Hunk{
@@ -428,7 +548,7 @@ func TestAppModelDiffViewHunkDetailBack(t *testing.T) {
 }
 
 func TestAppModelDiffViewSingleHunkBack(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewFileDiff
 	m.diff.viewingHunk = true
 	m.diff.hunks = []git.Hunk{
@@ -451,30 +571,30 @@ func TestAppModelDiffViewSingleHunkBack(t *testing.T) {
 
 func TestAppModelBranchesBackBlockedInModes(t *testing.T) {
 	tests := []struct {
-		name    string
-		setup   func(*BranchesModel)
+		name  string
+		setup func(*BranchesModel)
 	}{
 		{
-			name: "showHelp",
+			name:  "showHelp",
 			setup: func(m *BranchesModel) { m.showHelp = true },
 		},
 		{
-			name: "deleteConfirmMode",
+			name:  "deleteConfirmMode",
 			setup: func(m *BranchesModel) { m.deleteConfirmMode = true },
 		},
 		{
-			name: "inputMode",
+			name:  "inputMode",
 			setup: func(m *BranchesModel) { m.inputMode = true },
 		},
 		{
-			name: "forceDeleteMode",
+			name:  "forceDeleteMode",
 			setup: func(m *BranchesModel) { m.forceDeleteMode = true },
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := NewAppModel()
+			m := NewAppModel(nil)
 			m.mode = viewBranches
 			tt.setup(&m.branches)
 
@@ -490,22 +610,22 @@ func TestAppModelBranchesBackBlockedInModes(t *testing.T) {
 
 func TestAppModelStashesBackBlockedInModes(t *testing.T) {
 	tests := []struct {
-		name    string
-		setup   func(*StashesModel)
+		name  string
+		setup func(*StashesModel)
 	}{
 		{
-			name: "showHelp",
+			name:  "showHelp",
 			setup: func(m *StashesModel) { m.showHelp = true },
 		},
 		{
-			name: "confirmMode",
+			name:  "confirmMode",
 			setup: func(m *StashesModel) { m.confirmMode = true },
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := NewAppModel()
+			m := NewAppModel(nil)
 			m.mode = viewStashes
 			tt.setup(&m.stashes)
 
@@ -520,7 +640,7 @@ func TestAppModelStashesBackBlockedInModes(t *testing.T) {
 }
 
 func TestAppModelView(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.status.status = &git.StatusResult{}
 	m.status.branchStatus = git.BranchStatus{Name: "main"}
 
@@ -533,7 +653,7 @@ func TestAppModelView(t *testing.T) {
 }
 
 func TestAppModelViewBranches(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewBranches
 	m.branches.branches = []git.Branch{{Name: "main"}}
 
@@ -545,7 +665,7 @@ func TestAppModelViewBranches(t *testing.T) {
 }
 
 func TestAppModelViewStashes(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStashes
 	m.stashes.stashes = []git.Stash{{Index: 0, Message: "test"}}
 
@@ -557,7 +677,7 @@ func TestAppModelViewStashes(t *testing.T) {
 }
 
 func TestAppModelViewStashDiff(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStashDiff
 	m.stashes.diffModel = NewStashDiffModel(100, 50)
 
@@ -570,22 +690,23 @@ func TestAppModelViewStashDiff(t *testing.T) {
 }
 
 func TestAppModelViewLog(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewLog
-	m.log = NewLogModelWithSize(100, 50)
-	m.log.lines = []string{"commit abc123"}
+	m.log = NewLogModelWithSize(nil, 100, 50)
+	m.log.loaded = true
+	m.log.commits = []git.Commit{{Hash: "abc1234", Subject: "Initial commit"}}
 
 	view := m.View()
 
-	if !strings.Contains(view, "commit") {
+	if !strings.Contains(view, "Initial commit") {
 		t.Error("view should show log content")
 	}
 }
 
 func TestAppModelViewDiff(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewFileDiff
-	m.diff = NewDiffModel(nil)
+	m.diff = NewDiffModel(nil, nil)
 	m.diff.diff = &git.CombinedDiffResult{}
 
 	view := m.View()
@@ -597,7 +718,7 @@ func TestAppModelViewDiff(t *testing.T) {
 }
 
 func TestAppModelEscapeKey(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewBranches
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
@@ -612,7 +733,7 @@ func TestAppModelEscapeKey(t *testing.T) {
 }
 
 func TestAppModelArrowKeyNavigation(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 	m.status.items = []StatusItem{
 		{File: git.FileStatus{Path: "test.txt"}, Section: "unstaged"},
@@ -631,7 +752,7 @@ func TestAppModelArrowKeyNavigation(t *testing.T) {
 }
 
 func TestAppModelEnterKeyNavigation(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewStatus
 	m.status.items = []StatusItem{
 		{File: git.FileStatus{Path: "test.txt"}, Section: "unstaged"},
@@ -650,7 +771,7 @@ func TestAppModelEnterKeyNavigation(t *testing.T) {
 }
 
 func TestAppModelLeftKeyBack(t *testing.T) {
-	m := NewAppModel()
+	m := NewAppModel(nil)
 	m.mode = viewLog
 
 	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyLeft})
@@ -668,7 +789,11 @@ func TestRefreshStatus(t *testing.T) {
 	// This test would require mocking git operations
 	// Just verify it doesn't panic when git commands fail
 	// In a real git repo, it would work
-	msg := refreshStatus()
+	repo, err := git.Open(context.Background(), ".")
+	if err != nil {
+		t.Skip("not running inside a git repository")
+	}
+	msg := NewStatusModel(repo).refreshStatus()
 
 	// Should return either statusMsg or errMsg
 	switch msg.(type) {
@@ -681,6 +806,41 @@ func TestRefreshStatus(t *testing.T) {
 	}
 }
 
+func TestRefreshStatusWithFakeExecutor(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("status --porcelain=v2 -z --branch", git.Result{Stdout: strings.Join([]string{
+		"# branch.oid 1111111111111111111111111111111111111111",
+		"# branch.head main",
+		"# branch.upstream origin/main",
+		"# branch.ab +2 -0",
+		"1 M. N... 100644 100644 100644 aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb staged.txt",
+		"? new.txt",
+		"",
+	}, "\x00")})
+
+	msg := NewStatusModel(repo).refreshStatus()
+
+	status, ok := msg.(statusMsg)
+	if !ok {
+		t.Fatalf("expected statusMsg, got %T", msg)
+	}
+	if len(status.status.Staged) != 1 || status.status.Staged[0].Path != "staged.txt" {
+		t.Errorf("unexpected staged files: %+v", status.status.Staged)
+	}
+	if len(status.status.Untracked) != 1 {
+		t.Errorf("expected 1 untracked file, got %d", len(status.status.Untracked))
+	}
+	if status.branchStatus.Name != "main" {
+		t.Errorf("branch = %q, want main", status.branchStatus.Name)
+	}
+	if status.branchStatus.Remote != "origin/main" || status.branchStatus.Ahead != 2 {
+		t.Errorf("unexpected tracking status: %+v", status.branchStatus)
+	}
+	if len(fake.calls) != 2 {
+		t.Errorf("expected a single status call after open, got %d calls", len(fake.calls))
+	}
+}
+
 func TestFileFilter(t *testing.T) {
 	filter := FileFilter{
 		Path:       "test.txt",
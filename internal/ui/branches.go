@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -12,6 +14,7 @@ import (
 
 // BranchesModel is the bubbletea model for the branches tab
 type BranchesModel struct {
+	repo                *git.Repo
 	branches            []git.Branch
 	cursor              int
 	scrollOffset        int
@@ -30,12 +33,12 @@ type BranchesModel struct {
 }
 
 // NewBranchesModel creates a new branches model
-func NewBranchesModel() BranchesModel {
-	return NewBranchesModelWithOptions(false)
+func NewBranchesModel(repo *git.Repo) BranchesModel {
+	return NewBranchesModelWithOptions(repo, false)
 }
 
 // NewBranchesModelWithOptions creates a new branches model with options
-func NewBranchesModelWithOptions(showVerboseHelp bool) BranchesModel {
+func NewBranchesModelWithOptions(repo *git.Repo, showVerboseHelp bool) BranchesModel {
 	ti := textinput.New()
 	ti.Placeholder = "New branch name"
 	ti.CharLimit = 100
@@ -47,6 +50,7 @@ func NewBranchesModelWithOptions(showVerboseHelp bool) BranchesModel {
 	di.Width = 40
 
 	return BranchesModel{
+		repo:            repo,
 		branchInput:     ti,
 		deleteInput:     di,
 		showVerboseHelp: showVerboseHelp,
@@ -55,11 +59,11 @@ func NewBranchesModelWithOptions(showVerboseHelp bool) BranchesModel {
 
 // Init initializes the model
 func (m BranchesModel) Init() tea.Cmd {
-	return refreshBranches
+	return m.refreshBranches
 }
 
-func refreshBranches() tea.Msg {
-	branches, err := git.GetBranches()
+func (m BranchesModel) refreshBranches() tea.Msg {
+	branches, err := m.repo.GetBranches(context.Background())
 	if err != nil {
 		return errMsg{err}
 	}
@@ -232,8 +236,11 @@ func (m BranchesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case branchDeleteFailedMsg:
 		m.err = msg.err
-		m.pendingDeleteBranch = msg.branchName
-		m.forceDeleteMode = true
+		// Offer a force delete only when git refused because of unmerged commits
+		if errors.Is(msg.err, git.ErrNotFullyMerged) {
+			m.pendingDeleteBranch = msg.branchName
+			m.forceDeleteMode = true
+		}
 		return m, nil
 
 	case errMsg:
@@ -246,21 +253,21 @@ func (m BranchesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m BranchesModel) doCheckoutBranch(name string) tea.Cmd {
 	return func() tea.Msg {
-		err := git.CheckoutBranch(name)
+		err := m.repo.CheckoutBranch(context.Background(), name)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshBranches()
+		return m.refreshBranches()
 	}
 }
 
 func (m BranchesModel) doCreateBranch(name string) tea.Cmd {
 	return func() tea.Msg {
-		err := git.CreateBranch(name)
+		err := m.repo.CreateBranch(context.Background(), name)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshBranches()
+		return m.refreshBranches()
 	}
 }
 
@@ -270,15 +277,11 @@ func (m BranchesModel) doDeleteBranch() tea.Cmd {
 	}
 	branch := m.branches[m.cursor]
 	return func() tea.Msg {
-		err := git.DeleteBranch(branch.Name)
+		err := m.repo.DeleteBranch(context.Background(), branch.Name)
 		if err != nil {
-			// Check if the error is about unmerged branch
-			if strings.Contains(err.Error(), "not fully merged") {
-				return branchDeleteFailedMsg{branchName: branch.Name, err: err}
-			}
-			return errMsg{err}
+			return branchDeleteFailedMsg{branchName: branch.Name, err: err}
 		}
-		return refreshBranches()
+		return m.refreshBranches()
 	}
 }
 
@@ -288,11 +291,11 @@ func (m BranchesModel) doForceDeleteBranch() tea.Cmd {
 	}
 	branchName := m.pendingDeleteBranch
 	return func() tea.Msg {
-		err := git.ForceDeleteBranch(branchName)
+		err := m.repo.ForceDeleteBranch(context.Background(), branchName)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshBranches()
+		return m.refreshBranches()
 	}
 }
 
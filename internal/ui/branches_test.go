@@ -11,7 +11,7 @@ import (
 )
 
 func TestNewBranchesModel(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 
 	if m.cursor != 0 {
 		t.Errorf("cursor = %d, want 0", m.cursor)
@@ -31,7 +31,7 @@ func TestNewBranchesModel(t *testing.T) {
 }
 
 func TestBranchesModelInit(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	cmd := m.Init()
 
 	if cmd == nil {
@@ -40,7 +40,7 @@ func TestBranchesModelInit(t *testing.T) {
 }
 
 func TestBranchesModelNavigation(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 		{Name: "feature-1"},
@@ -79,7 +79,7 @@ func TestBranchesModelNavigation(t *testing.T) {
 }
 
 func TestBranchesModelNavigationBounds(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main"},
 		{Name: "feature"},
@@ -104,7 +104,7 @@ func TestBranchesModelNavigationBounds(t *testing.T) {
 }
 
 func TestBranchesModelHelpToggle(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 
 	// Toggle help
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
@@ -122,7 +122,7 @@ func TestBranchesModelHelpToggle(t *testing.T) {
 }
 
 func TestBranchesModelInputMode(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 
 	// Press n to enter input mode
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
@@ -140,7 +140,7 @@ func TestBranchesModelInputMode(t *testing.T) {
 }
 
 func TestBranchesModelInputModeEnter(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.inputMode = true
 	m.branchInput.SetValue("new-branch")
 
@@ -156,7 +156,7 @@ func TestBranchesModelInputModeEnter(t *testing.T) {
 }
 
 func TestBranchesModelInputModeEnterEmpty(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.inputMode = true
 	m.branchInput.SetValue("")
 
@@ -172,7 +172,7 @@ func TestBranchesModelInputModeEnterEmpty(t *testing.T) {
 }
 
 func TestBranchesModelDeleteConfirmMode(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 		{Name: "feature"},
@@ -195,7 +195,7 @@ func TestBranchesModelDeleteConfirmMode(t *testing.T) {
 }
 
 func TestBranchesModelDeleteConfirmModeOnCurrentBranch(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 	}
@@ -210,7 +210,7 @@ func TestBranchesModelDeleteConfirmModeOnCurrentBranch(t *testing.T) {
 }
 
 func TestBranchesModelDeleteConfirmWithTyping(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 		{Name: "feature"},
@@ -231,7 +231,7 @@ func TestBranchesModelDeleteConfirmWithTyping(t *testing.T) {
 }
 
 func TestBranchesModelDeleteConfirmWrongName(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 		{Name: "feature"},
@@ -252,7 +252,7 @@ func TestBranchesModelDeleteConfirmWrongName(t *testing.T) {
 }
 
 func TestBranchesModelForceDeleteMode(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.forceDeleteMode = true
 	m.pendingDeleteBranch = "feature"
 
@@ -269,7 +269,7 @@ func TestBranchesModelForceDeleteMode(t *testing.T) {
 }
 
 func TestBranchesModelForceDeleteModeCancel(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.forceDeleteMode = true
 	m.pendingDeleteBranch = "feature"
 
@@ -286,7 +286,7 @@ func TestBranchesModelForceDeleteModeCancel(t *testing.T) {
 }
 
 func TestBranchesModelCheckout(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 		{Name: "feature"},
@@ -303,7 +303,7 @@ func TestBranchesModelCheckout(t *testing.T) {
 }
 
 func TestBranchesModelCheckoutCurrentBranch(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 	}
@@ -318,7 +318,7 @@ func TestBranchesModelCheckoutCurrentBranch(t *testing.T) {
 }
 
 func TestBranchesModelWindowResize(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
 	m = newModel.(BranchesModel)
@@ -332,7 +332,7 @@ func TestBranchesModelWindowResize(t *testing.T) {
 }
 
 func TestBranchesModelBranchesMsg(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 
 	branches := []git.Branch{
 		{Name: "main", IsCurrent: true},
@@ -353,7 +353,7 @@ func TestBranchesModelBranchesMsg(t *testing.T) {
 }
 
 func TestBranchesModelBranchesMsgCursorOnCurrent(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 
 	branches := []git.Branch{
 		{Name: "feature-1"},
@@ -371,11 +371,11 @@ func TestBranchesModelBranchesMsgCursorOnCurrent(t *testing.T) {
 }
 
 func TestBranchesModelBranchDeleteFailedMsg(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 
 	msg := branchDeleteFailedMsg{
 		branchName: "feature",
-		err:        fmt.Errorf("not fully merged"),
+		err:        fmt.Errorf("git branch -d feature: %w", git.ErrNotFullyMerged),
 	}
 
 	newModel, _ := m.Update(msg)
@@ -392,8 +392,27 @@ func TestBranchesModelBranchDeleteFailedMsg(t *testing.T) {
 	}
 }
 
+func TestBranchesModelBranchDeleteFailedOtherError(t *testing.T) {
+	m := NewBranchesModel(nil)
+
+	msg := branchDeleteFailedMsg{
+		branchName: "feature",
+		err:        fmt.Errorf("error: cannot delete branch 'feature' used by worktree"),
+	}
+
+	newModel, _ := m.Update(msg)
+	m = newModel.(BranchesModel)
+
+	if m.forceDeleteMode {
+		t.Error("should not offer force delete for errors other than ErrNotFullyMerged")
+	}
+	if m.err == nil {
+		t.Error("err should be set")
+	}
+}
+
 func TestBranchesModelErrMsg(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 
 	newModel, _ := m.Update(errMsg{err: fmt.Errorf("test error")})
 	m = newModel.(BranchesModel)
@@ -404,7 +423,7 @@ func TestBranchesModelErrMsg(t *testing.T) {
 }
 
 func TestBranchesModelView(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true, LastCommit: "Initial commit"},
 		{Name: "feature", LastCommit: "Add feature"},
@@ -424,7 +443,7 @@ func TestBranchesModelView(t *testing.T) {
 }
 
 func TestBranchesModelViewEmpty(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = nil
 
 	view := m.View()
@@ -435,7 +454,7 @@ func TestBranchesModelViewEmpty(t *testing.T) {
 }
 
 func TestBranchesModelViewWithError(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.err = fmt.Errorf("test error")
 	m.branches = []git.Branch{{Name: "main"}}
 
@@ -447,7 +466,7 @@ func TestBranchesModelViewWithError(t *testing.T) {
 }
 
 func TestBranchesModelViewHelp(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.showHelp = true
 
 	view := m.View()
@@ -458,7 +477,7 @@ func TestBranchesModelViewHelp(t *testing.T) {
 }
 
 func TestBranchesModelViewInputMode(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{{Name: "main"}}
 	m.inputMode = true
 	m.branchInput.Focus()
@@ -471,7 +490,7 @@ func TestBranchesModelViewInputMode(t *testing.T) {
 }
 
 func TestBranchesModelViewDeleteConfirmMode(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 		{Name: "feature"},
@@ -488,7 +507,7 @@ func TestBranchesModelViewDeleteConfirmMode(t *testing.T) {
 }
 
 func TestBranchesModelViewForceDeleteMode(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{{Name: "main"}}
 	m.forceDeleteMode = true
 	m.pendingDeleteBranch = "feature"
@@ -502,7 +521,7 @@ func TestBranchesModelViewForceDeleteMode(t *testing.T) {
 }
 
 func TestBranchesModelViewWithTracking(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true, Upstream: "origin/main", Ahead: 2, Behind: 1},
 	}
@@ -515,7 +534,7 @@ func TestBranchesModelViewWithTracking(t *testing.T) {
 }
 
 func TestBranchesModelViewTruncatesLongCommit(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	longMessage := strings.Repeat("a", 100)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true, LastCommit: longMessage},
@@ -530,7 +549,7 @@ func TestBranchesModelViewTruncatesLongCommit(t *testing.T) {
 }
 
 func TestBranchesModelArrowKeys(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main"},
 		{Name: "feature"},
@@ -552,7 +571,7 @@ func TestBranchesModelArrowKeys(t *testing.T) {
 }
 
 func TestBranchesModelEnterKey(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main", IsCurrent: true},
 		{Name: "feature"},
@@ -567,7 +586,7 @@ func TestBranchesModelEnterKey(t *testing.T) {
 }
 
 func TestBranchesModelHelpModeBlocksNavigation(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.branches = []git.Branch{
 		{Name: "main"},
 		{Name: "feature"},
@@ -583,7 +602,7 @@ func TestBranchesModelHelpModeBlocksNavigation(t *testing.T) {
 }
 
 func TestBranchesModelInputModeTyping(t *testing.T) {
-	m := NewBranchesModel()
+	m := NewBranchesModel(nil)
 	m.inputMode = true
 	m.branchInput.Focus()
 
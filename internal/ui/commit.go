@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxCommitBodyLines limits how much of the message is shown above the hunks
+const maxCommitBodyLines = 8
+
+// CommitModel is the bubbletea model for a single commit: its metadata
+// above a read-only hunk list of the changes it introduced
+type CommitModel struct {
+	repo     *git.Repo
+	commit   git.Commit
+	upstream string // upstream of the current branch, styled as in the log
+	diff     DiffModel
+	width    int
+	height   int
+}
+
+// NewCommitModel creates a commit detail model. upstream is the current
+// branch's upstream, as the log view has it.
+func NewCommitModel(repo *git.Repo, commit git.Commit, upstream string, width, height int) CommitModel {
+	m := CommitModel{
+		repo:     repo,
+		commit:   commit,
+		upstream: upstream,
+		diff:     DiffModel{repo: repo, readOnly: true},
+	}
+	m.setSize(width, height)
+	return m
+}
+
+// Init initializes the model
+func (m CommitModel) Init() tea.Cmd {
+	return m.refreshCommitDiff
+}
+
+func (m CommitModel) refreshCommitDiff() tea.Msg {
+	diff, err := m.repo.GetCommitDiff(context.Background(), m.commit.Hash)
+	if err != nil {
+		return errMsg{err}
+	}
+	return combinedDiffMsg{diff}
+}
+
+// setSize sizes the model, leaving the hunk view what's left under the header
+func (m *CommitModel) setSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.diff.width = width
+	m.diff.height = max(height-strings.Count(m.renderHeader(), "\n"), 0)
+}
+
+// AtTop returns true if the hunk list is showing, so going back leaves
+// the commit rather than a hunk or the help
+func (m CommitModel) AtTop() bool {
+	d := m.diff
+	if d.showHelp || d.viewingFullDiff {
+		return false
+	}
+	// A single hunk opens straight into the detail view
+	return !d.viewingHunk || len(d.hunks) == 1
+}
+
+// Update handles messages
+func (m CommitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.setSize(msg.Width, msg.Height)
+		return m, nil
+	}
+	newDiff, cmd := m.diff.Update(msg)
+	m.diff = newDiff.(DiffModel)
+	return m, cmd
+}
+
+// View renders the model
+func (m CommitModel) View() string {
+	if m.diff.showHelp {
+		return m.diff.View()
+	}
+	return m.renderHeader() + m.diff.View()
+}
+
+// renderHeader renders the commit metadata and message, git show style
+func (m CommitModel) renderHeader() string {
+	var sb strings.Builder
+	c := m.commit
+
+	sb.WriteString(StyleCommitHash.Render("commit " + c.Hash))
+	if len(c.Refs) > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(renderRefs(c.Refs, m.upstream))
+	}
+	sb.WriteString("\n")
+	if c.IsMerge() {
+		parents := make([]string, len(c.Parents))
+		for i, p := range c.Parents {
+			parents[i] = git.Commit{Hash: p}.ShortHash()
+		}
+		sb.WriteString(StyleMuted.Render("Merge:  " + strings.Join(parents, " ")))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(StyleMuted.Render(fmt.Sprintf("Author: %s <%s>", c.AuthorName, c.AuthorEmail)))
+	sb.WriteString("\n")
+	sb.WriteString(StyleMuted.Render("Date:   " + c.AuthorDate.Format("Mon Jan 2 15:04:05 2006 -0700")))
+	sb.WriteString("\n")
+	if c.CommitterName != c.AuthorName || c.CommitterEmail != c.AuthorEmail {
+		sb.WriteString(StyleMuted.Render(fmt.Sprintf("Commit: %s <%s>", c.CommitterName, c.CommitterEmail)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(StyleMuted.Render("Sign:   ") + renderSignature(c.Signature))
+	sb.WriteString("\n")
+
+	sb.WriteString("\n    ")
+	sb.WriteString(c.Subject)
+	sb.WriteString("\n")
+	if c.Body != "" {
+		lines := strings.Split(c.Body, "\n")
+		sb.WriteString("\n")
+		for i, line := range lines {
+			if i == maxCommitBodyLines {
+				sb.WriteString(StyleMuted.Render(fmt.Sprintf("    ... %d more lines", len(lines)-i)))
+				sb.WriteString("\n")
+				break
+			}
+			sb.WriteString("    " + line)
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString(StyleMuted.Render("───────────────────────────────────────────────────────────────"))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
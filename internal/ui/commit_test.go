@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const commitDiffOutput = `diff --git a/a.txt b/a.txt
+index 5626abf..f719efd 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1 +1 @@
+-one
++two
+diff --git a/b.txt b/b.txt
+new file mode 100644
+index 0000000..3e75765
+--- /dev/null
++++ b/b.txt
+@@ -0,0 +1 @@
++new
+`
+
+func testCommit() git.Commit {
+	return git.Commit{
+		Hash:           "0123456789abcdef0123456789abcdef01234567",
+		Parents:        []string{"89abcdef0123456789abcdef0123456789abcdef"},
+		AuthorName:     "Test User",
+		AuthorEmail:    "test@example.com",
+		AuthorDate:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		CommitterName:  "Test User",
+		CommitterEmail: "test@example.com",
+		Subject:        "Change a, add b",
+		Body:           "Explains why.",
+		Refs:           []git.Ref{{Name: "main", Kind: git.RefBranch, Head: true}},
+	}
+}
+
+// loadedCommitModel returns a commit model with its diff loaded
+func loadedCommitModel(t *testing.T) (CommitModel, *fakeExecutor) {
+	t.Helper()
+	repo, fake := newFakeRepo(t)
+	commit := testCommit()
+	fake.on("show --format= --patch --diff-merges=first-parent "+commit.Hash, git.Result{Stdout: commitDiffOutput})
+
+	m := NewCommitModel(repo, commit, "", 100, 60)
+	newModel, _ := m.Update(m.Init()())
+	return newModel.(CommitModel), fake
+}
+
+func TestCommitModelLoadsDiff(t *testing.T) {
+	m, _ := loadedCommitModel(t)
+
+	if len(m.diff.hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(m.diff.hunks))
+	}
+	if m.diff.viewingHunk {
+		t.Error("should show the hunk list when there are several hunks")
+	}
+
+	view := m.View()
+	for _, want := range []string{
+		"commit " + testCommit().Hash,
+		"(HEAD -> main)",
+		"Author: Test User <test@example.com>",
+		"Sign:   · not signed",
+		"Change a, add b",
+		"Explains why.",
+		"a.txt +1 -1",
+		"b.txt +1 -0",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+	if strings.Contains(view, "[U]") || strings.Contains(view, "[Unstaged]") {
+		t.Error("committed hunks should not carry stage labels")
+	}
+	if lines := strings.Count(view, "\n"); lines > 60 {
+		t.Errorf("view should fit the height, got %d lines", lines)
+	}
+}
+
+func TestCommitModelIgnoresStagingKeys(t *testing.T) {
+	m, fake := loadedCommitModel(t)
+
+	for _, key := range []string{" ", Keys.Stage, Keys.Unstage, Keys.Discard, Keys.Split, Keys.EditHunk} {
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = newModel.(CommitModel)
+		if cmd != nil {
+			t.Errorf("key %q should do nothing in a commit", key)
+		}
+	}
+	if m.diff.confirmMode || len(m.diff.hunks) != 2 {
+		t.Error("commit hunks should not be discarded or split")
+	}
+	for _, cmd := range fake.calls {
+		if len(cmd.Args) > 0 && cmd.Args[0] == "apply" {
+			t.Errorf("unexpected git apply: %v", cmd.Args)
+		}
+	}
+}
+
+func TestCommitModelHunkDetail(t *testing.T) {
+	m, _ := loadedCommitModel(t)
+
+	if !m.AtTop() {
+		t.Error("hunk list should be the top of the commit view")
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(CommitModel)
+	if !m.diff.viewingHunk {
+		t.Fatal("enter should open the hunk detail")
+	}
+	if m.AtTop() {
+		t.Error("going back from the hunk detail should return to the hunk list")
+	}
+	if !strings.Contains(m.View(), "a.txt @@ -1 +1 @@ ───") {
+		t.Error("hunk detail should show the file and hunk header")
+	}
+}
+
+func TestCommitModelLongBody(t *testing.T) {
+	commit := testCommit()
+	commit.Body = strings.Repeat("line\n", 20) + "last"
+	m := NewCommitModel(nil, commit, "", 100, 60)
+
+	header := m.renderHeader()
+	if !strings.Contains(header, "... 13 more lines") {
+		t.Errorf("long bodies should be truncated, got:\n%s", header)
+	}
+	if m.diff.height != 60-strings.Count(header, "\n") {
+		t.Errorf("diff height = %d, want the space under the header", m.diff.height)
+	}
+}
+
+func TestAppModelLogOpensCommit(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	commit := testCommit()
+	fake.on("show --format= --patch --diff-merges=first-parent "+commit.Hash, git.Result{Stdout: commitDiffOutput})
+
+	m := NewAppModel(repo)
+	m.mode = viewLog
+	m.log = NewLogModelWithSize(repo, 100, 60)
+	m.log.loaded = true
+	m.log.commits = []git.Commit{commit}
+	m.log.upstream = "origin/main"
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(AppModel)
+	if m.mode != viewCommit {
+		t.Fatalf("mode = %v, want viewCommit", m.mode)
+	}
+	if m.commit.upstream != "origin/main" {
+		t.Errorf("commit upstream = %q, want the log's origin/main", m.commit.upstream)
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(AppModel)
+	if len(m.commit.diff.hunks) != 2 {
+		t.Errorf("expected commit hunks to load, got %d", len(m.commit.diff.hunks))
+	}
+
+	// esc leaves the hunk detail first, then the commit
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(AppModel)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(AppModel)
+	if m.mode != viewCommit || m.commit.diff.viewingHunk {
+		t.Fatal("esc should leave the hunk detail and stay on the commit")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(AppModel)
+	if m.mode != viewLog {
+		t.Errorf("mode = %v, want viewLog", m.mode)
+	}
+}
+
+func TestAppModelQuitFromCommitGoesBack(t *testing.T) {
+	m := NewAppModel(nil)
+	m.mode = viewCommit
+	m.commit = NewCommitModel(nil, testCommit(), "", 100, 60)
+	m.commit.diff.viewingHunk = true
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = newModel.(AppModel)
+
+	if m.mode != viewLog {
+		t.Errorf("mode = %v, want viewLog (q goes back from commit)", m.mode)
+	}
+	if cmd != nil {
+		t.Error("q should not quit from the commit view")
+	}
+}
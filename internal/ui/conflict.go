@@ -0,0 +1,402 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConflictModel is the bubbletea model for resolving a conflicted file
+// block by block
+type ConflictModel struct {
+	repo             *git.Repo
+	path             string
+	file             *git.ConflictFile
+	hunks            []*git.ConflictHunk
+	cursor           int
+	listScrollOffset int
+	writing          bool // a write of the file is in flight
+	dirty            bool // blocks were resolved while writing
+	showHelp         bool
+	lastKey          string
+	err              error
+	width            int
+	height           int
+}
+
+// NewConflictModel creates a conflict resolution model for a file
+func NewConflictModel(repo *git.Repo, path string, width, height int) ConflictModel {
+	return ConflictModel{
+		repo:   repo,
+		path:   path,
+		width:  width,
+		height: height,
+	}
+}
+
+// Init initializes the model
+func (m ConflictModel) Init() tea.Cmd {
+	return m.refreshConflict
+}
+
+func (m ConflictModel) refreshConflict() tea.Msg {
+	file, err := m.repo.GetConflict(context.Background(), m.path)
+	if err != nil {
+		return errMsg{err}
+	}
+	return conflictMsg{file}
+}
+
+// Update handles messages
+func (m ConflictModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		if m.showHelp {
+			if key == Keys.Help || key == "esc" || key == Keys.Quit {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+
+		// Check for gg sequence
+		if m.lastKey == Keys.Top && key == Keys.Top {
+			m.lastKey = ""
+			m.cursor = 0
+			m.ensureCursorVisible()
+			return m, nil
+		}
+
+		if key == Keys.Top {
+			m.lastKey = Keys.Top
+			return m, nil
+		}
+		m.lastKey = ""
+
+		switch key {
+		case Keys.Quit:
+			return m, tea.Quit
+		case Keys.Help:
+			m.showHelp = true
+			return m, nil
+		case Keys.Down, "down":
+			if len(m.hunks) > 0 {
+				m.cursor = min(m.cursor+1, len(m.hunks)-1)
+				m.ensureCursorVisible()
+			}
+			return m, nil
+		case Keys.Up, "up":
+			if len(m.hunks) > 0 {
+				m.cursor = max(m.cursor-1, 0)
+				m.ensureCursorVisible()
+			}
+			return m, nil
+		case Keys.Bottom:
+			if len(m.hunks) > 0 {
+				m.cursor = len(m.hunks) - 1
+				m.ensureCursorVisible()
+			}
+			return m, nil
+		case " ":
+			if m.cursor < len(m.hunks) {
+				return m.resolve(nextResolution(m.hunks[m.cursor].Resolution))
+			}
+			return m, nil
+		case Keys.TakeOurs:
+			return m.resolve(git.ResolveOurs)
+		case Keys.TakeTheirs:
+			return m.resolve(git.ResolveTheirs)
+		case Keys.TakeBoth:
+			return m.resolve(git.ResolveBoth)
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case conflictMsg:
+		m.file = msg.file
+		m.hunks = msg.file.Hunks()
+		if m.cursor >= len(m.hunks) {
+			m.cursor = max(0, len(m.hunks)-1)
+		}
+		m.ensureCursorVisible()
+		return m, nil
+
+	case conflictWrittenMsg:
+		m.writing = false
+		switch {
+		case errors.Is(msg.err, git.ErrFileChanged):
+			// Edited outside the view: load the edits rather than overwrite them
+			m.err = fmt.Errorf("%s changed on disk and was reloaded", m.path)
+			m.dirty = false
+			return m, m.refreshConflict
+		case msg.err != nil:
+			m.err = msg.err
+			return m, nil
+		}
+		if m.file == nil {
+			return m, nil
+		}
+		m.file.Original = msg.written
+		if m.dirty {
+			return m.writeConflict()
+		}
+		return m, nil
+
+	case errMsg:
+		m.err = msg.err
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// nextResolution cycles ours -> theirs -> both for the Space key
+func nextResolution(r git.Resolution) git.Resolution {
+	switch r {
+	case git.ResolveOurs:
+		return git.ResolveTheirs
+	case git.ResolveTheirs:
+		return git.ResolveBoth
+	default:
+		return git.ResolveOurs
+	}
+}
+
+// resolve applies a resolution to the hunk under the cursor and writes the
+// file, moving on to the next unresolved hunk
+func (m ConflictModel) resolve(resolution git.Resolution) (ConflictModel, tea.Cmd) {
+	if m.file == nil || m.cursor >= len(m.hunks) {
+		return m, nil
+	}
+	m.hunks[m.cursor].Resolution = resolution
+	for i := m.cursor + 1; i < len(m.hunks); i++ {
+		if m.hunks[i].Resolution == git.Unresolved {
+			m.cursor = i
+			m.ensureCursorVisible()
+			break
+		}
+	}
+	return m.writeConflict()
+}
+
+// writeConflict writes a copy of the file as it stands, so later choices
+// don't change it mid-write. One write runs at a time; choices made while
+// it runs are written when it finishes.
+func (m ConflictModel) writeConflict() (ConflictModel, tea.Cmd) {
+	if m.writing {
+		m.dirty = true
+		return m, nil
+	}
+	m.writing = true
+	m.dirty = false
+	file := m.file.Clone()
+	return m, func() tea.Msg {
+		if err := m.repo.WriteConflict(context.Background(), file); err != nil {
+			return conflictWrittenMsg{err: err}
+		}
+		return conflictWrittenMsg{resolved: file.IsResolved(), written: file.Original}
+	}
+}
+
+// visibleHunkListLines returns the number of block list lines that can be displayed
+func (m ConflictModel) visibleHunkListLines() int {
+	reserved := 15
+	if m.height <= reserved {
+		return 10 // fallback minimum
+	}
+	available := (m.height - reserved) / 3
+	if available < 5 {
+		available = 5
+	}
+	return available
+}
+
+// ensureCursorVisible adjusts listScrollOffset to keep cursor in view
+func (m *ConflictModel) ensureCursorVisible() {
+	visible := m.visibleHunkListLines()
+	if m.cursor < m.listScrollOffset {
+		m.listScrollOffset = m.cursor
+	}
+	if m.cursor >= m.listScrollOffset+visible {
+		m.listScrollOffset = m.cursor - visible + 1
+	}
+	maxOffset := max(len(m.hunks)-visible, 0)
+	if m.listScrollOffset > maxOffset {
+		m.listScrollOffset = maxOffset
+	}
+}
+
+func (m ConflictModel) anchorBottom(content string) string {
+	lines := strings.Count(content, "\n")
+	if m.height <= lines {
+		return content
+	}
+	return strings.Repeat("\n", m.height-lines-1) + content
+}
+
+// View renders the model
+func (m ConflictModel) View() string {
+	if m.showHelp {
+		return m.renderHelp()
+	}
+
+	var sb strings.Builder
+
+	if m.err != nil {
+		sb.WriteString(StyleUnstaged.Render(fmt.Sprintf("Error: %v", m.err)))
+		sb.WriteString("\n")
+	}
+
+	if m.file == nil {
+		sb.WriteString(StyleMuted.Render("Loading..."))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	if len(m.hunks) == 0 {
+		sb.WriteString(StyleEmpty.Render("No conflict markers"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	visibleStart := m.listScrollOffset
+	visibleEnd := min(m.listScrollOffset+m.visibleHunkListLines(), len(m.hunks))
+
+	// Preview of the current block above the list
+	available := 50
+	if m.height > (visibleEnd-visibleStart)+10 {
+		available = m.height - (visibleEnd - visibleStart) - 10
+	}
+	hunk := m.hunks[m.cursor]
+	sb.WriteString(fmt.Sprintf("─── %s %s block %d of %d ───", renderResolutionLabel(hunk.Resolution), m.file.DisplayPath, m.cursor+1, len(m.hunks)))
+	sb.WriteString("\n")
+	sb.WriteString(renderConflictSide("ours", hunk.Ours, StyleDiffAdded, hunk.Resolution == git.ResolveOurs || hunk.Resolution == git.ResolveBoth, available/3))
+	sb.WriteString(renderConflictSide("base", hunk.Base, StyleDiffContext, false, available/3))
+	sb.WriteString(renderConflictSide("theirs", hunk.Theirs, StyleDiffRemoved, hunk.Resolution == git.ResolveTheirs || hunk.Resolution == git.ResolveBoth, available/3))
+	sb.WriteString("\n")
+
+	if m.listScrollOffset > 0 {
+		sb.WriteString(StyleMuted.Render(fmt.Sprintf("  ↑ %d more above", m.listScrollOffset)))
+		sb.WriteString("\n")
+	}
+
+	for i := visibleStart; i < visibleEnd; i++ {
+		h := m.hunks[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		sb.WriteString(cursor)
+		sb.WriteString(renderResolutionLabel(h.Resolution))
+		sb.WriteString(fmt.Sprintf(" block %d: ours %d, theirs %d lines", i+1, len(h.Ours), len(h.Theirs)))
+		sb.WriteString("\n")
+	}
+
+	if visibleEnd < len(m.hunks) {
+		sb.WriteString(StyleMuted.Render(fmt.Sprintf("  ↓ %d more below", len(m.hunks)-visibleEnd)))
+		sb.WriteString("\n")
+	}
+
+	resolved := 0
+	for _, h := range m.hunks {
+		if h.Resolution != git.Unresolved {
+			resolved++
+		}
+	}
+	sb.WriteString(StyleMuted.Render(fmt.Sprintf("%d of %d blocks resolved  (%s ours, %s theirs, %s both)",
+		resolved, len(m.hunks), Keys.TakeOurs, Keys.TakeTheirs, Keys.TakeBoth)))
+	sb.WriteString("\n")
+
+	return m.anchorBottom(sb.String())
+}
+
+// renderConflictSide renders one side of a conflict block, truncated to limit lines
+func renderConflictSide(label string, lines []string, style lipgloss.Style, chosen bool, limit int) string {
+	var sb strings.Builder
+	marker := " "
+	if chosen {
+		marker = "✓"
+	}
+	sb.WriteString(StyleSectionHeader.Render(fmt.Sprintf("%s %s", marker, label)))
+	sb.WriteString("\n")
+	if len(lines) == 0 {
+		sb.WriteString(StyleMuted.Render("  (empty)"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+	show := len(lines)
+	if limit > 0 && show > limit {
+		show = limit
+	}
+	for _, line := range lines[:show] {
+		sb.WriteString(style.Render("  " + line))
+		sb.WriteString("\n")
+	}
+	if show < len(lines) {
+		sb.WriteString(StyleMuted.Render(fmt.Sprintf("  ... %d more lines", len(lines)-show)))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func renderResolutionLabel(r git.Resolution) string {
+	switch r {
+	case git.ResolveOurs:
+		return StyleStaged.Render("[O]")
+	case git.ResolveTheirs:
+		return StyleStaged.Render("[T]")
+	case git.ResolveBoth:
+		return StyleStaged.Render("[B]")
+	default:
+		return StyleConflicted.Render("[ ]")
+	}
+}
+
+func (m ConflictModel) renderHelp() string {
+	var sb strings.Builder
+
+	sb.WriteString(StyleHelpTitle.Render("Conflict View Shortcuts"))
+	sb.WriteString("\n\n")
+
+	backKeys := formatKeyList(Keys.Left, "←", "ESC")
+	moveKeys := formatKeyList(Keys.Down, Keys.Up, "↓", "↑")
+	topKey := formatDoubleKey(Keys.Top)
+
+	help := []struct {
+		key  string
+		desc string
+	}{
+		{moveKeys, "Navigate blocks"},
+		{topKey, "Go to top"},
+		{Keys.Bottom, "Go to bottom"},
+		{"SPACE", "Cycle ours/theirs/both"},
+		{Keys.TakeOurs, "Take ours"},
+		{Keys.TakeTheirs, "Take theirs"},
+		{Keys.TakeBoth, "Take both (ours first)"},
+		{backKeys, "Go back"},
+		{Keys.Help, "Toggle help"},
+		{Keys.Quit, "Quit"},
+	}
+
+	for _, h := range help {
+		sb.WriteString(fmt.Sprintf("  %s  %s\n",
+			StyleHelpKey.Render(fmt.Sprintf("%-8s", h.key)),
+			StyleHelpDesc.Render(h.desc)))
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(StyleMuted.Render("Each choice is written to the file; it is staged once every block is resolved."))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const twoConflicts = "a\n<<<<<<< ours\nO1\n||||||| base\nB1\n=======\nT1\n>>>>>>> theirs\nb\n<<<<<<< ours\nO2\n||||||| base\nB2\n=======\nT2\n>>>>>>> theirs\nc\n"
+
+func loadedConflictModel() ConflictModel {
+	m := NewConflictModel(nil, "file.txt", 80, 40)
+	file := git.ParseConflictMarkers(twoConflicts)
+	file.Path = "file.txt"
+	file.DisplayPath = "file.txt"
+	newM, _ := m.Update(conflictMsg{file})
+	return newM.(ConflictModel)
+}
+
+func TestConflictModelLoad(t *testing.T) {
+	m := loadedConflictModel()
+
+	if len(m.hunks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(m.hunks))
+	}
+	view := m.View()
+	for _, want := range []string{"block 1 of 2", "O1", "B1", "T1", "0 of 2 blocks resolved"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+}
+
+func TestConflictModelNavigation(t *testing.T) {
+	m := loadedConflictModel()
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = newM.(ConflictModel)
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.cursor)
+	}
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = newM.(ConflictModel)
+	if m.cursor != 1 {
+		t.Errorf("cursor should stay at the last block, got %d", m.cursor)
+	}
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	m = newM.(ConflictModel)
+	if m.cursor != 0 {
+		t.Errorf("cursor = %d, want 0", m.cursor)
+	}
+}
+
+func TestConflictModelResolve(t *testing.T) {
+	m := loadedConflictModel()
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(">")})
+	m = newM.(ConflictModel)
+
+	if m.hunks[0].Resolution != git.ResolveTheirs {
+		t.Errorf("first block = %v, want ResolveTheirs", m.hunks[0].Resolution)
+	}
+	if m.cursor != 1 {
+		t.Errorf("cursor should advance to the next unresolved block, got %d", m.cursor)
+	}
+	if cmd == nil {
+		t.Error("resolving a block should write the file")
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	m = newM.(ConflictModel)
+
+	if !m.file.IsResolved() {
+		t.Fatal("file should be resolved after both blocks")
+	}
+	if got := m.file.Content(); got != "a\nT1\nb\nO2\nT2\nc\n" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestConflictModelWritesOneAtATime(t *testing.T) {
+	m := loadedConflictModel()
+
+	newM, first := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("<")})
+	m = newM.(ConflictModel)
+	newM, second := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(">")})
+	m = newM.(ConflictModel)
+	if first == nil || second != nil || !m.writing || !m.dirty {
+		t.Fatalf("the second choice should wait for the first write, got writing=%v dirty=%v", m.writing, m.dirty)
+	}
+
+	written := "a\nO1\nb\n<<<<<<< ours\nO2\n||||||| base\nB2\n=======\nT2\n>>>>>>> theirs\nc\n"
+	newM, next := m.Update(conflictWrittenMsg{written: written})
+	m = newM.(ConflictModel)
+	if next == nil || !m.writing || m.dirty {
+		t.Errorf("finishing the first write should start the next, got writing=%v dirty=%v", m.writing, m.dirty)
+	}
+	if m.file.Original != written {
+		t.Errorf("Original = %q, want what was written", m.file.Original)
+	}
+}
+
+func TestConflictModelChangedOnDisk(t *testing.T) {
+	m := loadedConflictModel()
+	m.writing = true
+
+	newM, cmd := m.Update(conflictWrittenMsg{err: fmt.Errorf("file.txt: %w", git.ErrFileChanged)})
+	m = newM.(ConflictModel)
+	if m.writing || cmd == nil {
+		t.Error("a file edited elsewhere should be reloaded")
+	}
+	if !strings.Contains(m.View(), "changed on disk") {
+		t.Errorf("view should say the file changed, got:\n%s", m.View())
+	}
+}
+
+func TestConflictModelSpaceCycles(t *testing.T) {
+	m := loadedConflictModel()
+
+	want := []git.Resolution{git.ResolveOurs, git.ResolveTheirs, git.ResolveBoth, git.ResolveOurs}
+	for i, w := range want {
+		// Keep the cursor on the last block so it does not advance
+		m.cursor = 1
+		newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+		m = newM.(ConflictModel)
+		if m.hunks[1].Resolution != w {
+			t.Errorf("press %d: resolution = %v, want %v", i+1, m.hunks[1].Resolution, w)
+		}
+	}
+}
+
+func TestConflictModelErrMsg(t *testing.T) {
+	m := NewConflictModel(nil, "file.txt", 80, 40)
+	newM, _ := m.Update(errMsg{err: fmt.Errorf("was deleted on one side")})
+	m = newM.(ConflictModel)
+
+	if !strings.Contains(m.View(), "Error:") {
+		t.Error("view should show the error")
+	}
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"go-on-git/internal/git"
+)
+
+// conventionalStep is the picker the Conventional Commits assistant shows
+type conventionalStep int
+
+const (
+	conventionalDone conventionalStep = iota
+	conventionalType
+	conventionalScope
+)
+
+// conventionalAssistant walks through picking a commit's type and scope
+// before the message editor opens, and builds the header from them
+type conventionalAssistant struct {
+	step    conventionalStep
+	types   []string
+	scopes  []string // scope choices; "" stands for no scope
+	cursor  int
+	header  git.ConventionalHeader
+	skipped bool // esc: write the header by hand
+}
+
+// newConventionalAssistant starts the assistant at the type picker. The
+// scopes offered are the configured ones, or else the top-level
+// directories of the staged files.
+func newConventionalAssistant(rules git.ConventionalRules, staged []git.FileStatus) conventionalAssistant {
+	scopes := rules.Scopes
+	if len(scopes) == 0 {
+		scopes = stagedScopes(staged)
+	}
+	if !rules.RequireScope {
+		scopes = append([]string{""}, scopes...)
+	}
+	return conventionalAssistant{
+		step:   conventionalType,
+		types:  rules.Types,
+		scopes: scopes,
+	}
+}
+
+// stagedScopes returns the sorted top-level directories of the staged files
+func stagedScopes(staged []git.FileStatus) []string {
+	var scopes []string
+	for _, f := range staged {
+		dir, _, found := strings.Cut(path.Clean(f.Path), "/")
+		if found && !slices.Contains(scopes, dir) {
+			scopes = append(scopes, dir)
+		}
+	}
+	slices.Sort(scopes)
+	return scopes
+}
+
+// active reports whether a picker is showing
+func (a conventionalAssistant) active() bool {
+	return a.step != conventionalDone
+}
+
+// choices returns the options of the current picker
+func (a conventionalAssistant) choices() []string {
+	if a.step == conventionalScope {
+		return a.scopes
+	}
+	return a.types
+}
+
+// update handles a key in the current picker
+func (a conventionalAssistant) update(key string) conventionalAssistant {
+	switch key {
+	case Keys.Down, "down":
+		a.cursor = min(a.cursor+1, len(a.choices())-1)
+	case Keys.Up, "up":
+		a.cursor = max(a.cursor-1, 0)
+	case "!":
+		a.header.Breaking = !a.header.Breaking
+	case "enter":
+		if a.step == conventionalType {
+			a.header.Type = a.types[a.cursor]
+			a.step, a.cursor = conventionalScope, 0
+			// Nothing to pick when the only choice is no scope
+			if len(a.scopes) == 0 || (len(a.scopes) == 1 && a.scopes[0] == "") {
+				a.step = conventionalDone
+			}
+		} else {
+			a.header.Scope = a.scopes[a.cursor]
+			a.step = conventionalDone
+		}
+	case "esc":
+		a.step = conventionalDone
+		a.skipped = true
+	}
+	return a
+}
+
+// prefix returns the header up to its description, to start the message with
+func (a conventionalAssistant) prefix() string {
+	if a.skipped {
+		return ""
+	}
+	return a.header.String()
+}
+
+// view renders the current picker, one choice per line
+func (a conventionalAssistant) view() string {
+	var sb strings.Builder
+	title := "Commit type:"
+	if a.step == conventionalScope {
+		title = "Scope for " + a.header.Type + ":"
+	}
+	sb.WriteString(title)
+	if a.header.Breaking {
+		sb.WriteString(" " + StyleConfirm.Render("breaking change"))
+	}
+	sb.WriteString(StyleMuted.Render("  (enter to pick, ! breaking change, esc to write it yourself)"))
+	sb.WriteString("\n")
+	for i, choice := range a.choices() {
+		if choice == "" {
+			choice = StyleMuted.Render("(no scope)")
+		}
+		if i == a.cursor {
+			sb.WriteString(StyleSelected.Render("> ") + choice)
+		} else {
+			sb.WriteString("  " + choice)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// lines returns the number of lines view uses
+func (a conventionalAssistant) lines() int {
+	return len(a.choices()) + 1
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStagedScopes(t *testing.T) {
+	staged := []git.FileStatus{
+		{Path: "internal/ui/status.go"},
+		{Path: "README.md"},
+		{Path: "docs/guide.md"},
+		{Path: "internal/git/git.go"},
+	}
+	if got := stagedScopes(staged); !slices.Equal(got, []string{"docs", "internal"}) {
+		t.Errorf("stagedScopes() = %v", got)
+	}
+}
+
+func TestConventionalAssistant(t *testing.T) {
+	rules := git.ConventionalRules{Enabled: true, Types: []string{"feat", "fix"}}
+	a := newConventionalAssistant(rules, []git.FileStatus{{Path: "ui/a.go"}})
+
+	for _, key := range []string{Keys.Down, "!", "enter"} {
+		a = a.update(key)
+	}
+	if a.step != conventionalScope || !strings.Contains(a.view(), "(no scope)") {
+		t.Fatalf("expected the scope picker with a no-scope choice, got:\n%s", a.view())
+	}
+	a = a.update(Keys.Down).update("enter")
+	if a.active() || a.prefix() != "fix(ui)!: " {
+		t.Errorf("prefix = %q", a.prefix())
+	}
+
+	// Without scopes to offer, picking the type is enough
+	a = newConventionalAssistant(rules, nil).update("enter")
+	if a.active() || a.prefix() != "feat: " {
+		t.Errorf("prefix = %q", a.prefix())
+	}
+
+	// A required scope has no empty choice
+	rules.RequireScope = true
+	rules.Scopes = []string{"api"}
+	a = newConventionalAssistant(rules, nil).update("enter").update("enter")
+	if a.prefix() != "feat(api): " {
+		t.Errorf("prefix = %q", a.prefix())
+	}
+
+	a = newConventionalAssistant(rules, nil).update("esc")
+	if a.active() || a.prefix() != "" {
+		t.Error("esc should leave the header to the user")
+	}
+}
+
+func TestStatusModelConventionalCommit(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("commit -m fix(ui): handle resize", git.Result{})
+	rules := git.ConventionalRules{Enabled: true, Types: []string{"feat", "fix"}}
+
+	m := stagedStatusModel(repo)
+	m.status.Staged = []git.FileStatus{{Path: "ui/app.go"}}
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Commit)})
+	m = newM.(StatusModel)
+	newM, _ = m.Update(commitSetupMsg{rules: rules})
+	m = newM.(StatusModel)
+	if !strings.Contains(m.View(), "Commit type:") {
+		t.Fatalf("commit should start with the type picker, got:\n%s", m.View())
+	}
+
+	for _, key := range []string{Keys.Down, "enter", Keys.Down, "enter"} {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if key == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		newM, _ = m.Update(msg)
+		m = newM.(StatusModel)
+	}
+	if got := m.commitInput.Value(); got != "fix(ui): " {
+		t.Fatalf("editor should start with the header, got %q", got)
+	}
+
+	// The message is checked before committing
+	m.commitInput.SetValue("fixed resize")
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newM.(StatusModel)
+	if !m.commitMode || cmd != nil || m.err == nil {
+		t.Fatal("an invalid message should keep the editor open with an error")
+	}
+
+	m.commitInput.SetValue("fix(ui): handle resize")
+	newM, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newM.(StatusModel)
+	if m.commitMode || cmd == nil {
+		t.Fatal("a valid message should be committed")
+	}
+	cmd()
+	if _, ok := fake.called("commit -m fix(ui): handle resize"); !ok {
+		t.Error("expected the conventional message to be committed")
+	}
+}
@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
 
 	"go-on-git/internal/git"
@@ -11,6 +14,7 @@ import (
 
 // DiffModel is the bubbletea model for the diff view
 type DiffModel struct {
+	repo             *git.Repo
 	diff             *git.CombinedDiffResult
 	hunks            []git.Hunk
 	cursor           int
@@ -19,9 +23,14 @@ type DiffModel struct {
 	viewingHunk      bool         // true when drilled into a single hunk
 	viewingFullDiff  bool         // true when viewing complete diff output
 	scrollOffset     int          // scroll position within hunk content or full diff
+	lineCursor       int          // line under the cursor in the hunk detail view
+	lineVisual       bool         // true when selecting a range of lines in the hunk detail view
+	lineVisualStart  int          // line where the visual range started
 	showHelp         bool
 	confirmMode      bool
 	confirmInput     string
+	editRetryPath    string // edited patch that failed to apply, offered for re-editing
+	readOnly         bool   // browsing committed changes: nothing to stage, discard or edit
 	lastKey          string
 	err              error
 	width            int
@@ -29,7 +38,7 @@ type DiffModel struct {
 }
 
 // NewDiffModel creates a new diff model
-func NewDiffModel(filterFiles []string) DiffModel {
+func NewDiffModel(repo *git.Repo, filterFiles []string) DiffModel {
 	// Convert string paths to FileFilters showing both staged and unstaged
 	filters := make([]FileFilter, 0)
 	for _, path := range filterFiles {
@@ -37,12 +46,13 @@ func NewDiffModel(filterFiles []string) DiffModel {
 		filters = append(filters, FileFilter{Path: path, ShowStaged: false})
 	}
 	return DiffModel{
+		repo:        repo,
 		filterFiles: filters,
 	}
 }
 
 // NewDiffModelWithSize creates a new diff model with known dimensions
-func NewDiffModelWithSize(filterFiles []string, width, height int) DiffModel {
+func NewDiffModelWithSize(repo *git.Repo, filterFiles []string, width, height int) DiffModel {
 	// Convert string paths to FileFilters showing both staged and unstaged
 	filters := make([]FileFilter, 0)
 	for _, path := range filterFiles {
@@ -50,6 +60,7 @@ func NewDiffModelWithSize(filterFiles []string, width, height int) DiffModel {
 		filters = append(filters, FileFilter{Path: path, ShowStaged: false})
 	}
 	return DiffModel{
+		repo:        repo,
 		filterFiles: filters,
 		width:       width,
 		height:      height,
@@ -57,8 +68,9 @@ func NewDiffModelWithSize(filterFiles []string, width, height int) DiffModel {
 }
 
 // NewDiffModelWithFilters creates a new diff model with specific file filters
-func NewDiffModelWithFilters(filters []FileFilter, width, height int) DiffModel {
+func NewDiffModelWithFilters(repo *git.Repo, filters []FileFilter, width, height int) DiffModel {
 	return DiffModel{
+		repo:        repo,
 		filterFiles: filters,
 		width:       width,
 		height:      height,
@@ -76,7 +88,7 @@ func (m DiffModel) Init() tea.Cmd {
 }
 
 func (m DiffModel) refreshCombinedDiff() tea.Msg {
-	diff, err := git.GetCombinedDiff()
+	diff, err := m.repo.GetCombinedDiff(context.Background())
 	if err != nil {
 		return errMsg{err}
 	}
@@ -97,6 +109,22 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Edited hunk didn't apply: offer to edit it again
+		if m.editRetryPath != "" {
+			switch key {
+			case "y", "Y":
+				path := m.editRetryPath
+				m.editRetryPath = ""
+				m.err = nil
+				return m, openHunkEditor(path)
+			case "n", "N", "esc":
+				os.Remove(m.editRetryPath)
+				m.editRetryPath = ""
+				return m, nil
+			}
+			return m, nil
+		}
+
 		if m.confirmMode {
 			switch key {
 			case "backspace":
@@ -108,7 +136,9 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.confirmInput == "yes" {
 					m.confirmMode = false
 					m.confirmInput = ""
-					return m, m.doDiscard()
+					cmd := m.doDiscard()
+					m.lineVisual = false
+					return m, cmd
 				}
 				return m, nil
 			case "esc":
@@ -124,6 +154,10 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if m.readOnly && isStagingKey(key) {
+			return m, nil
+		}
+
 		// Handle full diff view navigation
 		if m.viewingFullDiff {
 			switch key {
@@ -168,42 +202,60 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.viewingHunk {
 			switch key {
 			case Keys.Left, "left", "esc":
+				if m.lineVisual {
+					m.lineVisual = false
+					return m, nil
+				}
 				m.viewingHunk = false
 				m.scrollOffset = 0
+				m.lineCursor = 0
 				return m, nil
 			case Keys.Down, "down":
 				if m.cursor < len(m.hunks) {
-					maxScroll := len(m.hunks[m.cursor].Lines) - m.visibleLines()
-					if maxScroll > 0 {
-						m.scrollOffset = min(m.scrollOffset+1, maxScroll)
-					}
+					m.lineCursor = min(m.lineCursor+1, max(len(m.hunks[m.cursor].Lines)-1, 0))
+					m.ensureLineCursorVisible()
 				}
 				return m, nil
 			case Keys.Up, "up":
-				m.scrollOffset = max(m.scrollOffset-1, 0)
+				m.lineCursor = max(m.lineCursor-1, 0)
+				m.ensureLineCursorVisible()
 				return m, nil
 			case Keys.Top:
 				if m.lastKey == Keys.Top {
 					m.lastKey = ""
-					m.scrollOffset = 0
+					m.lineCursor = 0
+					m.ensureLineCursorVisible()
 					return m, nil
 				}
 				m.lastKey = Keys.Top
 				return m, nil
 			case Keys.Bottom:
 				if m.cursor < len(m.hunks) {
-					maxScroll := len(m.hunks[m.cursor].Lines) - m.visibleLines()
-					if maxScroll > 0 {
-						m.scrollOffset = maxScroll
-					}
+					m.lineCursor = max(len(m.hunks[m.cursor].Lines)-1, 0)
+					m.ensureLineCursorVisible()
 				}
 				return m, nil
+			case Keys.Split:
+				m.splitHunk()
+				return m, nil
+			case Keys.EditHunk:
+				return m, m.editHunk()
+			case Keys.Visual:
+				m.lineVisual = !m.lineVisual
+				m.lineVisualStart = m.lineCursor
+				return m, nil
 			case " ":
-				return m, m.toggleStage()
+				cmd := m.toggleStage()
+				m.lineVisual = false
+				return m, cmd
 			case Keys.Stage:
-				return m, m.stageHunk()
+				cmd := m.stageHunk()
+				m.lineVisual = false
+				return m, cmd
 			case Keys.Unstage:
-				return m, m.unstageHunk()
+				cmd := m.unstageHunk()
+				m.lineVisual = false
+				return m, cmd
 			case Keys.Discard:
 				if len(m.hunks) > 0 && m.cursor < len(m.hunks) && !m.hunks[m.cursor].Staged {
 					m.confirmMode = true
@@ -249,6 +301,7 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(m.hunks) > 0 && m.cursor < len(m.hunks) {
 				m.viewingHunk = true
 				m.scrollOffset = 0
+				m.lineCursor = 0
 			}
 			return m, nil
 		case Keys.Down, "down":
@@ -269,6 +322,11 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.ensureHunkCursorVisible()
 			}
 			return m, nil
+		case Keys.Split:
+			m.splitHunk()
+			return m, nil
+		case Keys.EditHunk:
+			return m, m.editHunk()
 		case " ":
 			return m, m.toggleStage()
 		case Keys.Stage:
@@ -292,6 +350,7 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.diff = msg.diff
 		newHunks := m.getFilteredHunks()
 		if len(m.hunks) > 0 && len(newHunks) > 0 {
+			newHunks = m.resplitHunks(newHunks)
 			newHunks = m.keepHunkOrder(newHunks)
 		}
 		m.hunks = newHunks
@@ -303,7 +362,25 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(m.hunks) == 1 && !m.viewingHunk {
 			m.viewingHunk = true
 			m.scrollOffset = 0
+			m.lineCursor = 0
 		}
+		if m.viewingHunk && m.cursor < len(m.hunks) {
+			m.lineCursor = min(m.lineCursor, max(len(m.hunks[m.cursor].Lines)-1, 0))
+			m.ensureLineCursorVisible()
+		}
+		return m, nil
+
+	case hunkEditedMsg:
+		if msg.err != nil {
+			os.Remove(msg.path)
+			m.err = msg.err
+			return m, nil
+		}
+		return m, m.stageEditedHunk(msg.path)
+
+	case hunkEditFailedMsg:
+		m.err = msg.err
+		m.editRetryPath = msg.path
 		return m, nil
 
 	case errMsg:
@@ -314,6 +391,67 @@ func (m DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// isStagingKey returns true for keys that change the index or working tree,
+// or only make sense when doing so
+func isStagingKey(key string) bool {
+	switch key {
+	case " ", Keys.Stage, Keys.Unstage, Keys.Discard, Keys.Split, Keys.EditHunk, Keys.Visual:
+		return true
+	}
+	return false
+}
+
+// splitHunk replaces the hunk under the cursor with its smaller pieces
+func (m *DiffModel) splitHunk() {
+	if m.cursor >= len(m.hunks) {
+		return
+	}
+	pieces := m.hunks[m.cursor].Split()
+	if len(pieces) < 2 {
+		return
+	}
+	hunks := make([]git.Hunk, 0, len(m.hunks)+len(pieces)-1)
+	hunks = append(hunks, m.hunks[:m.cursor]...)
+	hunks = append(hunks, pieces...)
+	hunks = append(hunks, m.hunks[m.cursor+1:]...)
+	m.hunks = hunks
+	m.lineCursor = 0
+	m.lineVisual = false
+	m.scrollOffset = 0
+	m.ensureHunkCursorVisible()
+}
+
+// resplitHunks splits refreshed hunks again when they contain pieces the
+// user split off before the refresh, since git diff merges them back
+func (m DiffModel) resplitHunks(newHunks []git.Hunk) []git.Hunk {
+	previous := make(map[string]bool, len(m.hunks))
+	for _, hunk := range m.hunks {
+		previous[hunkStableKey(hunk)] = true
+	}
+
+	result := make([]git.Hunk, 0, len(newHunks))
+	for _, hunk := range newHunks {
+		if !previous[hunkStableKey(hunk)] {
+			if pieces := hunk.Split(); len(pieces) > 1 && hasStableKey(pieces, previous) {
+				result = append(result, pieces...)
+				continue
+			}
+		}
+		result = append(result, hunk)
+	}
+	return result
+}
+
+// hasStableKey returns true if any of the hunks has one of the keys
+func hasStableKey(hunks []git.Hunk, keys map[string]bool) bool {
+	for _, hunk := range hunks {
+		if keys[hunkStableKey(hunk)] {
+			return true
+		}
+	}
+	return false
+}
+
 func (m DiffModel) keepHunkOrder(newHunks []git.Hunk) []git.Hunk {
 	indexByKey := make(map[string][]int, len(newHunks))
 	for i, hunk := range newHunks {
@@ -418,6 +556,40 @@ func (m *DiffModel) ensureHunkCursorVisible() {
 	}
 }
 
+// ensureLineCursorVisible adjusts scrollOffset to keep the line cursor in view
+func (m *DiffModel) ensureLineCursorVisible() {
+	visible := m.visibleLines()
+	if m.lineCursor < m.scrollOffset {
+		m.scrollOffset = m.lineCursor
+	}
+	if m.lineCursor >= m.scrollOffset+visible {
+		m.scrollOffset = m.lineCursor - visible + 1
+	}
+}
+
+// selectedLines returns the hunk line indices covered by the visual range
+func (m DiffModel) selectedLines() map[int]bool {
+	start, end := m.lineVisualStart, m.lineCursor
+	if start > end {
+		start, end = end, start
+	}
+	selected := make(map[int]bool, end-start+1)
+	for i := start; i <= end; i++ {
+		selected[i] = true
+	}
+	return selected
+}
+
+// patchFor returns the patch for the whole hunk, or only the selected lines
+// when a visual range is active in the hunk detail view. reverse must match
+// whether the patch will be applied with --reverse.
+func (m DiffModel) patchFor(hunk git.Hunk, fileDiff *git.FileDiff, reverse bool) string {
+	if m.viewingHunk && m.lineVisual {
+		return hunk.GeneratePartialPatch(fileDiff, m.selectedLines(), reverse)
+	}
+	return hunk.GeneratePatch(fileDiff)
+}
+
 func (m DiffModel) anchorBottom(content string) string {
 	lines := strings.Count(content, "\n")
 	if m.height <= lines {
@@ -460,7 +632,7 @@ func (m DiffModel) getFilteredHunks() []git.Hunk {
 
 	// Add hunks for untracked files
 	for _, path := range untrackedFiles {
-		fileDiff := git.GetUntrackedFileDiff(path)
+		fileDiff := m.repo.GetUntrackedFileDiff(context.Background(), path)
 		if fileDiff != nil {
 			for _, hunk := range fileDiff.Hunks {
 				hunk.Staged = false // Untracked files are not staged
@@ -483,20 +655,24 @@ func (m DiffModel) toggleStage() tea.Cmd {
 		return nil
 	}
 
+	patch := m.patchFor(hunk, fileDiff, hunk.Staged)
+	if patch == "" {
+		return nil
+	}
+
 	return func() tea.Msg {
-		patch := hunk.GeneratePatch(fileDiff)
 		var err error
 		if hunk.Staged {
-			err = git.UnstageHunk(patch)
+			err = m.repo.UnstageHunk(context.Background(), patch)
 		} else {
-			err = git.StageHunk(patch)
+			err = m.repo.StageHunk(context.Background(), patch)
 		}
 		if err != nil {
 			return errMsg{err}
 		}
 
 		// Refresh combined diff
-		diff, err := git.GetCombinedDiff()
+		diff, err := m.repo.GetCombinedDiff(context.Background())
 		if err != nil {
 			return errMsg{err}
 		}
@@ -520,14 +696,18 @@ func (m DiffModel) stageHunk() tea.Cmd {
 		return nil
 	}
 
+	patch := m.patchFor(hunk, fileDiff, false)
+	if patch == "" {
+		return nil
+	}
+
 	return func() tea.Msg {
-		patch := hunk.GeneratePatch(fileDiff)
-		err := git.StageHunk(patch)
+		err := m.repo.StageHunk(context.Background(), patch)
 		if err != nil {
 			return errMsg{err}
 		}
 
-		diff, err := git.GetCombinedDiff()
+		diff, err := m.repo.GetCombinedDiff(context.Background())
 		if err != nil {
 			return errMsg{err}
 		}
@@ -551,14 +731,18 @@ func (m DiffModel) unstageHunk() tea.Cmd {
 		return nil
 	}
 
+	patch := m.patchFor(hunk, fileDiff, true)
+	if patch == "" {
+		return nil
+	}
+
 	return func() tea.Msg {
-		patch := hunk.GeneratePatch(fileDiff)
-		err := git.UnstageHunk(patch)
+		err := m.repo.UnstageHunk(context.Background(), patch)
 		if err != nil {
 			return errMsg{err}
 		}
 
-		diff, err := git.GetCombinedDiff()
+		diff, err := m.repo.GetCombinedDiff(context.Background())
 		if err != nil {
 			return errMsg{err}
 		}
@@ -582,14 +766,95 @@ func (m DiffModel) doDiscard() tea.Cmd {
 		return nil
 	}
 
+	patch := m.patchFor(hunk, fileDiff, true)
+	if patch == "" {
+		return nil
+	}
+
 	return func() tea.Msg {
-		patch := hunk.GeneratePatch(fileDiff)
-		err := git.DiscardHunk(patch)
+		err := m.repo.DiscardHunk(context.Background(), patch)
 		if err != nil {
 			return errMsg{err}
 		}
 		// Refresh combined diff
-		diff, err := git.GetCombinedDiff()
+		diff, err := m.repo.GetCombinedDiff(context.Background())
+		if err != nil {
+			return errMsg{err}
+		}
+		return combinedDiffMsg{diff}
+	}
+}
+
+// editHunk opens the patch for the hunk under the cursor in $EDITOR
+func (m DiffModel) editHunk() tea.Cmd {
+	if len(m.hunks) == 0 || m.cursor >= len(m.hunks) {
+		return nil
+	}
+
+	hunk := m.hunks[m.cursor]
+	// Editing only makes sense for staging unstaged changes
+	if hunk.Staged {
+		return nil
+	}
+
+	fileDiff := m.diff.GetFileDiff(&hunk)
+	if fileDiff == nil {
+		return nil
+	}
+
+	f, err := os.CreateTemp("", "go-on-git-hunk-*.diff")
+	if err != nil {
+		return func() tea.Msg { return errMsg{err} }
+	}
+	_, err = f.WriteString(hunk.GenerateEditablePatch(fileDiff))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return func() tea.Msg { return errMsg{err} }
+	}
+	return openHunkEditor(f.Name())
+}
+
+// openHunkEditor suspends the UI and opens the patch file in $EDITOR
+func openHunkEditor(path string) tea.Cmd {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return hunkEditedMsg{path, err}
+	})
+}
+
+// stageEditedHunk validates the edited patch and stages it. The patch file
+// is kept when it doesn't apply so it can be edited again.
+func (m DiffModel) stageEditedHunk(path string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			os.Remove(path)
+			return errMsg{err}
+		}
+
+		patch := git.ParseEditedPatch(string(content))
+		if patch == "" {
+			// Every change was removed: leave the hunk as it is
+			os.Remove(path)
+			return nil
+		}
+
+		if err := m.repo.CheckEditedHunk(context.Background(), patch); err != nil {
+			return hunkEditFailedMsg{path, err}
+		}
+		os.Remove(path)
+		if err := m.repo.StageEditedHunk(context.Background(), patch); err != nil {
+			return errMsg{err}
+		}
+
+		diff, err := m.repo.GetCombinedDiff(context.Background())
 		if err != nil {
 			return errMsg{err}
 		}
@@ -659,7 +924,7 @@ func (m DiffModel) View() string {
 	if m.cursor < len(m.hunks) && availableForDetail > 0 {
 		hunk := m.hunks[m.cursor]
 
-		sb.WriteString(fmt.Sprintf("─── %s %s ───", renderStageLabel(hunk.Staged), hunk.Header))
+		sb.WriteString(fmt.Sprintf("─── %s%s ───", m.stageLabel(hunk.Staged), hunk.Header))
 		sb.WriteString("\n")
 
 		totalLines := len(hunk.Lines)
@@ -702,12 +967,15 @@ func (m DiffModel) View() string {
 			cursor = "> "
 		}
 
-		stageLabel := "[U]"
+		stageLabel := "[U] "
 		stageStyle := StyleHunkHeaderUnstaged
 		if h.Staged {
-			stageLabel = "[S]"
+			stageLabel = "[S] "
 			stageStyle = StyleHunkHeaderStaged
 		}
+		if m.readOnly {
+			stageLabel = ""
+		}
 
 		adds, dels := 0, 0
 		for _, line := range h.Lines {
@@ -721,7 +989,7 @@ func (m DiffModel) View() string {
 
 		sb.WriteString(cursor)
 		sb.WriteString(stageStyle.Render(stageLabel))
-		sb.WriteString(fmt.Sprintf(" @@ %s +%d -%d", h.DisplayFilePath, adds, dels))
+		sb.WriteString(fmt.Sprintf("@@ %s +%d -%d", h.DisplayFilePath, adds, dels))
 		sb.WriteString("\n")
 	}
 
@@ -736,6 +1004,9 @@ func (m DiffModel) View() string {
 		sb.WriteString("\n")
 		hunk := m.hunks[m.cursor]
 		sb.WriteString(StyleConfirm.Render(fmt.Sprintf("Discard hunk from '%s'? Type 'yes' to confirm: %s", hunk.DisplayFilePath, m.confirmInput)))
+	} else if m.editRetryPath != "" {
+		sb.WriteString("\n")
+		sb.WriteString(m.renderEditRetryPrompt())
 	}
 
 	return m.anchorBottom(sb.String())
@@ -750,18 +1021,30 @@ func (m DiffModel) renderHunkDetail() string {
 	totalLines := len(hunk.Lines)
 	visible := m.visibleLines()
 	endLine := min(m.scrollOffset+visible, totalLines)
+	var selected map[int]bool
+	if m.lineVisual {
+		selected = m.selectedLines()
+	}
 	for i := m.scrollOffset; i < endLine; i++ {
 		line := hunk.Lines[i]
-		var styled string
+		style := StyleDiffContext
 		switch line.Type {
 		case git.LineAdded:
-			styled = StyleDiffAdded.Render(line.Content)
+			style = StyleDiffAdded
 		case git.LineRemoved:
-			styled = StyleDiffRemoved.Render(line.Content)
-		default:
-			styled = StyleDiffContext.Render(line.Content)
+			style = StyleDiffRemoved
+		}
+		prefix := "  "
+		if i == m.lineCursor {
+			prefix = "> "
+		}
+		if selected[i] {
+			sb.WriteString(StyleVisual.Render(prefix))
+			sb.WriteString(style.Inherit(StyleVisual).Render(line.Content))
+		} else {
+			sb.WriteString(prefix)
+			sb.WriteString(style.Render(line.Content))
 		}
-		sb.WriteString(styled)
 		sb.WriteString("\n")
 	}
 
@@ -774,17 +1057,32 @@ func (m DiffModel) renderHunkDetail() string {
 	}
 
 	// Header with file info and navigation hint (at bottom)
-	sb.WriteString(fmt.Sprintf("─── %s %s %s ───", renderStageLabel(hunk.Staged), hunk.DisplayFilePath, hunk.Header))
+	sb.WriteString(fmt.Sprintf("─── %s%s %s ───", m.stageLabel(hunk.Staged), hunk.DisplayFilePath, hunk.Header))
+	if m.lineVisual {
+		sb.WriteString(" ")
+		sb.WriteString(StyleVisual.Render("-- VISUAL --"))
+	}
 	sb.WriteString("\n")
 
 	// Confirm prompt (only shown when confirming)
 	if m.confirmMode {
-		sb.WriteString(StyleConfirm.Render(fmt.Sprintf("Discard hunk from '%s'? Type 'yes' to confirm: %s", hunk.DisplayFilePath, m.confirmInput)))
+		what := "hunk"
+		if m.lineVisual {
+			what = "selected lines"
+		}
+		sb.WriteString(StyleConfirm.Render(fmt.Sprintf("Discard %s from '%s'? Type 'yes' to confirm: %s", what, hunk.DisplayFilePath, m.confirmInput)))
+	} else if m.editRetryPath != "" {
+		sb.WriteString(m.renderEditRetryPrompt())
 	}
 
 	return sb.String()
 }
 
+// renderEditRetryPrompt asks whether to re-edit a patch that failed to apply
+func (m DiffModel) renderEditRetryPrompt() string {
+	return StyleConfirm.Render("Edited hunk does not apply. Edit again? (y/n) ")
+}
+
 // fullDiffTotalLines returns the total number of lines in the full diff view
 func (m DiffModel) fullDiffTotalLines() int {
 	total := 0
@@ -805,13 +1103,7 @@ func (m DiffModel) renderFullDiff() string {
 	for _, h := range m.hunks {
 		// Add file header when file changes
 		if h.FilePath != lastFilePath {
-			stageLabel := "[Unstaged]"
-			stageStyle := StyleHunkHeaderUnstaged
-			if h.Staged {
-				stageLabel = "[Staged]"
-				stageStyle = StyleHunkHeaderStaged
-			}
-			lines = append(lines, stageStyle.Render(stageLabel)+" "+h.DisplayFilePath)
+			lines = append(lines, m.stageLabel(h.Staged)+h.DisplayFilePath)
 			lastFilePath = h.FilePath
 		}
 
@@ -873,24 +1165,31 @@ func (m DiffModel) renderHelp() string {
 	topKey := formatDoubleKey(Keys.Top)
 
 	help := []struct {
-		key  string
-		desc string
+		key     string
+		desc    string
+		staging bool
 	}{
-		{drillKeys, "View hunk detail (scrollable)"},
-		{Keys.FullDiff, "Toggle full diff view"},
-		{backKeys, "Go back"},
-		{moveKeys, "Navigate / scroll"},
-		{topKey, "Go to top"},
-		{Keys.Bottom, "Go to bottom"},
-		{"SPACE", "Toggle stage/unstage hunk"},
-		{Keys.Stage, "Stage hunk"},
-		{Keys.Unstage, "Unstage hunk"},
-		{Keys.Discard, "Discard hunk (unstaged only)"},
-		{Keys.Help, "Toggle help"},
-		{Keys.Quit, "Quit"},
+		{drillKeys, "View hunk detail (scrollable)", false},
+		{Keys.FullDiff, "Toggle full diff view", false},
+		{backKeys, "Go back", false},
+		{moveKeys, "Navigate / scroll", false},
+		{topKey, "Go to top", false},
+		{Keys.Bottom, "Go to bottom", false},
+		{Keys.Split, "Split hunk into smaller hunks", true},
+		{Keys.EditHunk, "Edit hunk in $EDITOR and stage it", true},
+		{Keys.Visual, "Select lines (hunk detail)", true},
+		{"SPACE", "Toggle stage/unstage hunk or lines", true},
+		{Keys.Stage, "Stage hunk or lines", true},
+		{Keys.Unstage, "Unstage hunk or lines", true},
+		{Keys.Discard, "Discard hunk or lines (unstaged only)", true},
+		{Keys.Help, "Toggle help", false},
+		{Keys.Quit, "Quit", false},
 	}
 
 	for _, h := range help {
+		if h.staging && m.readOnly {
+			continue
+		}
 		sb.WriteString(fmt.Sprintf("  %s  %s\n",
 			StyleHelpKey.Render(fmt.Sprintf("%-8s", h.key)),
 			StyleHelpDesc.Render(h.desc)))
@@ -899,6 +1198,15 @@ func (m DiffModel) renderHelp() string {
 	return sb.String()
 }
 
+// stageLabel renders the hunk's stage label followed by a space, or nothing
+// for read-only diffs where every hunk is already committed
+func (m DiffModel) stageLabel(staged bool) string {
+	if m.readOnly {
+		return ""
+	}
+	return renderStageLabel(staged) + " "
+}
+
 func renderStageLabel(staged bool) string {
 	if staged {
 		return StyleHunkHeaderStaged.Render("[Staged]")
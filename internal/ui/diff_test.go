@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -11,7 +13,7 @@ import (
 )
 
 func TestNewDiffModel(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 
 	if m.cursor != 0 {
 		t.Errorf("cursor = %d, want 0", m.cursor)
@@ -31,7 +33,7 @@ func TestNewDiffModel(t *testing.T) {
 }
 
 func TestNewDiffModelWithSize(t *testing.T) {
-	m := NewDiffModelWithSize([]string{"file1.txt", "file2.txt"}, 100, 50)
+	m := NewDiffModelWithSize(nil, []string{"file1.txt", "file2.txt"}, 100, 50)
 
 	if m.width != 100 {
 		t.Errorf("width = %d, want 100", m.width)
@@ -50,7 +52,7 @@ func TestNewDiffModelWithFilters(t *testing.T) {
 		{Path: "file1.txt", ShowStaged: true},
 		{Path: "file2.txt", ShowStaged: false, Untracked: true},
 	}
-	m := NewDiffModelWithFilters(filters, 80, 40)
+	m := NewDiffModelWithFilters(nil, filters, 80, 40)
 
 	if len(m.filterFiles) != 2 {
 		t.Errorf("filterFiles = %d, want 2", len(m.filterFiles))
@@ -61,7 +63,7 @@ func TestNewDiffModelWithFilters(t *testing.T) {
 }
 
 func TestDiffModelInit(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	cmd := m.Init()
 
 	if cmd == nil {
@@ -70,7 +72,7 @@ func TestDiffModelInit(t *testing.T) {
 }
 
 func TestDiffModelIsViewingHunk(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 
 	if m.IsViewingHunk() {
 		t.Error("IsViewingHunk should be false initially")
@@ -83,7 +85,7 @@ func TestDiffModelIsViewingHunk(t *testing.T) {
 }
 
 func TestDiffModelNavigation(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.hunks = []git.Hunk{
 		{FilePath: "file1.txt", Header: "@@ -1,3 +1,3 @@"},
 		{FilePath: "file2.txt", Header: "@@ -1,3 +1,3 @@"},
@@ -136,7 +138,7 @@ func TestDiffModelNavigation(t *testing.T) {
 }
 
 func TestDiffModelDrillDown(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.hunks = []git.Hunk{
 		{
 			FilePath: "file1.txt",
@@ -168,7 +170,7 @@ func TestDiffModelDrillDown(t *testing.T) {
 }
 
 func TestDiffModelHunkDetailNavigation(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.height = 10
 	m.hunks = []git.Hunk{
 		{
@@ -179,30 +181,42 @@ func TestDiffModelHunkDetailNavigation(t *testing.T) {
 	}
 	m.viewingHunk = true
 
-	// Test scroll down
+	// Test line cursor down
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
 	m = newModel.(DiffModel)
-	if m.scrollOffset != 1 {
-		t.Errorf("scrollOffset = %d, want 1", m.scrollOffset)
+	if m.lineCursor != 1 {
+		t.Errorf("lineCursor = %d, want 1", m.lineCursor)
 	}
 
-	// Test scroll up
+	// Test line cursor up
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
 	m = newModel.(DiffModel)
-	if m.scrollOffset != 0 {
-		t.Errorf("scrollOffset = %d, want 0", m.scrollOffset)
+	if m.lineCursor != 0 {
+		t.Errorf("lineCursor = %d, want 0", m.lineCursor)
 	}
 
-	// Test can't scroll past top
+	// Test can't move past top
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
 	m = newModel.(DiffModel)
-	if m.scrollOffset != 0 {
-		t.Errorf("scrollOffset should stay at 0, got %d", m.scrollOffset)
+	if m.lineCursor != 0 || m.scrollOffset != 0 {
+		t.Errorf("cursor should stay at 0, got line %d scroll %d", m.lineCursor, m.scrollOffset)
+	}
+
+	// Test scrolling follows the cursor past the visible lines
+	for i := 0; i < m.visibleLines(); i++ {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+		m = newModel.(DiffModel)
+	}
+	if m.scrollOffset != 1 {
+		t.Errorf("scrollOffset = %d, want 1", m.scrollOffset)
 	}
 
 	// Test jump to bottom (G)
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
 	m = newModel.(DiffModel)
+	if m.lineCursor != 19 {
+		t.Errorf("lineCursor = %d after 'G', want 19", m.lineCursor)
+	}
 	if m.scrollOffset == 0 {
 		t.Error("scrollOffset should be > 0 after 'G'")
 	}
@@ -212,13 +226,13 @@ func TestDiffModelHunkDetailNavigation(t *testing.T) {
 	m = newModel.(DiffModel)
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
 	m = newModel.(DiffModel)
-	if m.scrollOffset != 0 {
-		t.Errorf("after 'gg', scrollOffset = %d, want 0", m.scrollOffset)
+	if m.lineCursor != 0 || m.scrollOffset != 0 {
+		t.Errorf("after 'gg', line %d scroll %d, want 0", m.lineCursor, m.scrollOffset)
 	}
 }
 
 func TestDiffModelHelpToggle(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 
 	// Toggle help
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
@@ -236,7 +250,7 @@ func TestDiffModelHelpToggle(t *testing.T) {
 }
 
 func TestDiffModelConfirmMode(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.hunks = []git.Hunk{
 		{FilePath: "file1.txt", Staged: false},
 	}
@@ -257,7 +271,7 @@ func TestDiffModelConfirmMode(t *testing.T) {
 }
 
 func TestDiffModelConfirmModeNotForStaged(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.hunks = []git.Hunk{
 		{FilePath: "file1.txt", Staged: true},
 	}
@@ -271,7 +285,7 @@ func TestDiffModelConfirmModeNotForStaged(t *testing.T) {
 }
 
 func TestDiffModelQuit(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
 	if cmd == nil {
@@ -280,7 +294,7 @@ func TestDiffModelQuit(t *testing.T) {
 }
 
 func TestDiffModelWindowResize(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
 	m = newModel.(DiffModel)
@@ -294,7 +308,7 @@ func TestDiffModelWindowResize(t *testing.T) {
 }
 
 func TestDiffModelCombinedDiffMsg(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 
 	diff := &git.CombinedDiffResult{
 		StagedDiff: &git.DiffResult{
@@ -331,7 +345,7 @@ func TestDiffModelCombinedDiffMsg(t *testing.T) {
 }
 
 func TestDiffModelAutoEnterDetailForSingleHunk(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 
 	diff := &git.CombinedDiffResult{
 		UnstagedDiff: &git.DiffResult{
@@ -356,7 +370,7 @@ func TestDiffModelAutoEnterDetailForSingleHunk(t *testing.T) {
 }
 
 func TestDiffModelErrMsg(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 
 	newModel, _ := m.Update(errMsg{err: fmt.Errorf("test error")})
 	m = newModel.(DiffModel)
@@ -378,7 +392,7 @@ func TestDiffModelVisibleLines(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		m := NewDiffModel(nil)
+		m := NewDiffModel(nil, nil)
 		m.height = tt.height
 		got := m.visibleLines()
 		if got != tt.want {
@@ -388,7 +402,7 @@ func TestDiffModelVisibleLines(t *testing.T) {
 }
 
 func TestDiffModelView(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.diff = &git.CombinedDiffResult{}
 	m.hunks = []git.Hunk{
 		{
@@ -414,7 +428,7 @@ func TestDiffModelView(t *testing.T) {
 }
 
 func TestDiffModelViewLoading(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.diff = nil
 
 	view := m.View()
@@ -425,7 +439,7 @@ func TestDiffModelViewLoading(t *testing.T) {
 }
 
 func TestDiffModelViewEmpty(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.diff = &git.CombinedDiffResult{}
 	m.hunks = nil
 
@@ -437,7 +451,7 @@ func TestDiffModelViewEmpty(t *testing.T) {
 }
 
 func TestDiffModelViewHelp(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.showHelp = true
 
 	view := m.View()
@@ -448,7 +462,7 @@ func TestDiffModelViewHelp(t *testing.T) {
 }
 
 func TestDiffModelViewHunkDetail(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.diff = &git.CombinedDiffResult{}
 	m.hunks = []git.Hunk{
 		{
@@ -474,7 +488,7 @@ func TestDiffModelViewHunkDetail(t *testing.T) {
 }
 
 func TestDiffModelViewConfirmPrompt(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.diff = &git.CombinedDiffResult{}
 	m.hunks = []git.Hunk{
 		{FilePath: "file1.txt", Staged: false},
@@ -492,7 +506,7 @@ func TestDiffModelViewConfirmPrompt(t *testing.T) {
 }
 
 func TestDiffModelViewWithError(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.err = fmt.Errorf("test error")
 	m.diff = &git.CombinedDiffResult{}
 
@@ -504,7 +518,7 @@ func TestDiffModelViewWithError(t *testing.T) {
 }
 
 func TestDiffModelArrowKeys(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.hunks = []git.Hunk{
 		{FilePath: "file1.txt"},
 		{FilePath: "file2.txt"},
@@ -526,7 +540,7 @@ func TestDiffModelArrowKeys(t *testing.T) {
 }
 
 func TestDiffModelEnterKey(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.hunks = []git.Hunk{
 		{FilePath: "file1.txt", Lines: []git.DiffLine{{Content: "test"}}},
 	}
@@ -540,7 +554,7 @@ func TestDiffModelEnterKey(t *testing.T) {
 }
 
 func TestDiffModelHelpModeBlocksNavigation(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.hunks = []git.Hunk{
 		{FilePath: "file1.txt"},
 		{FilePath: "file2.txt"},
@@ -606,8 +620,47 @@ func TestRenderStageLabel(t *testing.T) {
 	}
 }
 
+func TestDiffModelStageHunkUsesExecutor(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("apply --cached", git.Result{})
+	fake.on("diff --cached", git.Result{})
+	fake.on("diff", git.Result{})
+
+	hunk := git.Hunk{
+		FilePath: "file.txt",
+		Header:   "@@ -1 +1 @@",
+		Lines: []git.DiffLine{
+			{Content: "-old", Type: git.LineRemoved},
+			{Content: "+new", Type: git.LineAdded},
+		},
+	}
+	m := NewDiffModel(repo, nil)
+	m.diff = &git.CombinedDiffResult{
+		UnstagedDiff: &git.DiffResult{
+			Files: []git.FileDiff{{Path: "file.txt", Header: []string{"diff --git a/file.txt b/file.txt"}, Hunks: []git.Hunk{hunk}}},
+		},
+	}
+	m.hunks = []git.Hunk{hunk}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd == nil {
+		t.Fatal("staging a hunk should return a command")
+	}
+	if _, ok := cmd().(combinedDiffMsg); !ok {
+		t.Fatal("staging a hunk should refresh the diff")
+	}
+
+	call, ok := fake.called("apply --cached")
+	if !ok {
+		t.Fatal("expected git apply --cached to be executed")
+	}
+	if !strings.Contains(call.Stdin, "+new") {
+		t.Errorf("expected hunk patch on stdin, got %q", call.Stdin)
+	}
+}
+
 func TestDiffModelKeepHunkOrder(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.hunks = []git.Hunk{
 		{FilePath: "a.txt", Lines: []git.DiffLine{{Content: "+a", Type: git.LineAdded}}},
 		{FilePath: "b.txt", Lines: []git.DiffLine{{Content: "+b", Type: git.LineAdded}}},
@@ -635,7 +688,7 @@ func TestDiffModelKeepHunkOrder(t *testing.T) {
 }
 
 func TestDiffModelAnchorBottom(t *testing.T) {
-	m := NewDiffModel(nil)
+	m := NewDiffModel(nil, nil)
 	m.height = 10
 
 	content := "line1\nline2\nline3\n"
@@ -646,3 +699,270 @@ func TestDiffModelAnchorBottom(t *testing.T) {
 		t.Error("anchored content should have leading newlines")
 	}
 }
+
+func TestDiffModelStageSelectedLines(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("apply --cached", git.Result{})
+	fake.on("diff --cached", git.Result{})
+	fake.on("diff", git.Result{})
+
+	hunk := git.Hunk{
+		FilePath: "file.txt",
+		Header:   "@@ -1,2 +1,2 @@",
+		StartOld: 1, CountOld: 2, StartNew: 1, CountNew: 2,
+		Lines: []git.DiffLine{
+			{Content: "-one", Type: git.LineRemoved},
+			{Content: "-two", Type: git.LineRemoved},
+			{Content: "+ONE", Type: git.LineAdded},
+			{Content: "+TWO", Type: git.LineAdded},
+		},
+	}
+	m := NewDiffModel(repo, nil)
+	m.diff = &git.CombinedDiffResult{
+		UnstagedDiff: &git.DiffResult{
+			Files: []git.FileDiff{{Path: "file.txt", Header: []string{"diff --git a/file.txt b/file.txt"}, Hunks: []git.Hunk{hunk}}},
+		},
+	}
+	m.hunks = []git.Hunk{hunk}
+	m.viewingHunk = true
+
+	// Select just the line under the cursor ("-one")
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	m = newModel.(DiffModel)
+	if !m.lineVisual {
+		t.Fatal("v should start a line selection")
+	}
+	if !strings.Contains(m.View(), "-- VISUAL --") {
+		t.Error("view should show visual mode")
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = newModel.(DiffModel)
+	if cmd == nil {
+		t.Fatal("staging lines should return a command")
+	}
+	if m.lineVisual {
+		t.Error("staging should end the line selection")
+	}
+	cmd()
+
+	call, ok := fake.called("apply --cached")
+	if !ok {
+		t.Fatal("expected git apply --cached to be executed")
+	}
+	want := "diff --git a/file.txt b/file.txt\n@@ -1,2 +1,1 @@\n-one\n two\n"
+	if call.Stdin != want {
+		t.Errorf("partial patch = %q, want %q", call.Stdin, want)
+	}
+}
+
+func TestDiffModelSelectedLinesWithoutChanges(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	hunk := git.Hunk{
+		FilePath: "file.txt",
+		Header:   "@@ -1,2 +1,2 @@",
+		Lines: []git.DiffLine{
+			{Content: " context", Type: git.LineContext},
+			{Content: "+new", Type: git.LineAdded},
+		},
+	}
+	m := NewDiffModel(repo, nil)
+	m.diff = &git.CombinedDiffResult{
+		UnstagedDiff: &git.DiffResult{
+			Files: []git.FileDiff{{Path: "file.txt", Hunks: []git.Hunk{hunk}}},
+		},
+	}
+	m.hunks = []git.Hunk{hunk}
+	m.viewingHunk = true
+	m.lineVisual = true
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd != nil {
+		t.Error("staging a selection of context lines should do nothing")
+	}
+	if _, ok := fake.called("apply --cached"); ok {
+		t.Error("no patch should be applied")
+	}
+}
+
+func TestDiffModelEscEndsLineSelection(t *testing.T) {
+	m := NewDiffModel(nil, nil)
+	m.hunks = []git.Hunk{{FilePath: "file.txt", Lines: make([]git.DiffLine, 3)}}
+	m.viewingHunk = true
+	m.lineVisual = true
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(DiffModel)
+	if m.lineVisual || !m.viewingHunk {
+		t.Error("esc should end the line selection before leaving the hunk")
+	}
+}
+
+// splittableHunk has two blocks of changes separated by context
+func splittableHunk() git.Hunk {
+	return git.Hunk{
+		FilePath: "file.txt",
+		Header:   "@@ -1,5 +1,5 @@",
+		StartOld: 1, CountOld: 5, StartNew: 1, CountNew: 5,
+		Lines: []git.DiffLine{
+			{Content: "-one", Type: git.LineRemoved},
+			{Content: "+ONE", Type: git.LineAdded},
+			{Content: " two", Type: git.LineContext},
+			{Content: " three", Type: git.LineContext},
+			{Content: "-four", Type: git.LineRemoved},
+			{Content: "+FOUR", Type: git.LineAdded},
+			{Content: " five", Type: git.LineContext},
+		},
+	}
+}
+
+func TestDiffModelSplitHunk(t *testing.T) {
+	m := NewDiffModel(nil, nil)
+	other := git.Hunk{FilePath: "other.txt", Lines: []git.DiffLine{{Content: "+x", Type: git.LineAdded}}}
+	m.hunks = []git.Hunk{splittableHunk(), other}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = newModel.(DiffModel)
+
+	if len(m.hunks) != 3 {
+		t.Fatalf("expected 3 hunks after split, got %d", len(m.hunks))
+	}
+	if m.hunks[0].Header != "@@ -1,3 +1,3 @@" || m.hunks[1].Header != "@@ -2,4 +2,4 @@" {
+		t.Errorf("unexpected piece headers %q, %q", m.hunks[0].Header, m.hunks[1].Header)
+	}
+	if m.hunks[2].FilePath != "other.txt" {
+		t.Error("hunks after the split one should keep their order")
+	}
+
+	// A hunk with a single block of changes stays as it is
+	m.cursor = 2
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = newModel.(DiffModel)
+	if len(m.hunks) != 3 {
+		t.Errorf("unsplittable hunk should not change, got %d hunks", len(m.hunks))
+	}
+}
+
+func TestDiffModelSplitHunkSurvivesRefresh(t *testing.T) {
+	m := NewDiffModel(nil, nil)
+	m.hunks = []git.Hunk{splittableHunk()}
+	m.viewingHunk = true
+	m.splitHunk()
+	m.cursor = 1
+
+	// git diff reports the pieces as one hunk again after a refresh
+	hunk := splittableHunk()
+	newModel, _ := m.Update(combinedDiffMsg{&git.CombinedDiffResult{
+		UnstagedDiff: &git.DiffResult{Files: []git.FileDiff{{Path: "file.txt", Hunks: []git.Hunk{hunk}}}},
+	}})
+	m = newModel.(DiffModel)
+
+	if len(m.hunks) != 2 {
+		t.Fatalf("expected the hunk to be split again, got %d hunks", len(m.hunks))
+	}
+	if m.cursor != 1 || m.hunks[1].Lines[len(m.hunks[1].Lines)-2].Content != "+FOUR" {
+		t.Error("cursor should stay on the second piece")
+	}
+}
+
+// writeEditedPatch writes an edited hunk patch to a temp file
+func writeEditedPatch(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hunk.diff")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write patch: %v", err)
+	}
+	return path
+}
+
+func TestDiffModelStageEditedHunk(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("apply --check --cached --recount", git.Result{})
+	fake.on("apply --cached --recount", git.Result{})
+	fake.on("diff --cached", git.Result{})
+	fake.on("diff", git.Result{})
+
+	path := writeEditedPatch(t, "diff --git a/f b/f\n@@ -1 +1,2 @@\n a\n+b\n# ---\n# guide\n")
+	m := NewDiffModel(repo, nil)
+
+	_, cmd := m.Update(hunkEditedMsg{path: path})
+	if cmd == nil {
+		t.Fatal("a finished edit should stage the patch")
+	}
+	if _, ok := cmd().(combinedDiffMsg); !ok {
+		t.Fatal("staging the edited hunk should refresh the diff")
+	}
+
+	call, ok := fake.called("apply --cached --recount")
+	if !ok {
+		t.Fatal("expected git apply --cached --recount")
+	}
+	if strings.Contains(call.Stdin, "#") {
+		t.Errorf("comment lines should be stripped, got %q", call.Stdin)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("patch file should be removed after staging")
+	}
+}
+
+func TestDiffModelEditedHunkDoesNotApply(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("apply --check --cached --recount", git.Result{Stderr: "error: patch does not apply", ExitCode: 1})
+
+	path := writeEditedPatch(t, "@@ -1 +1,2 @@\n a\n+b\n")
+	m := NewDiffModel(repo, nil)
+	m.diff = &git.CombinedDiffResult{}
+	m.hunks = []git.Hunk{{FilePath: "f"}}
+
+	newModel, cmd := m.Update(hunkEditedMsg{path: path})
+	m = newModel.(DiffModel)
+	msg := cmd()
+	if _, ok := msg.(hunkEditFailedMsg); !ok {
+		t.Fatalf("expected hunkEditFailedMsg, got %#v", msg)
+	}
+	if _, ok := fake.called("apply --cached --recount"); ok {
+		t.Error("a patch that fails the check must not be applied")
+	}
+
+	newModel, _ = m.Update(msg)
+	m = newModel.(DiffModel)
+	if m.editRetryPath != path || m.err == nil {
+		t.Fatal("failed edit should offer to edit again")
+	}
+	if !strings.Contains(m.View(), "Edit again? (y/n)") {
+		t.Error("view should ask to edit again")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = newModel.(DiffModel)
+	if m.editRetryPath != "" {
+		t.Error("declining should clear the retry")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("declining should remove the patch file")
+	}
+}
+
+func TestDiffModelEditedHunkAborted(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	path := writeEditedPatch(t, "# everything deleted\n")
+	m := NewDiffModel(repo, nil)
+
+	_, cmd := m.Update(hunkEditedMsg{path: path})
+	if msg := cmd(); msg != nil {
+		t.Errorf("aborted edit should do nothing, got %#v", msg)
+	}
+	if _, ok := fake.called("apply --check --cached --recount"); ok {
+		t.Error("aborted edit should not be checked")
+	}
+}
+
+func TestDiffModelEditStagedHunkIgnored(t *testing.T) {
+	m := NewDiffModel(nil, nil)
+	m.diff = &git.CombinedDiffResult{}
+	m.hunks = []git.Hunk{{FilePath: "f", Staged: true}}
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}}); cmd != nil {
+		t.Error("editing a staged hunk should do nothing")
+	}
+}
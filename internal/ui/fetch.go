@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"strings"
+
+	"go-on-git/internal/git"
+)
+
+// fetchMenu picks what to fetch: one remote or all of them, optionally
+// pruning remote-tracking branches that are gone from the remote
+type fetchMenu struct {
+	remotes []string // the last choice fetches all of them
+	cursor  int
+	prune   bool
+	open    bool
+}
+
+// newFetchMenu opens the menu on the remote of upstream, if it has one
+func newFetchMenu(remotes []string, upstream string) fetchMenu {
+	menu := fetchMenu{remotes: remotes, open: true}
+	for i, remote := range remotes {
+		if strings.HasPrefix(upstream, remote+"/") {
+			menu.cursor = i
+		}
+	}
+	return menu
+}
+
+// active reports whether the menu is showing
+func (f fetchMenu) active() bool {
+	return f.open
+}
+
+// update handles a key, returning what to fetch once it is chosen
+func (f fetchMenu) update(key string) (fetchMenu, *git.FetchOptions) {
+	switch key {
+	case Keys.Down, "down":
+		f.cursor = min(f.cursor+1, len(f.remotes))
+	case Keys.Up, "up":
+		f.cursor = max(f.cursor-1, 0)
+	case "p":
+		f.prune = !f.prune
+	case "enter":
+		f.open = false
+		opts := git.FetchOptions{Prune: f.prune}
+		if f.cursor == len(f.remotes) {
+			opts.All = true
+		} else {
+			opts.Remote = f.remotes[f.cursor]
+		}
+		return f, &opts
+	case "esc":
+		f.open = false
+	}
+	return f, nil
+}
+
+// view renders the remotes, one per line, then the prune toggle
+func (f fetchMenu) view() string {
+	var sb strings.Builder
+	sb.WriteString("Fetch from:")
+	sb.WriteString(StyleMuted.Render("  (enter to fetch, esc to cancel)"))
+	sb.WriteString("\n")
+	choices := append(append([]string(nil), f.remotes...), "all remotes")
+	for i, choice := range choices {
+		if i == f.cursor {
+			sb.WriteString(StyleSelected.Render("> ") + choice)
+		} else {
+			sb.WriteString("  " + choice)
+		}
+		sb.WriteString("\n")
+	}
+	check := " "
+	if f.prune {
+		check = "x"
+	}
+	sb.WriteString(StyleMuted.Render("[" + check + "] prune deleted branches (p)"))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// lines returns the number of lines view uses
+func (f fetchMenu) lines() int {
+	return len(f.remotes) + 3
+}
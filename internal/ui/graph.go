@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"go-on-git/internal/git"
+)
+
+// commitGraph lays commits out in lanes from their parent hashes, one row
+// per commit, newest first. Each lane holds the hash of the commit it is
+// waiting for; an empty lane is free for reuse.
+type commitGraph struct {
+	lanes []string
+}
+
+// graphCell is one character of a graph row, coloured by the lane it
+// belongs to
+type graphCell struct {
+	glyph string
+	lane  int
+}
+
+// graphRow is the graph column for one commit: a glyph per lane with
+// connectors between them
+type graphRow []graphCell
+
+// next places a commit in the graph and returns its row. Commits must be
+// passed in log order.
+func (g *commitGraph) next(c git.Commit) graphRow {
+	before := slices.Clone(g.lanes)
+
+	col := slices.Index(g.lanes, c.Hash)
+	if col < 0 {
+		col = g.freeLane(-1)
+	}
+
+	// Other lanes waiting for this commit end here
+	var merging []int
+	for i, hash := range g.lanes {
+		if hash == c.Hash && i != col {
+			merging = append(merging, i)
+			g.lanes[i] = ""
+		}
+	}
+
+	// The first parent continues the commit's lane; other parents join an
+	// existing lane or branch out into a new one
+	g.lanes[col] = ""
+	if len(c.Parents) > 0 {
+		g.lanes[col] = c.Parents[0]
+	}
+	var joining, branching []int
+	for _, parent := range c.Parents[min(1, len(c.Parents)):] {
+		if i := slices.Index(g.lanes, parent); i >= 0 {
+			joining = append(joining, i)
+			continue
+		}
+		i := g.freeLane(col)
+		g.lanes[i] = parent
+		branching = append(branching, i)
+	}
+
+	width := max(len(before), len(g.lanes))
+	for len(g.lanes) > 0 && g.lanes[len(g.lanes)-1] == "" {
+		g.lanes = g.lanes[:len(g.lanes)-1]
+	}
+
+	// Lanes connected to the commit by a horizontal line
+	lo, hi := col, col
+	for _, i := range slices.Concat(merging, joining, branching) {
+		lo, hi = min(lo, i), max(hi, i)
+	}
+	// Horizontal lines take the colour of the lane they lead to
+	spanLane := func(i int) int {
+		if i < col {
+			return lo
+		}
+		return hi
+	}
+
+	row := make(graphRow, 0, width*2)
+	for i := 0; i < width; i++ {
+		active := i < len(before) && before[i] != ""
+		inSpan := i > lo && i < hi
+		var glyph string
+		switch {
+		case i == col:
+			glyph = "●"
+		case slices.Contains(merging, i):
+			glyph = pickGlyph(i < col, "└", "┘")
+		case slices.Contains(branching, i):
+			glyph = pickGlyph(i < col, "┌", "┐")
+		case slices.Contains(joining, i):
+			glyph = pickGlyph(i < col, "├", "┤")
+		case active && inSpan:
+			glyph = "┼"
+		case active:
+			glyph = "│"
+		case inSpan:
+			row = append(row, graphCell{"─", spanLane(i)})
+			row = append(row, graphCell{"─", spanLane(i)})
+			continue
+		default:
+			glyph = " "
+		}
+		row = append(row, graphCell{glyph, i})
+		gap := " "
+		if i >= lo && i < hi {
+			gap = "─"
+		}
+		row = append(row, graphCell{gap, spanLane(i)})
+	}
+	return row
+}
+
+// freeLane returns the first free lane other than skip, adding one if needed
+func (g *commitGraph) freeLane(skip int) int {
+	for i, hash := range g.lanes {
+		if hash == "" && i != skip {
+			return i
+		}
+	}
+	g.lanes = append(g.lanes, "")
+	return len(g.lanes) - 1
+}
+
+func pickGlyph(left bool, l, r string) string {
+	if left {
+		return l
+	}
+	return r
+}
+
+// String returns the row without styling
+func (r graphRow) String() string {
+	var sb strings.Builder
+	for _, cell := range r {
+		sb.WriteString(cell.glyph)
+	}
+	return sb.String()
+}
+
+// render styles the row, padding it to width lanes so that rows line up
+func (r graphRow) render(width int) string {
+	var sb strings.Builder
+	for _, cell := range r {
+		if cell.glyph == " " {
+			sb.WriteString(" ")
+			continue
+		}
+		sb.WriteString(StyleGraphLanes[cell.lane%len(StyleGraphLanes)].Render(cell.glyph))
+	}
+	if pad := width*2 - len(r); pad > 0 {
+		sb.WriteString(strings.Repeat(" ", pad))
+	}
+	return sb.String()
+}
+
+// lanes returns the number of lanes in the row
+func (r graphRow) lanes() int {
+	return len(r) / 2
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"go-on-git/internal/git"
+)
+
+// graphOf lays out commits given as "hash:parent,parent" and returns the
+// unstyled rows with trailing spaces trimmed
+func graphOf(specs ...string) []string {
+	var g commitGraph
+	rows := make([]string, len(specs))
+	for i, spec := range specs {
+		hash, parents, _ := strings.Cut(spec, ":")
+		c := git.Commit{Hash: hash}
+		if parents != "" {
+			c.Parents = strings.Split(parents, ",")
+		}
+		rows[i] = strings.TrimRight(g.next(c).String(), " ")
+	}
+	return rows
+}
+
+func TestCommitGraph(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []string
+		want  []string
+	}{
+		{
+			name:  "linear history",
+			specs: []string{"c:b", "b:a", "a:"},
+			want:  []string{"●", "●", "●"},
+		},
+		{
+			name:  "merge branches out and back",
+			specs: []string{"m:b,c", "b:a", "c:a", "a:"},
+			want:  []string{"●─┐", "● │", "│ ●", "●─┘"},
+		},
+		{
+			name:  "two tips converge",
+			specs: []string{"x:a", "y:a", "a:"},
+			want:  []string{"●", "│ ●", "●─┘"},
+		},
+		{
+			name:  "merge crosses a lane",
+			specs: []string{"x:p", "y:q", "z:p", "p:r", "q:r", "r:"},
+			want:  []string{"●", "│ ●", "│ │ ●", "●─┼─┘", "│ ●", "●─┘"},
+		},
+		{
+			name:  "merge joins an existing lane",
+			specs: []string{"f:a", "m:b,a", "b:a", "a:"},
+			want:  []string{"●", "├─●", "│ ●", "●─┘"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := graphOf(tt.specs...)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("graph mismatch\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestCommitGraphFreesLanes(t *testing.T) {
+	var g commitGraph
+	for _, c := range []git.Commit{
+		{Hash: "m", Parents: []string{"b", "c"}},
+		{Hash: "c", Parents: []string{"a"}},
+		{Hash: "b", Parents: []string{"a"}},
+		{Hash: "a"},
+	} {
+		g.next(c)
+	}
+	if len(g.lanes) != 0 {
+		t.Errorf("expected every lane to be freed after the root, got %v", g.lanes)
+	}
+}
+
+func TestGraphRowRenderPads(t *testing.T) {
+	var g commitGraph
+	rendered := g.next(git.Commit{Hash: "a"}).render(3)
+	if got := len([]rune(stripANSI(rendered))); got != 6 {
+		t.Errorf("expected row padded to 3 lanes (6 cells), got %d in %q", got, rendered)
+	}
+}
+
+// stripANSI removes terminal escape sequences
+func stripANSI(s string) string {
+	var sb strings.Builder
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			inEscape = true
+		case inEscape:
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+				inEscape = false
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -21,12 +21,42 @@ type Keymap struct {
 	Unstage    string
 	UnstageAll string
 	Discard    string
+	Split      string
+	EditHunk   string
 	Commit     string
 	CommitEdit string
+	Amend      string
+	AmendEdit  string
 	Push       string
+	PushMenu   string
+	Fetch      string
+	Pull       string
+	Cancel     string
 	Stash      string
 	StashAll   string
 
+	// Conflicts
+	Resolve    string
+	TakeOurs   string
+	TakeTheirs string
+	TakeBoth   string
+
+	// In-progress operations (merge, rebase, cherry-pick, revert, bisect)
+	Continue string
+	Skip     string
+	Abort    string
+
+	// Interactive rebase
+	Rebase   string
+	Pick     string
+	Reword   string
+	Edit     string
+	Squash   string
+	Fixup    string
+	Drop     string
+	MoveUp   string
+	MoveDown string
+
 	// Views
 	FileDiff string
 	AllDiffs string
@@ -34,6 +64,8 @@ type Keymap struct {
 	Branches string
 	Stashes  string
 	Log      string
+	FileLog  string
+	Filter   string
 
 	// Modes
 	Visual      string
@@ -63,17 +95,43 @@ var keymapBindings = []keymapBinding{
 	{action: "unstage", key: func(k *Keymap) *string { return &k.Unstage }},
 	{action: "unstage-all", key: func(k *Keymap) *string { return &k.UnstageAll }},
 	{action: "discard", key: func(k *Keymap) *string { return &k.Discard }},
+	{action: "split", key: func(k *Keymap) *string { return &k.Split }},
+	{action: "edit-hunk", key: func(k *Keymap) *string { return &k.EditHunk }},
 	{action: "commit", key: func(k *Keymap) *string { return &k.Commit }},
 	{action: "commit-edit", key: func(k *Keymap) *string { return &k.CommitEdit }},
+	{action: "amend", key: func(k *Keymap) *string { return &k.Amend }},
+	{action: "amend-edit", key: func(k *Keymap) *string { return &k.AmendEdit }},
 	{action: "push", key: func(k *Keymap) *string { return &k.Push }},
+	{action: "push-menu", key: func(k *Keymap) *string { return &k.PushMenu }},
+	{action: "fetch", key: func(k *Keymap) *string { return &k.Fetch }},
+	{action: "pull", key: func(k *Keymap) *string { return &k.Pull }},
+	{action: "cancel", key: func(k *Keymap) *string { return &k.Cancel }},
 	{action: "stash", key: func(k *Keymap) *string { return &k.Stash }},
 	{action: "stash-all", key: func(k *Keymap) *string { return &k.StashAll }},
+	{action: "resolve", key: func(k *Keymap) *string { return &k.Resolve }},
+	{action: "take-ours", key: func(k *Keymap) *string { return &k.TakeOurs }},
+	{action: "take-theirs", key: func(k *Keymap) *string { return &k.TakeTheirs }},
+	{action: "take-both", key: func(k *Keymap) *string { return &k.TakeBoth }},
+	{action: "continue", key: func(k *Keymap) *string { return &k.Continue }},
+	{action: "skip", key: func(k *Keymap) *string { return &k.Skip }},
+	{action: "abort", key: func(k *Keymap) *string { return &k.Abort }},
+	{action: "rebase", key: func(k *Keymap) *string { return &k.Rebase }},
+	{action: "pick", key: func(k *Keymap) *string { return &k.Pick }},
+	{action: "reword", key: func(k *Keymap) *string { return &k.Reword }},
+	{action: "edit", key: func(k *Keymap) *string { return &k.Edit }},
+	{action: "squash", key: func(k *Keymap) *string { return &k.Squash }},
+	{action: "fixup", key: func(k *Keymap) *string { return &k.Fixup }},
+	{action: "drop", key: func(k *Keymap) *string { return &k.Drop }},
+	{action: "move-up", key: func(k *Keymap) *string { return &k.MoveUp }},
+	{action: "move-down", key: func(k *Keymap) *string { return &k.MoveDown }},
 	{action: "file-diff", key: func(k *Keymap) *string { return &k.FileDiff }},
 	{action: "all-diffs", key: func(k *Keymap) *string { return &k.AllDiffs }},
 	{action: "full-diff", key: func(k *Keymap) *string { return &k.FullDiff }},
 	{action: "branches", key: func(k *Keymap) *string { return &k.Branches }},
 	{action: "stashes", key: func(k *Keymap) *string { return &k.Stashes }},
 	{action: "log", key: func(k *Keymap) *string { return &k.Log }},
+	{action: "file-log", key: func(k *Keymap) *string { return &k.FileLog }},
+	{action: "filter", key: func(k *Keymap) *string { return &k.Filter }},
 	{action: "visual", key: func(k *Keymap) *string { return &k.Visual }},
 	{action: "help", key: func(k *Keymap) *string { return &k.Help }},
 	{action: "verbose-help", key: func(k *Keymap) *string { return &k.VerboseHelp }},
@@ -101,12 +159,42 @@ func DefaultKeymap() *Keymap {
 		Unstage:    "u",
 		UnstageAll: "U",
 		Discard:    "d",
+		Split:      "s",
+		EditHunk:   "e",
 		Commit:     "c",
 		CommitEdit: "C",
+		Amend:      "m",
+		AmendEdit:  "M",
 		Push:       "p",
+		PushMenu:   "ctrl+p",
+		Fetch:      "F",
+		Pull:       "P",
+		Cancel:     "x",
 		Stash:      "s",
 		StashAll:   "S",
 
+		// Conflicts
+		Resolve:    "r",
+		TakeOurs:   "<",
+		TakeTheirs: ">",
+		TakeBoth:   "+",
+
+		// In-progress operations
+		Continue: "R",
+		Skip:     "K",
+		Abort:    "X",
+
+		// Interactive rebase
+		Rebase:   "i",
+		Pick:     "p",
+		Reword:   "r",
+		Edit:     "e",
+		Squash:   "s",
+		Fixup:    "f",
+		Drop:     "d",
+		MoveUp:   "K",
+		MoveDown: "J",
+
 		// Views
 		FileDiff: "l",
 		AllDiffs: "i",
@@ -114,6 +202,8 @@ func DefaultKeymap() *Keymap {
 		Branches: "b",
 		Stashes:  "e",
 		Log:      "o",
+		FileLog:  "O",
+		Filter:   "F",
 
 		// Modes
 		Visual:      "v",
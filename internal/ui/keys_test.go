@@ -55,6 +55,15 @@ func TestDefaultKeymap(t *testing.T) {
 	if km.Push != "p" {
 		t.Errorf("expected Push to be 'p', got %q", km.Push)
 	}
+	if km.PushMenu != "ctrl+p" {
+		t.Errorf("expected PushMenu to be 'ctrl+p', got %q", km.PushMenu)
+	}
+	if km.Fetch != "F" {
+		t.Errorf("expected Fetch to be 'F', got %q", km.Fetch)
+	}
+	if km.Pull != "P" {
+		t.Errorf("expected Pull to be 'P', got %q", km.Pull)
+	}
 	if km.Stash != "s" {
 		t.Errorf("expected Stash to be 's', got %q", km.Stash)
 	}
@@ -251,9 +260,12 @@ func TestListKeymapActions(t *testing.T) {
 	expectedActions := []string{
 		"up", "down", "left", "right", "top", "bottom",
 		"select", "back", "quit",
-		"stage", "stage-all", "unstage", "unstage-all", "discard",
-		"commit", "commit-edit", "push", "stash", "stash-all",
-		"file-diff", "all-diffs", "branches", "stashes", "log",
+		"stage", "stage-all", "unstage", "unstage-all", "discard", "split", "edit-hunk",
+		"commit", "commit-edit", "amend", "amend-edit", "push", "push-menu", "fetch", "pull", "cancel", "stash", "stash-all",
+		"resolve", "take-ours", "take-theirs", "take-both",
+		"continue", "skip", "abort",
+		"rebase", "pick", "reword", "edit", "squash", "fixup", "drop", "move-up", "move-down",
+		"file-diff", "all-diffs", "branches", "stashes", "log", "file-log", "filter",
 		"visual", "help", "verbose-help", "new-branch", "delete",
 	}
 
@@ -419,16 +431,42 @@ func TestKeymapBindingsComplete(t *testing.T) {
 		{"unstage", func(k *Keymap) string { return k.Unstage }},
 		{"unstage-all", func(k *Keymap) string { return k.UnstageAll }},
 		{"discard", func(k *Keymap) string { return k.Discard }},
+		{"split", func(k *Keymap) string { return k.Split }},
+		{"edit-hunk", func(k *Keymap) string { return k.EditHunk }},
 		{"commit", func(k *Keymap) string { return k.Commit }},
 		{"commit-edit", func(k *Keymap) string { return k.CommitEdit }},
+		{"amend", func(k *Keymap) string { return k.Amend }},
+		{"amend-edit", func(k *Keymap) string { return k.AmendEdit }},
 		{"push", func(k *Keymap) string { return k.Push }},
+		{"push-menu", func(k *Keymap) string { return k.PushMenu }},
+		{"fetch", func(k *Keymap) string { return k.Fetch }},
+		{"pull", func(k *Keymap) string { return k.Pull }},
+		{"cancel", func(k *Keymap) string { return k.Cancel }},
 		{"stash", func(k *Keymap) string { return k.Stash }},
 		{"stash-all", func(k *Keymap) string { return k.StashAll }},
+		{"resolve", func(k *Keymap) string { return k.Resolve }},
+		{"take-ours", func(k *Keymap) string { return k.TakeOurs }},
+		{"take-theirs", func(k *Keymap) string { return k.TakeTheirs }},
+		{"take-both", func(k *Keymap) string { return k.TakeBoth }},
+		{"continue", func(k *Keymap) string { return k.Continue }},
+		{"skip", func(k *Keymap) string { return k.Skip }},
+		{"abort", func(k *Keymap) string { return k.Abort }},
+		{"rebase", func(k *Keymap) string { return k.Rebase }},
+		{"pick", func(k *Keymap) string { return k.Pick }},
+		{"reword", func(k *Keymap) string { return k.Reword }},
+		{"edit", func(k *Keymap) string { return k.Edit }},
+		{"squash", func(k *Keymap) string { return k.Squash }},
+		{"fixup", func(k *Keymap) string { return k.Fixup }},
+		{"drop", func(k *Keymap) string { return k.Drop }},
+		{"move-up", func(k *Keymap) string { return k.MoveUp }},
+		{"move-down", func(k *Keymap) string { return k.MoveDown }},
 		{"file-diff", func(k *Keymap) string { return k.FileDiff }},
 		{"all-diffs", func(k *Keymap) string { return k.AllDiffs }},
 		{"branches", func(k *Keymap) string { return k.Branches }},
 		{"stashes", func(k *Keymap) string { return k.Stashes }},
 		{"log", func(k *Keymap) string { return k.Log }},
+		{"file-log", func(k *Keymap) string { return k.FileLog }},
+		{"filter", func(k *Keymap) string { return k.Filter }},
 		{"visual", func(k *Keymap) string { return k.Visual }},
 		{"help", func(k *Keymap) string { return k.Help }},
 		{"verbose-help", func(k *Keymap) string { return k.VerboseHelp }},
@@ -1,18 +1,43 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"go-on-git/internal/git"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// logPageSize is how many commits are loaded at a time
+const logPageSize = 200
 
-// LogModel is the bubbletea model for the log view
+// logWindowPages is how many pages are kept loaded around the cursor
+const logWindowPages = 3
+
+// LogModel is the bubbletea model for the log view. Commits are loaded a
+// page at a time as the cursor nears either end of what has been loaded,
+// and pages far from the cursor are dropped, so memory stays bounded
+// however far the log is scrolled. cursor and scrollOffset count from the
+// top of the log; commits and rows start at base.
 type LogModel struct {
-	lines           []string
+	repo            *git.Repo
+	commits         []git.Commit
+	base            int         // index in the log of commits[0], a multiple of logPageSize
+	graph           commitGraph // graph after the last loaded commit
+	checkpoints     [][]string  // graph lanes before each page, to lay out a reloaded page
+	rows            []graphRow  // graph column for each commit
+	upstream        string      // upstream of the current branch, e.g. "origin/main"
+	loaded          bool
+	loadingMore     bool // a page after the first is being loaded
+	exhausted       bool // the last page of the log is loaded
+	filter          git.LogFilter
+	filterMode      bool // editing the filter
+	filterInput     textinput.Model
+	cursor          int
 	scrollOffset    int
 	showHelp        bool
 	showVerboseHelp bool
@@ -22,21 +47,32 @@ type LogModel struct {
 }
 
 // NewLogModel creates a new log model
-func NewLogModel() LogModel {
-	return LogModel{}
+func NewLogModel(repo *git.Repo) LogModel {
+	return NewLogModelWithFilter(repo, 0, 0, false, git.LogFilter{})
 }
 
 // NewLogModelWithSize creates a new log model with dimensions
-func NewLogModelWithSize(width, height int) LogModel {
-	return LogModel{
-		width:  width,
-		height: height,
-	}
+func NewLogModelWithSize(repo *git.Repo, width, height int) LogModel {
+	return NewLogModelWithFilter(repo, width, height, false, git.LogFilter{})
 }
 
 // NewLogModelWithOptions creates a new log model with all options
-func NewLogModelWithOptions(width, height int, showVerboseHelp bool) LogModel {
+func NewLogModelWithOptions(repo *git.Repo, width, height int, showVerboseHelp bool) LogModel {
+	return NewLogModelWithFilter(repo, width, height, showVerboseHelp, git.LogFilter{})
+}
+
+// NewLogModelWithFilter creates a log model showing only commits that
+// match the filter
+func NewLogModelWithFilter(repo *git.Repo, width, height int, showVerboseHelp bool, filter git.LogFilter) LogModel {
+	fi := textinput.New()
+	fi.Placeholder = "--author=name --grep=text -S string --since=date -- path"
+	fi.CharLimit = 200
+	fi.Width = 60
+
 	return LogModel{
+		repo:            repo,
+		filter:          filter,
+		filterInput:     fi,
 		width:           width,
 		height:          height,
 		showVerboseHelp: showVerboseHelp,
@@ -44,20 +80,117 @@ func NewLogModelWithOptions(width, height int, showVerboseHelp bool) LogModel {
 }
 
 type logMsg struct {
-	content string
+	commits  []git.Commit
+	upstream string
+	filter   git.LogFilter // filter the page was loaded with
+	skip     int           // commits before this page
+	paged    bool          // loaded by loadPage; otherwise the page replaces the log
 }
 
-func refreshLog() tea.Msg {
-	content, err := git.GetLog(100)
+func (m LogModel) refreshLog() tea.Msg {
+	ctx := context.Background()
+	commits, err := m.repo.GetSignedLog(ctx, m.filter, 0, logPageSize)
 	if err != nil {
 		return errMsg{err}
 	}
-	return logMsg{content}
+	return logMsg{commits: commits, upstream: m.repo.GetBranchStatus(ctx).Remote, filter: m.filter}
+}
+
+// loadPage returns a command that loads the page after skip commits
+func (m LogModel) loadPage(skip int) tea.Cmd {
+	return func() tea.Msg {
+		commits, err := m.repo.GetSignedLog(context.Background(), m.filter, skip, logPageSize)
+		if err != nil {
+			return errMsg{err}
+		}
+		return logMsg{commits: commits, upstream: m.upstream, filter: m.filter, skip: skip, paged: true}
+	}
+}
+
+// loadMoreIfNeeded starts loading the page before or after those loaded
+// once the cursor is within a screen of either end
+func (m *LogModel) loadMoreIfNeeded() tea.Cmd {
+	if !m.loaded || m.loadingMore {
+		return nil
+	}
+	switch {
+	case m.base > 0 && m.cursor < m.base+m.visibleLines():
+		m.loadingMore = true
+		return m.loadPage(m.base - logPageSize)
+	case !m.exhausted && m.cursor >= m.end()-m.visibleLines():
+		m.loadingMore = true
+		return m.loadPage(m.end())
+	}
+	return nil
+}
+
+// end returns the index in the log after the last loaded commit
+func (m LogModel) end() int {
+	return m.base + len(m.commits)
+}
+
+// appendPage adds a page after those loaded, dropping the first page if
+// there are then too many
+func (m *LogModel) appendPage(commits []git.Commit) {
+	page := m.end() / logPageSize
+	if page == len(m.checkpoints) {
+		m.checkpoints = append(m.checkpoints, slices.Clone(m.graph.lanes))
+	}
+	m.commits = append(m.commits, commits...)
+	// Filters that skip commits leave parents dangling, so there is no
+	// graph to draw
+	if m.filter.KeepsTopology() {
+		for _, c := range commits {
+			m.rows = append(m.rows, m.graph.next(c))
+		}
+	}
+	m.exhausted = len(commits) < logPageSize
+	if len(m.commits) > logWindowPages*logPageSize {
+		// Copy what is kept so the dropped page can be freed
+		m.commits = slices.Clone(m.commits[logPageSize:])
+		if len(m.rows) > 0 {
+			m.rows = slices.Clone(m.rows[logPageSize:])
+		}
+		m.base += logPageSize
+	}
+}
+
+// prependPage adds the page before those loaded, dropping the last page
+// if there are then too many
+func (m *LogModel) prependPage(commits []git.Commit) {
+	if m.filter.KeepsTopology() {
+		graph := commitGraph{lanes: slices.Clone(m.checkpoints[m.base/logPageSize-1])}
+		rows := make([]graphRow, len(commits))
+		for i, c := range commits {
+			rows[i] = graph.next(c)
+		}
+		m.rows = slices.Concat(rows, m.rows)
+	}
+	m.commits = slices.Concat(commits, m.commits)
+	m.base -= logPageSize
+	if len(m.commits) > logWindowPages*logPageSize {
+		last := (len(m.commits) - 1) / logPageSize * logPageSize
+		m.commits = slices.Clone(m.commits[:last])
+		if len(m.rows) > 0 {
+			m.rows = slices.Clone(m.rows[:last])
+		}
+		m.graph = commitGraph{lanes: slices.Clone(m.checkpoints[(m.base+last)/logPageSize])}
+		m.exhausted = false
+	}
+}
+
+// SelectedCommit returns the commit under the cursor
+func (m LogModel) SelectedCommit() (git.Commit, bool) {
+	i := m.cursor - m.base
+	if i < 0 || i >= len(m.commits) {
+		return git.Commit{}, false
+	}
+	return m.commits[i], true
 }
 
 // Init initializes the model
 func (m LogModel) Init() tea.Cmd {
-	return refreshLog
+	return m.refreshLog
 }
 
 // Update handles messages
@@ -74,21 +207,39 @@ func (m LogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Account for header (2 lines) and optionally help bar (2 lines)
-		reservedLines := 4
-		if m.showVerboseHelp {
-			reservedLines = 6
-		}
-		visibleLines := m.height - reservedLines
-		if visibleLines < 1 {
-			visibleLines = 10
-		}
-		maxOffset := len(m.lines) - visibleLines
-		if maxOffset < 0 {
-			maxOffset = 0
+		if m.filterMode {
+			switch key {
+			case "enter":
+				filter, err := git.ParseLogFilter(m.filterInput.Value())
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+				m.filterMode = false
+				m.filterInput.Blur()
+				return m, m.applyFilter(filter)
+			case "esc":
+				m.filterMode = false
+				m.filterInput.Blur()
+				m.err = nil
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.filterInput, cmd = m.filterInput.Update(msg)
+				return m, cmd
+			}
 		}
 
+		visibleLines := m.visibleLines()
+		first, last := m.base, max(m.end()-1, 0)
+
 		switch key {
+		case Keys.Filter:
+			m.filterMode = true
+			m.filterInput.SetValue(m.filter.String())
+			m.filterInput.CursorEnd()
+			m.filterInput.Focus()
+			return m, textinput.Blink
 		case Keys.Help:
 			m.showHelp = true
 			return m, nil
@@ -96,24 +247,28 @@ func (m LogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.showVerboseHelp = !m.showVerboseHelp
 			return m, nil
 		case Keys.Down, "down":
-			m.scrollOffset = min(m.scrollOffset+1, maxOffset)
-			return m, nil
+			m.cursor = min(m.cursor+1, last)
 		case Keys.Up, "up":
-			m.scrollOffset = max(m.scrollOffset-1, 0)
-			return m, nil
+			m.cursor = max(m.cursor-1, first)
 		case Keys.Bottom:
-			m.scrollOffset = maxOffset
-			return m, nil
+			m.cursor = last
 		case Keys.Top:
-			m.scrollOffset = 0
-			return m, nil
+			m.cursor = 0
+			if m.base > 0 {
+				// The top of the log has been dropped: load it again
+				m.scrollOffset = 0
+				m.loadingMore = true
+				return m, m.refreshLog
+			}
 		case "ctrl+d":
-			m.scrollOffset = min(m.scrollOffset+visibleLines/2, maxOffset)
-			return m, nil
+			m.cursor = min(m.cursor+visibleLines/2, last)
 		case "ctrl+u":
-			m.scrollOffset = max(m.scrollOffset-visibleLines/2, 0)
+			m.cursor = max(m.cursor-visibleLines/2, first)
+		default:
 			return m, nil
 		}
+		m.ensureCursorVisible()
+		return m, m.loadMoreIfNeeded()
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -121,17 +276,78 @@ func (m LogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case logMsg:
-		m.lines = strings.Split(msg.content, "\n")
-		return m, nil
+		if msg.filter.String() != m.filter.String() {
+			// Page for a filter that has since been replaced
+			return m, nil
+		}
+		switch {
+		case !msg.paged:
+			m.commits = nil
+			m.rows = nil
+			m.base = 0
+			m.graph = commitGraph{}
+			m.checkpoints = nil
+			m.appendPage(msg.commits)
+		case msg.skip == m.end():
+			m.appendPage(msg.commits)
+		case msg.skip == m.base-logPageSize:
+			m.prependPage(msg.commits)
+		default:
+			// Stale page from before a reload
+			return m, nil
+		}
+		m.upstream = msg.upstream
+		m.loaded = true
+		m.loadingMore = false
+		m.cursor = min(max(m.cursor, m.base), max(m.end()-1, 0))
+		m.ensureCursorVisible()
+		return m, m.loadMoreIfNeeded()
 
 	case errMsg:
 		m.err = msg.err
+		m.loadingMore = false
 		return m, nil
 	}
 
 	return m, nil
 }
 
+// applyFilter reloads the log from the top with a new filter
+func (m *LogModel) applyFilter(filter git.LogFilter) tea.Cmd {
+	m.filter = filter
+	m.err = nil
+	m.cursor = 0
+	m.scrollOffset = 0
+	m.loadingMore = false
+	m.exhausted = false
+	return m.refreshLog
+}
+
+// visibleLines returns the number of commits that fit on screen
+func (m LogModel) visibleLines() int {
+	// Account for header (2 lines) and optionally help bar or filter prompt (2 lines)
+	reservedLines := 4
+	if m.showVerboseHelp || m.filterMode {
+		reservedLines = 6
+	}
+	visibleLines := m.height - reservedLines
+	if visibleLines < 1 {
+		visibleLines = 20
+	}
+	return visibleLines
+}
+
+// ensureCursorVisible adjusts scrollOffset to keep the cursor in view
+func (m *LogModel) ensureCursorVisible() {
+	visible := m.visibleLines()
+	if m.cursor < m.scrollOffset {
+		m.scrollOffset = m.cursor
+	}
+	if m.cursor >= m.scrollOffset+visible {
+		m.scrollOffset = m.cursor - visible + 1
+	}
+}
+
 // View renders the log view
 func (m LogModel) View() string {
 	if m.showHelp {
@@ -147,60 +363,102 @@ func (m LogModel) View() string {
 	if m.err != nil {
 		content.WriteString(StyleUnstaged.Render(fmt.Sprintf("Error: %v", m.err)))
 		content.WriteString("\n")
-		if m.showVerboseHelp {
-			content.WriteString("\n")
-			content.WriteString(m.renderHelpBar())
-		}
+		content.WriteString(m.renderFooter())
 		return m.anchorBottom(content.String())
 	}
 
-	if len(m.lines) == 0 {
-		content.WriteString(StyleMuted.Render("Loading..."))
-		content.WriteString("\n")
-		if m.showVerboseHelp {
-			content.WriteString("\n")
-			content.WriteString(m.renderHelpBar())
+	if !m.loaded || len(m.commits) == 0 {
+		if m.loaded && !m.filter.IsEmpty() {
+			content.WriteString(StyleEmpty.Render("No matching commits"))
+		} else if m.loaded {
+			content.WriteString(StyleEmpty.Render("No commits yet"))
+		} else {
+			content.WriteString(StyleMuted.Render("Loading..."))
 		}
+		content.WriteString("\n")
+		content.WriteString(m.renderFooter())
 		return m.anchorBottom(content.String())
 	}
 
-	// Account for header (2 lines) and optionally help bar (2 lines)
-	reservedLines := 4
-	if m.showVerboseHelp {
-		reservedLines = 6
-	}
-	visibleLines := m.height - reservedLines
-	if visibleLines < 1 {
-		visibleLines = 20
-	}
+	// Rows of the loaded commits on screen
+	startIdx := max(m.scrollOffset, m.base) - m.base
+	endIdx := min(m.scrollOffset+m.visibleLines(), m.end()) - m.base
 
-	endIdx := m.scrollOffset + visibleLines
-	if endIdx > len(m.lines) {
-		endIdx = len(m.lines)
+	// Pad the graph to the widest visible row so subjects line up
+	graphWidth := 0
+	for i := startIdx; i < endIdx && i < len(m.rows); i++ {
+		graphWidth = max(graphWidth, m.rows[i].lanes())
 	}
 
-	for i := m.scrollOffset; i < endIdx; i++ {
-		line := m.lines[i]
-		if strings.HasPrefix(line, "commit ") {
-			content.WriteString(StyleStaged.Render(line))
-		} else if strings.HasPrefix(line, "Author:") || strings.HasPrefix(line, "Date:") {
-			content.WriteString(StyleMuted.Render(line))
-		} else {
-			content.WriteString(line)
+	for i := startIdx; i < endIdx; i++ {
+		cursor := "  "
+		if m.base+i == m.cursor {
+			cursor = "> "
+		}
+		content.WriteString(cursor)
+		if i < len(m.rows) {
+			content.WriteString(m.rows[i].render(graphWidth))
 		}
+		content.WriteString(m.renderCommitLine(m.commits[i]))
 		content.WriteString("\n")
 	}
 
-	if m.showVerboseHelp {
-		content.WriteString("\n")
-		content.WriteString(m.renderHelpBar())
-	}
+	content.WriteString(m.renderFooter())
 
 	return m.anchorBottom(content.String())
 }
 
+// renderCommitLine renders a commit as a single log line
+func (m LogModel) renderCommitLine(c git.Commit) string {
+	var sb strings.Builder
+	sb.WriteString(StyleCommitHash.Render(c.ShortHash()))
+	sb.WriteString(" ")
+	sb.WriteString(signatureBadge(c.Signature))
+	sb.WriteString(" ")
+	if len(c.Refs) > 0 {
+		sb.WriteString(renderRefs(c.Refs, m.upstream))
+		sb.WriteString(" ")
+	}
+	sb.WriteString(c.Subject)
+	sb.WriteString(StyleMuted.Render(fmt.Sprintf("  %s, %s", c.AuthorName, c.AuthorDate.Format("2006-01-02"))))
+	return sb.String()
+}
+
+// renderRefs renders ref decorations the way git log does, styled by kind
+func renderRefs(refs []git.Ref, upstream string) string {
+	parts := make([]string, len(refs))
+	for i, ref := range refs {
+		parts[i] = renderRef(ref, upstream)
+	}
+	return StyleMuted.Render("(") + strings.Join(parts, StyleMuted.Render(", ")) + StyleMuted.Render(")")
+}
+
+func renderRef(ref git.Ref, upstream string) string {
+	switch {
+	case ref.Head:
+		return StyleRefHead.Render("HEAD ->") + " " + StyleRefBranch.Render(ref.Name)
+	case ref.Kind == git.RefHead:
+		return StyleRefHead.Render(ref.Name)
+	case ref.Kind == git.RefTag:
+		return StyleRefTag.Render(ref.String())
+	case ref.Kind == git.RefRemote && ref.Name == upstream:
+		return StyleRefUpstream.Render(ref.Name)
+	case ref.Kind == git.RefRemote:
+		return StyleRefRemote.Render(ref.Name)
+	}
+	return StyleRefBranch.Render(ref.Name)
+}
+
 func (m LogModel) renderHeader() string {
-	return StyleMuted.Render("> git log") + "  " + StyleMuted.Render("(esc to go back)") + "\n" + StyleMuted.Render("───────────────────────────────────────────────────────────────")
+	header := StyleMuted.Render("> git log")
+	if !m.filter.IsEmpty() {
+		header += " " + StyleHelpKey.Render(m.filter.String())
+	}
+	header += "  " + StyleMuted.Render("(esc to go back)")
+	if m.loadingMore {
+		header += "  " + StyleMuted.Render("Loading more commits...")
+	}
+	return header + "\n" + StyleMuted.Render("───────────────────────────────────────────────────────────────")
 }
 
 func (m LogModel) anchorBottom(content string) string {
@@ -212,6 +470,17 @@ func (m LogModel) anchorBottom(content string) string {
 	return strings.Repeat("\n", padding) + content
 }
 
+// renderFooter renders the filter prompt or, if enabled, the help bar
+func (m LogModel) renderFooter() string {
+	if m.filterMode {
+		return "\nFilter: " + m.filterInput.View() + StyleMuted.Render("  (enter to apply, esc to cancel)")
+	}
+	if m.showVerboseHelp {
+		return "\n" + m.renderHelpBar()
+	}
+	return ""
+}
+
 func (m LogModel) renderHelpBar() string {
 	var sb strings.Builder
 
@@ -219,7 +488,10 @@ func (m LogModel) renderHelpBar() string {
 	sb.WriteString("\n")
 
 	items := []struct{ key, desc string }{
-		{formatKeyList(Keys.Down, Keys.Up), "scroll"},
+		{formatKeyList(Keys.Down, Keys.Up), "navigate"},
+		{formatKeyList(Keys.Right, "Enter"), "view commit"},
+		{Keys.Filter, "filter"},
+		{Keys.Rebase, "rebase"},
 		{formatKeyList(Keys.Top, Keys.Bottom), "top/bottom"},
 		{"ctrl+d/u", "page down/up"},
 		{Keys.Help, "help"},
@@ -245,12 +517,16 @@ func (m LogModel) renderHelp() string {
 	moveKeys := formatKeyList(Keys.Down, Keys.Up, "↓", "↑")
 	topKey := formatDoubleKey(Keys.Top)
 	backKeys := formatKeyList(Keys.Left, "←", "ESC")
+	drillKeys := formatKeyList(Keys.Right, "→", "Enter")
 
 	help := []struct {
 		key  string
 		desc string
 	}{
-		{moveKeys, "Scroll down/up"},
+		{moveKeys, "Navigate commits"},
+		{drillKeys, "View commit and its changes"},
+		{Keys.Filter, "Filter by --author, --grep, -S/-G, --since/--until, paths"},
+		{Keys.Rebase, "Interactive rebase onto the selected commit"},
 		{topKey, "Go to top"},
 		{Keys.Bottom, "Go to bottom"},
 		{"ctrl+d", "Page down"},
@@ -2,14 +2,33 @@ package ui
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"go-on-git/internal/git"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// testCommits returns n commits with distinct hashes and subjects
+func testCommits(n int) []git.Commit {
+	commits := make([]git.Commit, n)
+	for i := range commits {
+		commits[i] = git.Commit{
+			Hash:       fmt.Sprintf("%040d", i),
+			Subject:    fmt.Sprintf("Commit %d", i),
+			AuthorName: "Test User",
+			AuthorDate: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	}
+	return commits
+}
+
 func TestNewLogModel(t *testing.T) {
-	m := NewLogModel()
+	m := NewLogModel(nil)
 
 	if m.scrollOffset != 0 {
 		t.Errorf("scrollOffset = %d, want 0", m.scrollOffset)
@@ -20,13 +39,13 @@ func TestNewLogModel(t *testing.T) {
 	if m.height != 0 {
 		t.Errorf("height = %d, want 0", m.height)
 	}
-	if len(m.lines) != 0 {
-		t.Errorf("lines should be empty, got %d", len(m.lines))
+	if len(m.commits) != 0 {
+		t.Errorf("commits should be empty, got %d", len(m.commits))
 	}
 }
 
 func TestNewLogModelWithSize(t *testing.T) {
-	m := NewLogModelWithSize(100, 50)
+	m := NewLogModelWithSize(nil, 100, 50)
 
 	if m.width != 100 {
 		t.Errorf("width = %d, want 100", m.width)
@@ -37,7 +56,7 @@ func TestNewLogModelWithSize(t *testing.T) {
 }
 
 func TestLogModelInit(t *testing.T) {
-	m := NewLogModel()
+	m := NewLogModel(nil)
 	cmd := m.Init()
 
 	if cmd == nil {
@@ -46,33 +65,36 @@ func TestLogModelInit(t *testing.T) {
 }
 
 func TestLogModelNavigation(t *testing.T) {
-	m := NewLogModelWithSize(100, 10)
-	m.lines = make([]string, 100)
+	m := NewLogModelWithSize(nil, 100, 10)
+	m.commits = testCommits(100)
 
-	// Test scroll down
+	// Test move down
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
 	m = newModel.(LogModel)
-	if m.scrollOffset != 1 {
-		t.Errorf("after 'j', scrollOffset = %d, want 1", m.scrollOffset)
+	if m.cursor != 1 {
+		t.Errorf("after 'j', cursor = %d, want 1", m.cursor)
 	}
 
-	// Test scroll up
+	// Test move up
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
 	m = newModel.(LogModel)
-	if m.scrollOffset != 0 {
-		t.Errorf("after 'k', scrollOffset = %d, want 0", m.scrollOffset)
+	if m.cursor != 0 {
+		t.Errorf("after 'k', cursor = %d, want 0", m.cursor)
 	}
 
-	// Test can't scroll above 0
+	// Test can't move above 0
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
 	m = newModel.(LogModel)
-	if m.scrollOffset != 0 {
-		t.Errorf("scrollOffset should stay at 0, got %d", m.scrollOffset)
+	if m.cursor != 0 {
+		t.Errorf("cursor should stay at 0, got %d", m.cursor)
 	}
 
-	// Test jump to bottom
+	// Test jump to bottom scrolls the last commit into view
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
 	m = newModel.(LogModel)
+	if m.cursor != 99 {
+		t.Errorf("after 'G', cursor = %d, want 99", m.cursor)
+	}
 	if m.scrollOffset == 0 {
 		t.Error("scrollOffset should be > 0 after 'G'")
 	}
@@ -80,56 +102,54 @@ func TestLogModelNavigation(t *testing.T) {
 	// Test jump to top
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
 	m = newModel.(LogModel)
-	if m.scrollOffset != 0 {
-		t.Errorf("after 'g', scrollOffset = %d, want 0", m.scrollOffset)
+	if m.cursor != 0 || m.scrollOffset != 0 {
+		t.Errorf("after 'g', cursor = %d, scrollOffset = %d, want 0, 0", m.cursor, m.scrollOffset)
 	}
 }
 
 func TestLogModelPageNavigation(t *testing.T) {
-	m := NewLogModelWithSize(100, 20)
-	m.lines = make([]string, 100)
+	m := NewLogModelWithSize(nil, 100, 20)
+	m.commits = testCommits(100)
 
 	// Test ctrl+d (half page down)
-	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}, Alt: false})
-	// Need to use the actual key type for ctrl+d
-	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
 	m = newModel.(LogModel)
-	if m.scrollOffset == 0 {
-		t.Error("scrollOffset should be > 0 after ctrl+d")
+	if m.cursor == 0 {
+		t.Error("cursor should be > 0 after ctrl+d")
 	}
 
-	// Save current offset
-	currentOffset := m.scrollOffset
+	// Save current position
+	current := m.cursor
 
 	// Test ctrl+u (half page up)
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
 	m = newModel.(LogModel)
-	if m.scrollOffset >= currentOffset {
-		t.Error("scrollOffset should decrease after ctrl+u")
+	if m.cursor >= current {
+		t.Error("cursor should decrease after ctrl+u")
 	}
 }
 
 func TestLogModelArrowKeys(t *testing.T) {
-	m := NewLogModelWithSize(100, 10)
-	m.lines = make([]string, 50)
+	m := NewLogModelWithSize(nil, 100, 10)
+	m.commits = testCommits(50)
 
 	// Test down arrow
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
 	m = newModel.(LogModel)
-	if m.scrollOffset != 1 {
-		t.Errorf("after down arrow, scrollOffset = %d, want 1", m.scrollOffset)
+	if m.cursor != 1 {
+		t.Errorf("after down arrow, cursor = %d, want 1", m.cursor)
 	}
 
 	// Test up arrow
 	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
 	m = newModel.(LogModel)
-	if m.scrollOffset != 0 {
-		t.Errorf("after up arrow, scrollOffset = %d, want 0", m.scrollOffset)
+	if m.cursor != 0 {
+		t.Errorf("after up arrow, cursor = %d, want 0", m.cursor)
 	}
 }
 
 func TestLogModelWindowResize(t *testing.T) {
-	m := NewLogModel()
+	m := NewLogModel(nil)
 
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
 	m = newModel.(LogModel)
@@ -143,20 +163,23 @@ func TestLogModelWindowResize(t *testing.T) {
 }
 
 func TestLogModelLogMsg(t *testing.T) {
-	m := NewLogModel()
+	m := NewLogModel(nil)
 
-	content := "commit abc123\nAuthor: Test\nDate: Today\n\n    Message"
+	m.cursor = 10
 
-	newModel, _ := m.Update(logMsg{content: content})
+	newModel, _ := m.Update(logMsg{commits: testCommits(5)})
 	m = newModel.(LogModel)
 
-	if len(m.lines) != 5 {
-		t.Errorf("len(lines) = %d, want 5", len(m.lines))
+	if len(m.commits) != 5 {
+		t.Errorf("len(commits) = %d, want 5", len(m.commits))
+	}
+	if m.cursor != 4 {
+		t.Errorf("cursor should be clamped to the last commit, got %d", m.cursor)
 	}
 }
 
 func TestLogModelErrMsg(t *testing.T) {
-	m := NewLogModel()
+	m := NewLogModel(nil)
 
 	newModel, _ := m.Update(errMsg{err: fmt.Errorf("test error")})
 	m = newModel.(LogModel)
@@ -167,38 +190,57 @@ func TestLogModelErrMsg(t *testing.T) {
 }
 
 func TestLogModelView(t *testing.T) {
-	m := NewLogModelWithSize(100, 20)
-	m.lines = []string{
-		"commit abc123",
-		"Author: Test User <test@example.com>",
-		"Date:   Mon Jan 1 00:00:00 2024",
-		"",
-		"    Initial commit",
-	}
+	m := NewLogModelWithSize(nil, 100, 20)
+	m.loaded = true
+	m.commits = []git.Commit{{
+		Hash:       "abc1234def5678",
+		Subject:    "Initial commit",
+		AuthorName: "Test User",
+		AuthorDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Refs: []git.Ref{
+			{Name: "main", Kind: git.RefBranch, Head: true},
+			{Name: "v1.0", Kind: git.RefTag},
+		},
+	}}
 
 	view := m.View()
 
-	if !strings.Contains(view, "commit") {
-		t.Error("view should contain 'commit'")
+	for _, want := range []string{"> ", "abc1234", "(HEAD -> main, tag: v1.0)", "Initial commit", "Test User, 2024-01-01"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
 	}
-	if !strings.Contains(view, "Author:") {
-		t.Error("view should contain 'Author:'")
+	if strings.Contains(view, "abc1234def") {
+		t.Error("view should show the short hash")
 	}
 }
 
 func TestLogModelViewLoading(t *testing.T) {
-	m := NewLogModelWithSize(100, 20)
-	m.lines = nil
+	m := NewLogModelWithSize(nil, 100, 20)
 
 	view := m.View()
 
 	if !strings.Contains(view, "Loading") {
-		t.Error("view should show 'Loading' when lines is empty")
+		t.Error("view should show 'Loading' before the log is loaded")
+	}
+}
+
+func TestLogModelViewNoCommits(t *testing.T) {
+	m := NewLogModelWithSize(nil, 100, 20)
+
+	newModel, _ := m.Update(logMsg{})
+	m = newModel.(LogModel)
+
+	if !strings.Contains(m.View(), "No commits yet") {
+		t.Error("view should say there are no commits in an empty repository")
+	}
+	if _, ok := m.SelectedCommit(); ok {
+		t.Error("no commit should be selected")
 	}
 }
 
 func TestLogModelViewWithError(t *testing.T) {
-	m := NewLogModelWithSize(100, 20)
+	m := NewLogModelWithSize(nil, 100, 20)
 	m.err = fmt.Errorf("test error")
 
 	view := m.View()
@@ -209,13 +251,10 @@ func TestLogModelViewWithError(t *testing.T) {
 }
 
 func TestLogModelViewStyling(t *testing.T) {
-	m := NewLogModelWithSize(100, 30)
-	m.lines = []string{
-		"commit abc123",
-		"Author: Test User",
-		"Date: Today",
-		"Regular line",
-	}
+	m := NewLogModelWithSize(nil, 100, 30)
+	m.loaded = true
+	m.commits = testCommits(4)
+	m.commits[0].Refs = []git.Ref{{Name: "main", Kind: git.RefBranch, Head: true}}
 
 	view := m.View()
 
@@ -226,21 +265,24 @@ func TestLogModelViewStyling(t *testing.T) {
 }
 
 func TestLogModelScrollBounds(t *testing.T) {
-	m := NewLogModelWithSize(100, 10)
-	m.lines = make([]string, 5) // Fewer lines than visible
+	m := NewLogModelWithSize(nil, 100, 10)
+	m.commits = testCommits(5) // Fewer commits than visible
 
 	// Scroll down should not go negative
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
 	m = newModel.(LogModel)
 
-	// maxOffset should be 0 when lines fit in view
-	if m.scrollOffset < 0 {
-		t.Errorf("scrollOffset should not be negative, got %d", m.scrollOffset)
+	// No scrolling when commits fit in view
+	if m.scrollOffset != 0 {
+		t.Errorf("scrollOffset should stay 0, got %d", m.scrollOffset)
+	}
+	if m.cursor != 4 {
+		t.Errorf("cursor = %d, want 4", m.cursor)
 	}
 }
 
 func TestLogModelAnchorBottom(t *testing.T) {
-	m := NewLogModelWithSize(100, 20)
+	m := NewLogModelWithSize(nil, 100, 20)
 
 	content := "line1\nline2\n"
 	anchored := m.anchorBottom(content)
@@ -252,8 +294,9 @@ func TestLogModelAnchorBottom(t *testing.T) {
 }
 
 func TestLogModelSmallHeight(t *testing.T) {
-	m := NewLogModelWithSize(100, 0)
-	m.lines = make([]string, 50)
+	m := NewLogModelWithSize(nil, 100, 0)
+	m.loaded = true
+	m.commits = testCommits(50)
 
 	// Should use default visible lines
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
@@ -268,8 +311,8 @@ func TestLogModelSmallHeight(t *testing.T) {
 
 func TestLogModelVisibleLinesCalculation(t *testing.T) {
 	// When height is very small, visibleLines should have a minimum
-	m := NewLogModelWithSize(100, 2)
-	m.lines = make([]string, 100)
+	m := NewLogModelWithSize(nil, 100, 2)
+	m.commits = testCommits(100)
 
 	// visibleLines = height - 2, minimum 1 for display
 	// This is handled in the Update function
@@ -283,18 +326,297 @@ func TestLogModelVisibleLinesCalculation(t *testing.T) {
 }
 
 func TestLogModelViewWithManyLines(t *testing.T) {
-	m := NewLogModelWithSize(100, 20)
-	lines := make([]string, 100)
-	for i := 0; i < 100; i++ {
-		lines[i] = fmt.Sprintf("Line %d", i)
-	}
-	m.lines = lines
+	m := NewLogModelWithSize(nil, 100, 20)
+	m.loaded = true
+	m.commits = testCommits(100)
+	m.cursor = 50
 	m.scrollOffset = 50
 
 	view := m.View()
 
-	// Should show lines around offset 50
-	if !strings.Contains(view, "Line 50") && !strings.Contains(view, "Line 51") {
-		t.Error("view should show lines near scroll offset")
+	// Should show commits from offset 50
+	if !strings.Contains(view, "Commit 50") || strings.Contains(view, "Commit 49") {
+		t.Error("view should show commits from the scroll offset")
+	}
+}
+
+func TestLogModelSelectedCommit(t *testing.T) {
+	m := NewLogModelWithSize(nil, 100, 20)
+	m.commits = testCommits(3)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m = newModel.(LogModel)
+
+	commit, ok := m.SelectedCommit()
+	if !ok || commit.Subject != "Commit 1" {
+		t.Errorf("expected Commit 1 to be selected, got %q", commit.Subject)
+	}
+}
+
+func TestLogModelGraph(t *testing.T) {
+	m := NewLogModelWithSize(nil, 100, 20)
+	commits := []git.Commit{
+		{Hash: "m", Parents: []string{"b", "c"}, Subject: "Merge c",
+			Refs: []git.Ref{{Name: "origin/main", Kind: git.RefRemote}}},
+		{Hash: "b", Parents: []string{"a"}, Subject: "On main"},
+		{Hash: "c", Parents: []string{"a"}, Subject: "On branch"},
+		{Hash: "a", Subject: "Root"},
+	}
+
+	newModel, _ := m.Update(logMsg{commits: commits, upstream: "origin/main"})
+	m = newModel.(LogModel)
+
+	if len(m.rows) != len(commits) {
+		t.Fatalf("expected a graph row per commit, got %d", len(m.rows))
+	}
+	if m.upstream != "origin/main" {
+		t.Errorf("upstream = %q, want origin/main", m.upstream)
+	}
+
+	view := m.View()
+	for _, want := range []string{"> ●─┐ m · (origin/main) Merge c", "  │ ● c · On branch", "  ●─┘ a · Root"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q, got:\n%s", want, view)
+		}
+	}
+}
+
+// logFormatArg is the --format argument GetLog passes to git log
+const logFormatArg = "--format=%H%x1f%P%x1f%an%x1f%ae%x1f%at%x1f%cn%x1f%ce%x1f%ct%x1f%D%x1f%x1f%x1f%x1f%s%x1f%b%x1e"
+
+// signedLogFormatArg is the --format argument GetSignedLog passes to git log
+const signedLogFormatArg = "--format=%H%x1f%P%x1f%an%x1f%ae%x1f%at%x1f%cn%x1f%ce%x1f%ct%x1f%D%x1f%G?%x1f%GS%x1f%GK%x1f%s%x1f%b%x1e"
+
+// logPageOutput formats n commits starting at start the way GetLog reads them
+func logPageOutput(start, n int) string {
+	var sb strings.Builder
+	for i := start; i < start+n; i++ {
+		fmt.Fprintf(&sb, "%040d\x1f%040d\x1fA\x1fa@x\x1f0\x1fA\x1fa@x\x1f0\x1f\x1fN\x1f\x1f\x1fCommit %d\x1f\x1e\n", i, i+1, i)
+	}
+	return sb.String()
+}
+
+func TestLogModelLoadsMorePages(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("rev-parse HEAD", git.Result{Stdout: "abc\n"})
+	fake.on(fmt.Sprintf("log --skip=%d --max-count=%d --decorate=full %s", logPageSize, logPageSize, signedLogFormatArg), git.Result{Stdout: logPageOutput(logPageSize, 3)})
+
+	m := NewLogModelWithSize(repo, 100, 20)
+	newModel, cmd := m.Update(logMsg{commits: testCommits(logPageSize)})
+	m = newModel.(LogModel)
+	if cmd != nil || m.loadingMore {
+		t.Fatal("should not load more while the cursor is far from the end")
+	}
+
+	// Jumping to the bottom starts loading the next page
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	m = newModel.(LogModel)
+	if cmd == nil || !m.loadingMore {
+		t.Fatal("should load the next page near the end of the log")
+	}
+	if !strings.Contains(m.View(), "Loading more commits") {
+		t.Error("view should show that more commits are loading")
+	}
+
+	// Another key press doesn't start a second load
+	newModel, again := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	m = newModel.(LogModel)
+	if again != nil {
+		t.Error("should not load the same page twice")
+	}
+
+	newModel, cmd = m.Update(cmd())
+	m = newModel.(LogModel)
+	if len(m.commits) != logPageSize+3 || len(m.rows) != len(m.commits) {
+		t.Fatalf("expected the page to be appended, got %d commits, %d rows", len(m.commits), len(m.rows))
+	}
+	if m.commits[logPageSize].Subject != fmt.Sprintf("Commit %d", logPageSize) {
+		t.Errorf("unexpected first commit of the page: %q", m.commits[logPageSize].Subject)
+	}
+	if !m.exhausted || m.loadingMore || cmd != nil {
+		t.Error("a short page should end loading")
+	}
+}
+
+func TestLogModelKeepsAWindowOfPages(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("rev-parse HEAD", git.Result{Stdout: "abc\n"})
+	const pages = 8
+	for page := range pages {
+		fake.on(fmt.Sprintf("log --skip=%d --max-count=%d --decorate=full %s", page*logPageSize, logPageSize, signedLogFormatArg),
+			git.Result{Stdout: logPageOutput(page*logPageSize, logPageSize)})
+	}
+	fake.on(fmt.Sprintf("log --skip=%d --max-count=%d --decorate=full %s", pages*logPageSize, logPageSize, signedLogFormatArg), git.Result{})
+
+	m := NewLogModelWithSize(repo, 100, 20)
+	newModel, _ := m.Update(m.loadPage(0)())
+	m = newModel.(LogModel)
+	m.loadingMore = false
+	firstRows := slices.Clone(m.rows)
+
+	// press sends a key and loads any page it asks for
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		newModel, cmd := m.Update(key)
+		m = newModel.(LogModel)
+		for cmd != nil {
+			newModel, cmd = m.Update(cmd())
+			m = newModel.(LogModel)
+		}
+		if len(m.commits) > logWindowPages*logPageSize || len(m.rows) != len(m.commits) {
+			t.Fatalf("expected at most %d pages loaded, got %d commits, %d rows", logWindowPages, len(m.commits), len(m.rows))
+		}
+		if c, ok := m.SelectedCommit(); !ok || c.Subject != fmt.Sprintf("Commit %d", m.cursor) {
+			t.Fatalf("cursor %d is on %q", m.cursor, c.Subject)
+		}
+	}
+
+	ctrlD := tea.KeyMsg{Type: tea.KeyCtrlD}
+	for m.cursor < pages*logPageSize-1 {
+		press(ctrlD)
+	}
+	if !m.exhausted || m.base != (pages-logWindowPages)*logPageSize {
+		t.Fatalf("expected the last %d pages loaded, got base %d, exhausted %v", logWindowPages, m.base, m.exhausted)
+	}
+
+	// Scrolling back up reloads the dropped pages with the same graph
+	ctrlU := tea.KeyMsg{Type: tea.KeyCtrlU}
+	for m.cursor > 0 {
+		press(ctrlU)
+	}
+	if m.base != 0 || m.exhausted {
+		t.Fatalf("expected the first pages loaded, got base %d, exhausted %v", m.base, m.exhausted)
+	}
+	if !reflect.DeepEqual(m.rows[:logPageSize], firstRows) {
+		t.Error("a reloaded page should have the graph it was first drawn with")
+	}
+	if !strings.Contains(m.View(), "> ") {
+		t.Errorf("view should show the cursor, got:\n%s", m.View())
+	}
+
+	// Jumping to the top once it has been dropped loads it again
+	for m.base == 0 {
+		press(ctrlD)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	if m.base != 0 || m.cursor != 0 || len(m.commits) != logPageSize {
+		t.Errorf("g should reload the top of the log, got base %d, cursor %d, %d commits", m.base, m.cursor, len(m.commits))
+	}
+}
+
+func TestLogModelIgnoresStalePage(t *testing.T) {
+	m := NewLogModelWithSize(nil, 100, 20)
+	newModel, _ := m.Update(logMsg{commits: testCommits(5)})
+	m = newModel.(LogModel)
+
+	newModel, _ = m.Update(logMsg{commits: testCommits(3), skip: 200, paged: true})
+	m = newModel.(LogModel)
+	if len(m.commits) != 5 {
+		t.Errorf("page for a different offset should be ignored, got %d commits", len(m.commits))
+	}
+}
+
+func TestLogModelFilterPrompt(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("rev-parse HEAD", git.Result{Stdout: "abc\n"})
+	fake.on(fmt.Sprintf("log --skip=0 --max-count=%d --decorate=full %s --author=alice --parents -- src", logPageSize, signedLogFormatArg),
+		git.Result{Stdout: logPageOutput(0, 2)})
+
+	m := NewLogModelWithSize(repo, 100, 20)
+	newModel, _ := m.Update(logMsg{commits: testCommits(10)})
+	m = newModel.(LogModel)
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = newModel.(LogModel)
+	if !m.filterMode {
+		t.Fatal("F should open the filter prompt")
+	}
+	for _, r := range "--author=alice src" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(LogModel)
+	}
+	if !strings.Contains(m.View(), "Filter: ") {
+		t.Error("view should show the filter prompt")
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(LogModel)
+	if m.filterMode || cmd == nil {
+		t.Fatal("enter should apply the filter and reload the log")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(LogModel)
+
+	if len(m.commits) != 2 {
+		t.Errorf("expected the filtered log, got %d commits", len(m.commits))
+	}
+	if len(m.rows) != 0 {
+		t.Error("author filters should not draw a graph")
+	}
+	if !strings.Contains(m.View(), "> git log --author=alice -- src") {
+		t.Errorf("header should show the active filter, got:\n%s", m.View())
+	}
+}
+
+func TestLogModelFilterErrors(t *testing.T) {
+	m := NewLogModelWithSize(nil, 100, 20)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = newModel.(LogModel)
+	for _, r := range "--bogus" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(LogModel)
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(LogModel)
+	if !m.filterMode || cmd != nil || m.err == nil {
+		t.Error("an invalid filter should keep the prompt open with an error")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(LogModel)
+	if m.filterMode || m.err != nil {
+		t.Error("esc should close the prompt and clear the error")
+	}
+}
+
+func TestLogModelIgnoresPageForOldFilter(t *testing.T) {
+	m := NewLogModelWithFilter(nil, 100, 20, false, git.LogFilter{Author: "alice"})
+
+	newModel, _ := m.Update(logMsg{commits: testCommits(3)})
+	m = newModel.(LogModel)
+	if len(m.commits) != 0 {
+		t.Error("a page loaded without the current filter should be ignored")
+	}
+}
+
+func TestAppModelFileLog(t *testing.T) {
+	m := NewAppModel(nil)
+	m.status.items = []StatusItem{
+		{File: git.FileStatus{Path: "src/main.go"}, Section: "unstaged"},
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	m = newModel.(AppModel)
+
+	if m.mode != viewLog {
+		t.Fatalf("mode = %v, want viewLog", m.mode)
+	}
+	if cmd == nil {
+		t.Error("should return commands for loading the log")
+	}
+	want := git.LogFilter{Paths: []string{"src/main.go"}, Follow: true}
+	if m.log.filter.String() != want.String() {
+		t.Errorf("filter = %q, want %q", m.log.filter.String(), want.String())
+	}
+
+	// Typing into the filter prompt doesn't leave the log
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = newModel.(AppModel)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = newModel.(AppModel)
+	if m.mode != viewLog {
+		t.Error("q typed into the filter prompt should not leave the log")
 	}
 }
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"go-on-git/internal/git"
+
+	"github.com/charmbracelet/bubbles/textarea"
+)
+
+// messageEditorHeight is the number of lines the inline message editor shows
+const messageEditorHeight = 6
+
+// newMessageEditor returns a multi-line commit message editor. Its first
+// line is the subject.
+func newMessageEditor() textarea.Model {
+	e := textarea.New()
+	e.Placeholder = "Subject line, then a blank line and the body"
+	e.ShowLineNumbers = false
+	e.CharLimit = 0
+	e.SetHeight(messageEditorHeight)
+	return e
+}
+
+// messageEditorWidth returns the editor width for a window, which follows
+// the window but stays within the body's line length
+func messageEditorWidth(windowWidth int) int {
+	return max(min(windowWidth-4, git.BodyLineLimit), 20)
+}
+
+// renderMessageEditor renders the editor at width, followed by the subject
+// length and any lint warnings for the message as typed
+func renderMessageEditor(editor textarea.Model, width int) string {
+	editor.SetWidth(messageEditorWidth(width))
+
+	var sb strings.Builder
+	sb.WriteString(editor.View())
+	sb.WriteString("\n")
+	subject, _, _ := strings.Cut(git.StripComments(editor.Value()), "\n")
+	gauge := fmt.Sprintf("subject %d/%d", utf8.RuneCountInString(subject), git.SubjectLimit)
+	if utf8.RuneCountInString(subject) > git.SubjectLimit {
+		sb.WriteString(StyleUntracked.Render(gauge))
+	} else {
+		sb.WriteString(StyleMuted.Render(gauge))
+	}
+	for _, warning := range git.LintCommitMessage(editor.Value()) {
+		sb.WriteString("\n")
+		sb.WriteString(StyleUntracked.Render("! " + warning))
+	}
+	return sb.String()
+}
+
+// setMessage fills the editor with message, leaving the cursor at the end
+// of the subject line
+func setMessage(editor *textarea.Model, message string) {
+	editor.SetValue(message)
+	for editor.Line() > 0 {
+		editor.CursorUp()
+	}
+	editor.CursorEnd()
+}
+
+// messageEditorLines returns the number of lines renderMessageEditor uses
+func messageEditorLines(editor textarea.Model) int {
+	return messageEditorHeight + 1 + len(git.LintCommitMessage(editor.Value()))
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// progressBarWidth is the width of the bar drawn for a phase with a percentage
+const progressBarWidth = 30
+
+// progressBuffer is how many updates may wait for the UI before later
+// ones are dropped
+const progressBuffer = 64
+
+// transferPhases are the phases that move objects between repositories,
+// which the final summary reports
+var transferPhases = map[string]bool{
+	"Writing objects":   true,
+	"Receiving objects": true,
+	"Unpacking objects": true,
+}
+
+// transferProgress follows the progress meter of a push, fetch or pull
+type transferProgress struct {
+	current  git.Progress // latest update
+	transfer git.Progress // latest update of a transfer phase
+	seen     bool
+}
+
+func (t transferProgress) update(p git.Progress) transferProgress {
+	t.current = p
+	t.seen = true
+	if transferPhases[p.Phase] && !p.Remote {
+		t.transfer = p
+	}
+	return t
+}
+
+// view renders the current phase with a bar when git reports a percentage
+func (t transferProgress) view() string {
+	p := t.current
+	phase := p.Phase
+	if p.Remote {
+		phase = "remote: " + phase
+	}
+	if p.Percent < 0 {
+		return fmt.Sprintf("%s: %d", phase, p.Current)
+	}
+	filled := progressBarWidth * min(p.Percent, 100) / 100
+	bar := StyleStaged.Render(strings.Repeat("█", filled)) + StyleMuted.Render(strings.Repeat("░", progressBarWidth-filled))
+	line := fmt.Sprintf("%s %s %3d%% (%d/%d)", phase, bar, p.Percent, p.Current, p.Total)
+	if p.Throughput != "" {
+		line += StyleMuted.Render(" " + p.Throughput)
+	}
+	return line
+}
+
+// summary describes the objects transferred, or "" if none were
+func (t transferProgress) summary() string {
+	p := t.transfer
+	if p.Phase == "" {
+		return ""
+	}
+	summary := fmt.Sprintf("%s %d/%d", strings.ToLower(p.Phase[:1])+p.Phase[1:], p.Current, p.Total)
+	if p.Throughput != "" {
+		summary += ", " + p.Throughput
+	}
+	return summary
+}
+
+// progressMsg carries a progress update of the running operation
+type progressMsg struct {
+	progress git.Progress
+	updates  <-chan git.Progress
+}
+
+// transferDoneMsg wraps the result of an operation run with progress,
+// along with the last of its progress
+type transferDoneMsg struct {
+	result   tea.Msg
+	progress transferProgress
+}
+
+// withProgress runs an operation that talks to a remote, handing it the
+// progress callback to pass to git and streaming the progress to the UI
+// as progressMsg while it runs
+func withProgress(run func(onProgress func(git.Progress)) tea.Msg) tea.Cmd {
+	updates := make(chan git.Progress, progressBuffer)
+	var progress transferProgress
+	onProgress := func(p git.Progress) {
+		progress = progress.update(p)
+		select {
+		case updates <- p:
+		default: // the UI is behind; it will catch up with a later update
+		}
+	}
+	return tea.Batch(func() tea.Msg {
+		defer close(updates)
+		result := run(onProgress)
+		return transferDoneMsg{result: result, progress: progress}
+	}, waitForProgress(updates))
+}
+
+// waitForProgress waits for the next progress update
+func waitForProgress(updates <-chan git.Progress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return progressMsg{progress: p, updates: updates}
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTransferProgress(t *testing.T) {
+	var p transferProgress
+	if p.summary() != "" {
+		t.Error("no progress should have no summary")
+	}
+
+	p = p.update(git.Progress{Phase: "Enumerating objects", Remote: true, Percent: -1, Current: 12})
+	if got := p.view(); got != "remote: Enumerating objects: 12" {
+		t.Errorf("view() = %q", got)
+	}
+
+	p = p.update(git.Progress{Phase: "Receiving objects", Percent: 50, Current: 5, Total: 10, Throughput: "1.00 MiB | 2.00 MiB/s"})
+	view := p.view()
+	for _, want := range []string{"Receiving objects", strings.Repeat("█", progressBarWidth/2), " 50% (5/10)", "1.00 MiB | 2.00 MiB/s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view() should contain %q, got %q", want, view)
+		}
+	}
+
+	p = p.update(git.Progress{Phase: "Resolving deltas", Percent: 100, Current: 2, Total: 2, Done: true})
+	if got := p.summary(); got != "receiving objects 5/10, 1.00 MiB | 2.00 MiB/s" {
+		t.Errorf("summary() = %q, want the last transfer phase", got)
+	}
+}
+
+func TestStatusModelPushProgress(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("push --progress", git.Result{
+		Stderr: "Counting objects: 100% (5/5), done.\n" +
+			"Writing objects:  50% (1/2)\r" +
+			"Writing objects: 100% (2/2), 1.00 KiB | 1.00 MiB/s, done.\n" +
+			"To /remote\n   1111111..2222222  main -> main\n",
+	})
+
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{}
+	m.branchStatus = git.BranchStatus{Name: "main", Remote: "origin/main", Ahead: 1}
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Push)})
+	m = newM.(StatusModel)
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newM.(StatusModel)
+
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("push should run alongside its progress updates, got %#v", batch)
+	}
+	done := batch[0]()
+
+	// Updates arrive one at a time while the push runs
+	wait := batch[1]
+	for _, want := range []string{"Counting objects", "Writing objects", "Writing objects"} {
+		msg, ok := wait().(progressMsg)
+		if !ok {
+			t.Fatalf("expected a progress update for %s", want)
+		}
+		newM, wait = m.Update(msg)
+		m = newM.(StatusModel)
+		if view := m.View(); !strings.Contains(view, "Pushing...") || !strings.Contains(view, want) {
+			t.Errorf("view should show %q, got:\n%s", want, view)
+		}
+	}
+	if !strings.Contains(m.View(), "100% (2/2)") {
+		t.Errorf("view should show the finished phase, got:\n%s", m.View())
+	}
+	if msg := wait(); msg != nil {
+		t.Errorf("updates should end with the push, got %#v", msg)
+	}
+
+	newM, _ = m.Update(done)
+	m = newM.(StatusModel)
+	if m.runningOp != "" || m.progress.seen {
+		t.Errorf("push should be finished, got runningOp=%q", m.runningOp)
+	}
+	want := "Pushed: writing objects 2/2, 1.00 KiB | 1.00 MiB/s"
+	if !strings.Contains(m.View(), want) {
+		t.Errorf("view should contain %q, got:\n%s", want, m.View())
+	}
+}
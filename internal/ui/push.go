@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"go-on-git/internal/git"
+)
+
+// Rows of the push menu
+const (
+	pushRowRemote = iota
+	pushRowBranch
+	pushRowLease
+	pushRowIncludes
+	pushRowNoVerify
+	pushRowTags
+	pushRowOptions
+	pushRows
+)
+
+// pushMenu picks where and how to push: the remote and branch, forcing
+// with a lease, skipping the pre-push hook, push options and tags
+type pushMenu struct {
+	remotes         []string
+	remote          int
+	branch          string
+	options         string // space-separated -o push options
+	forceWithLease  bool
+	forceIfIncludes bool
+	noVerify        bool
+	tags            bool
+	setUpstream     bool // the branch has no upstream yet
+	cursor          int
+	open            bool
+}
+
+// newPushMenu opens the menu on the branch's upstream, or on the first
+// remote and a branch of the same name if it has none
+func newPushMenu(remotes []string, upstream, branch string) pushMenu {
+	menu := pushMenu{remotes: remotes, branch: branch, setUpstream: upstream == "", open: true}
+	for i, remote := range remotes {
+		if rest, found := strings.CutPrefix(upstream, remote+"/"); found {
+			menu.remote = i
+			menu.branch = rest
+		}
+	}
+	return menu
+}
+
+// active reports whether the menu is showing
+func (p pushMenu) active() bool {
+	return p.open
+}
+
+// editing reports whether the cursor is on a row that takes text
+func (p pushMenu) editing() bool {
+	return p.cursor == pushRowBranch || p.cursor == pushRowOptions
+}
+
+// update handles a key, returning the push options once they are chosen
+func (p pushMenu) update(key string) (pushMenu, *git.PushOptions) {
+	switch {
+	case key == "down" || key == "tab" || (key == Keys.Down && !p.editing()):
+		p.cursor = min(p.cursor+1, pushRows-1)
+	case key == "up" || key == "shift+tab" || (key == Keys.Up && !p.editing()):
+		p.cursor = max(p.cursor-1, 0)
+	case key == "enter":
+		if strings.TrimSpace(p.branch) == "" {
+			return p, nil
+		}
+		p.open = false
+		opts := p.pushOptions()
+		return p, &opts
+	case key == "esc":
+		p.open = false
+	case p.editing():
+		p = p.edit(key)
+	case key == " ":
+		p = p.toggle()
+	}
+	return p, nil
+}
+
+// toggle flips the option under the cursor, or cycles the remote
+func (p pushMenu) toggle() pushMenu {
+	switch p.cursor {
+	case pushRowRemote:
+		p.remote = (p.remote + 1) % len(p.remotes)
+	case pushRowLease:
+		p.forceWithLease = !p.forceWithLease
+		// --force-if-includes does nothing without a lease
+		p.forceIfIncludes = p.forceIfIncludes && p.forceWithLease
+	case pushRowIncludes:
+		p.forceIfIncludes = !p.forceIfIncludes
+		p.forceWithLease = p.forceWithLease || p.forceIfIncludes
+	case pushRowNoVerify:
+		p.noVerify = !p.noVerify
+	case pushRowTags:
+		p.tags = !p.tags
+	}
+	return p
+}
+
+// edit types a key into the branch or push options
+func (p pushMenu) edit(key string) pushMenu {
+	field := &p.branch
+	if p.cursor == pushRowOptions {
+		field = &p.options
+	}
+	switch {
+	case key == "backspace":
+		if _, size := utf8.DecodeLastRuneInString(*field); size > 0 {
+			*field = (*field)[:len(*field)-size]
+		}
+	case key == " " && p.cursor == pushRowBranch:
+		// Branch names can't contain spaces
+	case utf8.RuneCountInString(key) == 1:
+		*field += key
+	}
+	return p
+}
+
+// pushOptions returns the options chosen in the menu
+func (p pushMenu) pushOptions() git.PushOptions {
+	return git.PushOptions{
+		Remote:          p.remotes[p.remote],
+		Branch:          strings.TrimSpace(p.branch),
+		SetUpstream:     p.setUpstream,
+		ForceWithLease:  p.forceWithLease,
+		ForceIfIncludes: p.forceIfIncludes,
+		NoVerify:        p.noVerify,
+		Options:         strings.Fields(p.options),
+		Tags:            p.tags,
+	}
+}
+
+// view renders the destination, then the toggles and push options
+func (p pushMenu) view() string {
+	var sb strings.Builder
+	sb.WriteString("Push:")
+	sb.WriteString(StyleMuted.Render("  (enter to push, esc to cancel)"))
+	sb.WriteString("\n")
+	check := func(on bool) string {
+		if on {
+			return "[x] "
+		}
+		return "[ ] "
+	}
+	rows := [pushRows]string{
+		pushRowRemote:   "remote:  " + p.remotes[p.remote] + StyleMuted.Render("  (space to change)"),
+		pushRowBranch:   "branch:  " + p.branch,
+		pushRowLease:    check(p.forceWithLease) + "force with lease",
+		pushRowIncludes: check(p.forceIfIncludes) + "force if includes",
+		pushRowNoVerify: check(p.noVerify) + "skip the pre-push hook (--no-verify)",
+		pushRowTags:     check(p.tags) + "push tags",
+		pushRowOptions:  "options: " + p.options,
+	}
+	for i, row := range rows {
+		if i == p.cursor {
+			sb.WriteString(StyleSelected.Render("> ") + row)
+			if p.editing() {
+				sb.WriteString(StyleSelected.Render("_"))
+			}
+		} else {
+			sb.WriteString("  " + row)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// lines returns the number of lines view uses
+func (p pushMenu) lines() int {
+	return pushRows + 1
+}
@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const remoteMain = "0123456789abcdef0123456789abcdef01234567"
+
+// pressKeys sends each key to the model in turn; strings are typed a
+// character at a time
+func pressKeys(m StatusModel, keys ...any) StatusModel {
+	var msgs []tea.KeyMsg
+	for _, key := range keys {
+		if msg, ok := key.(tea.KeyMsg); ok {
+			msgs = append(msgs, msg)
+			continue
+		}
+		for _, r := range key.(string) {
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	for _, msg := range msgs {
+		newM, _ := m.Update(msg)
+		m = newM.(StatusModel)
+	}
+	return m
+}
+
+var (
+	keyDown  = tea.KeyMsg{Type: tea.KeyDown}
+	keySpace = tea.KeyMsg{Type: tea.KeySpace}
+	keyBack  = tea.KeyMsg{Type: tea.KeyBackspace}
+	keyEnter = tea.KeyMsg{Type: tea.KeyEnter}
+	keyMenu  = tea.KeyMsg{Type: tea.KeyCtrlP}
+)
+
+func pushMenuModel(t *testing.T) (StatusModel, *fakeExecutor) {
+	t.Helper()
+	repo, fake := newFakeRepo(t)
+	fake.on("remote", git.Result{Stdout: "origin\nfork\n"})
+	fake.on("rev-parse --verify --quiet refs/remotes/origin/main", git.Result{Stdout: remoteMain + "\n"})
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{}
+	m.branchStatus = git.BranchStatus{Name: "main", Remote: "origin/main", Ahead: 1, Behind: 1}
+	return m, fake
+}
+
+func TestNewPushMenu(t *testing.T) {
+	menu := newPushMenu([]string{"origin", "fork"}, "fork/trunk", "main")
+	if opts := menu.pushOptions(); opts.Remote != "fork" || opts.Branch != "trunk" || opts.SetUpstream {
+		t.Errorf("menu should start on the upstream, got %+v", opts)
+	}
+
+	menu = newPushMenu([]string{"origin"}, "", "topic")
+	menu, _ = menu.update("down")
+	for _, key := range []string{"-", "j", " ", "k"} {
+		menu, _ = menu.update(key)
+	}
+	if opts := menu.pushOptions(); opts.Remote != "origin" || opts.Branch != "topic-jk" || !opts.SetUpstream {
+		t.Errorf("j/k should be typed into the branch, got %+v", opts)
+	}
+	if menu, opts := menu.update("esc"); menu.active() || opts != nil {
+		t.Error("esc should close the menu without pushing")
+	}
+}
+
+func TestStatusModelPushMenu(t *testing.T) {
+	m, fake := pushMenuModel(t)
+	args := "push --progress --no-verify -o ci.skip -o reviewer=jane --tags fork HEAD:refs/heads/topic"
+	fake.on(args, git.Result{})
+
+	m = pressKeys(m, keyMenu)
+	if !m.pushMenu.active() {
+		t.Fatal("ctrl+p should open the push menu")
+	}
+	view := m.View()
+	for _, want := range []string{"Push:", "> remote:  origin", "branch:  main", "[ ] force with lease", "[ ] push tags"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q, got:\n%s", want, view)
+		}
+	}
+
+	m = pressKeys(m,
+		keySpace, keyDown, // fork
+		keyBack, keyBack, keyBack, keyBack, "topic", keyDown,
+		keyDown, keyDown, keySpace, // --no-verify
+		keyDown, keySpace, // --tags
+		keyDown, "ci.skip", keySpace, "reviewer=jane",
+	)
+	newM, cmd := m.Update(keyEnter)
+	m = newM.(StatusModel)
+	if m.pushMenu.active() || m.runningOp != "push" || cmd == nil {
+		t.Fatalf("enter should start the push, got runningOp=%q", m.runningOp)
+	}
+	msg, _ := runOperation(cmd).(transferDoneMsg)
+	if done, ok := msg.result.(operationDoneMsg); !ok || done.err != nil {
+		t.Fatalf("expected a successful push, got %#v", msg)
+	}
+	if _, ok := fake.called(args); !ok {
+		t.Errorf("expected git %s to run", args)
+	}
+}
+
+func TestStatusModelPushSetsUpstream(t *testing.T) {
+	m, fake := pushMenuModel(t)
+	m.branchStatus = git.BranchStatus{Name: "topic"}
+	args := "push --progress -u origin HEAD:refs/heads/topic"
+	fake.on(args, git.Result{})
+
+	m = pressKeys(m, Keys.Push)
+	if m.confirmMode != confirmPushNew {
+		t.Fatalf("confirmMode = %v, want confirmPushNew", m.confirmMode)
+	}
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newM.(StatusModel)
+	msg, _ := runOperation(cmd).(transferDoneMsg)
+	if done, ok := msg.result.(operationDoneMsg); !ok || done.err != nil {
+		t.Fatalf("expected a successful push, got %#v", msg)
+	}
+	if _, ok := fake.called(args); !ok {
+		t.Errorf("expected git %s to run", args)
+	}
+}
+
+func TestStatusModelForcePushWithLease(t *testing.T) {
+	m, fake := pushMenuModel(t)
+	args := "push --progress --force-with-lease=main:" + remoteMain + " origin HEAD:refs/heads/main"
+	fake.on(args, git.Result{})
+
+	m = pressKeys(m, keyMenu, keyDown, keyDown, keySpace, keyEnter)
+	if m.confirmMode != confirmForcePush || m.runningOp != "" {
+		t.Fatalf("a force push should ask first, got confirmMode=%v runningOp=%q", m.confirmMode, m.runningOp)
+	}
+	if want := "Force-push to 'origin/main', expecting it at 0123456?"; !strings.Contains(m.View(), want) {
+		t.Errorf("view should show the lease %q, got:\n%s", want, m.View())
+	}
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newM.(StatusModel)
+	if m.runningOp != "push" {
+		t.Fatalf("y should start the push, got runningOp=%q", m.runningOp)
+	}
+	runOperation(cmd)
+	if _, ok := fake.called(args); !ok {
+		t.Errorf("expected git %s to run", args)
+	}
+}
+
+func TestStatusModelForcePushIfIncludes(t *testing.T) {
+	m, fake := pushMenuModel(t)
+	args := "push --progress --force-with-lease=main --force-if-includes origin HEAD:refs/heads/main"
+	fake.on(args, git.Result{})
+
+	// Turning on --force-if-includes turns on the lease it needs
+	m = pressKeys(m, keyMenu, keyDown, keyDown, keyDown, keySpace)
+	if !m.pushMenu.forceWithLease {
+		t.Fatal("force if includes should turn on force with lease")
+	}
+	m = pressKeys(m, keyEnter)
+	if !strings.Contains(m.View(), "expecting it at 0123456 and included in HEAD?") {
+		t.Errorf("view should show the lease, got:\n%s", m.View())
+	}
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newM.(StatusModel)
+	runOperation(cmd)
+	if _, ok := fake.called(args); !ok {
+		t.Errorf("expected git %s to run", args)
+	}
+}
+
+func TestStatusModelForcePushUnfetched(t *testing.T) {
+	m, fake := pushMenuModel(t)
+	fake.on("rev-parse --verify --quiet refs/remotes/origin/main", git.Result{ExitCode: 1})
+
+	m = pressKeys(m, keyMenu, keyDown, keyDown, keySpace, keyEnter)
+	if m.confirmMode != confirmNone || m.runningOp != "" {
+		t.Fatalf("nothing should be pushed, got confirmMode=%v runningOp=%q", m.confirmMode, m.runningOp)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "origin/main has not been fetched") {
+		t.Errorf("expected an error asking to fetch first, got %v", m.err)
+	}
+}
+
+func TestStatusModelPushRejectedOffersForce(t *testing.T) {
+	m, fake := pushMenuModel(t)
+	fake.on("branch --show-current", git.Result{Stdout: "main\n"})
+	fake.on("config --get branch.main.remote", git.Result{Stdout: "origin\n"})
+	fake.on("config --get branch.main.merge", git.Result{Stdout: "refs/heads/main\n"})
+	m.startOperation("push")
+
+	rejected := &git.Error{Args: []string{"push"}, Kind: git.ErrPushRejected, Err: &git.ExitError{Code: 1}}
+	newM, _ := m.Update(operationDoneMsg{"push", rejected})
+	m = newM.(StatusModel)
+	if !strings.Contains(m.View(), "(f)orce with lease") {
+		t.Errorf("view should offer to force-push, got:\n%s", m.View())
+	}
+
+	m = pressKeys(m, "f")
+	if m.confirmMode != confirmForcePush {
+		t.Fatalf("confirmMode = %v, want confirmForcePush", m.confirmMode)
+	}
+	if want := (git.PushOptions{Remote: "origin", Branch: "main", ForceWithLease: true, Lease: remoteMain}); !reflect.DeepEqual(m.pendingPush, want) {
+		t.Errorf("pendingPush = %+v, want %+v", m.pendingPush, want)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEscape})
+	if m.confirmMode != confirmNone || m.runningOp != "" {
+		t.Errorf("esc should cancel the force push, got confirmMode=%v runningOp=%q", m.confirmMode, m.runningOp)
+	}
+}
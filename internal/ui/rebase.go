@@ -0,0 +1,436 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-on-git/internal/git"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RebaseModel is the bubbletea model for planning an interactive rebase:
+// the commits after base as an editable todo list, oldest first, the
+// order git applies them in
+type RebaseModel struct {
+	repo            *git.Repo
+	base            git.Commit
+	steps           []git.RebaseStep
+	loaded          bool
+	cursor          int
+	scrollOffset    int
+	rewording       bool // editing the message of the step under the cursor
+	messageInput    textarea.Model
+	confirmMode     bool
+	running         bool
+	showHelp        bool
+	showVerboseHelp bool
+	err             error
+	width           int
+	height          int
+}
+
+// NewRebaseModel creates a rebase planner for the commits after base
+func NewRebaseModel(repo *git.Repo, base git.Commit, width, height int, showVerboseHelp bool) RebaseModel {
+	return RebaseModel{
+		repo:            repo,
+		base:            base,
+		messageInput:    newMessageEditor(),
+		showVerboseHelp: showVerboseHelp,
+		width:           width,
+		height:          height,
+	}
+}
+
+type rebaseTodoMsg struct {
+	steps []git.RebaseStep
+}
+
+// Init initializes the model
+func (m RebaseModel) Init() tea.Cmd {
+	return m.loadTodo
+}
+
+func (m RebaseModel) loadTodo() tea.Msg {
+	steps, err := m.repo.GetRebaseTodo(context.Background(), m.base.Hash)
+	if err != nil {
+		return errMsg{err}
+	}
+	return rebaseTodoMsg{steps}
+}
+
+// AtTop returns true if the todo list is showing, so going back leaves
+// the planner rather than the message editor, a prompt or the help
+func (m RebaseModel) AtTop() bool {
+	return !m.showHelp && !m.rewording && !m.confirmMode && !m.running
+}
+
+// Update handles messages
+func (m RebaseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		// Handle help mode
+		if m.showHelp {
+			if key == Keys.Help || key == "esc" || key == Keys.Quit {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+
+		if m.running {
+			return m, nil
+		}
+
+		if m.rewording {
+			switch key {
+			case "ctrl+s":
+				message := m.messageInput.Value()
+				if strings.TrimSpace(message) == "" {
+					m.err = fmt.Errorf("commit message is empty")
+					return m, nil
+				}
+				m.steps[m.cursor].Action = git.RebaseReword
+				m.steps[m.cursor].Message = message
+				m.rewording = false
+				m.messageInput.Blur()
+				m.err = nil
+				return m, nil
+			case "esc":
+				m.rewording = false
+				m.messageInput.Blur()
+				m.err = nil
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.messageInput, cmd = m.messageInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		if m.confirmMode {
+			switch key {
+			case "y", "Y":
+				m.confirmMode = false
+				m.running = true
+				return m, m.runRebase()
+			case "n", "N", "esc":
+				m.confirmMode = false
+				return m, nil
+			}
+			return m, nil
+		}
+
+		last := max(len(m.steps)-1, 0)
+
+		switch key {
+		case Keys.Help:
+			m.showHelp = true
+			return m, nil
+		case Keys.VerboseHelp:
+			m.showVerboseHelp = !m.showVerboseHelp
+			return m, nil
+		case Keys.Down, "down":
+			m.cursor = min(m.cursor+1, last)
+		case Keys.Up, "up":
+			m.cursor = max(m.cursor-1, 0)
+		case Keys.Bottom:
+			m.cursor = last
+		case Keys.Top:
+			m.cursor = 0
+		case Keys.Pick:
+			m.setAction(git.RebasePick)
+		case Keys.Edit:
+			m.setAction(git.RebaseEdit)
+		case Keys.Squash:
+			m.setAction(git.RebaseSquash)
+		case Keys.Fixup:
+			m.setAction(git.RebaseFixup)
+		case Keys.Drop:
+			m.setAction(git.RebaseDrop)
+		case Keys.Reword:
+			return m, m.startReword()
+		case Keys.MoveUp:
+			if m.cursor > 0 && m.cursor < len(m.steps) {
+				m.steps[m.cursor-1], m.steps[m.cursor] = m.steps[m.cursor], m.steps[m.cursor-1]
+				m.cursor--
+			}
+		case Keys.MoveDown:
+			if m.cursor < len(m.steps)-1 {
+				m.steps[m.cursor+1], m.steps[m.cursor] = m.steps[m.cursor], m.steps[m.cursor+1]
+				m.cursor++
+			}
+		case "enter":
+			if err := git.ValidateRebaseTodo(m.steps); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.err = nil
+			m.confirmMode = true
+			return m, nil
+		default:
+			return m, nil
+		}
+		m.ensureCursorVisible()
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case rebaseTodoMsg:
+		m.steps = msg.steps
+		m.loaded = true
+		m.cursor = 0
+		m.scrollOffset = 0
+		return m, nil
+
+	case rebaseDoneMsg:
+		// Success hands over to the status view; only failures land here
+		m.running = false
+		m.err = msg.err
+		return m, nil
+
+	case errMsg:
+		m.err = msg.err
+		m.running = false
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// setAction sets the action of the step under the cursor
+func (m *RebaseModel) setAction(action git.RebaseAction) {
+	if m.cursor < len(m.steps) {
+		m.steps[m.cursor].Action = action
+	}
+}
+
+// startReword opens the message editor on the step under the cursor,
+// starting from its new message if it already has one
+func (m *RebaseModel) startReword() tea.Cmd {
+	if m.cursor >= len(m.steps) {
+		return nil
+	}
+	step := m.steps[m.cursor]
+	message := step.Message
+	if message == "" {
+		message = step.Commit.Subject
+		if step.Commit.Body != "" {
+			message += "\n\n" + step.Commit.Body
+		}
+	}
+	m.rewording = true
+	m.err = nil
+	m.messageInput.SetWidth(messageEditorWidth(m.width))
+	m.messageInput.SetValue(message)
+	return m.messageInput.Focus()
+}
+
+func (m RebaseModel) runRebase() tea.Cmd {
+	steps := slices.Clone(m.steps)
+	return func() tea.Msg {
+		return rebaseDoneMsg{m.repo.RunRebase(context.Background(), m.base.Hash, steps)}
+	}
+}
+
+// visibleLines returns the number of steps that fit on screen
+func (m RebaseModel) visibleLines() int {
+	// Header (2 lines), plus the message editor or the help bar
+	reservedLines := 4
+	if m.rewording {
+		reservedLines += messageEditorLines(m.messageInput) + 2
+	} else if m.showVerboseHelp || m.confirmMode {
+		reservedLines += 2
+	}
+	visibleLines := m.height - reservedLines
+	if visibleLines < 1 {
+		visibleLines = 20
+	}
+	return visibleLines
+}
+
+// ensureCursorVisible adjusts scrollOffset to keep the cursor in view
+func (m *RebaseModel) ensureCursorVisible() {
+	visible := m.visibleLines()
+	if m.cursor < m.scrollOffset {
+		m.scrollOffset = m.cursor
+	}
+	if m.cursor >= m.scrollOffset+visible {
+		m.scrollOffset = m.cursor - visible + 1
+	}
+}
+
+// View renders the rebase planner
+func (m RebaseModel) View() string {
+	if m.showHelp {
+		return m.renderHelp()
+	}
+
+	var content strings.Builder
+
+	content.WriteString(m.renderHeader())
+	content.WriteString("\n\n")
+
+	if m.err != nil {
+		content.WriteString(StyleUnstaged.Render(fmt.Sprintf("Error: %v", m.err)))
+		content.WriteString("\n")
+	}
+
+	if !m.loaded || len(m.steps) == 0 {
+		if m.loaded {
+			content.WriteString(StyleEmpty.Render(fmt.Sprintf("No commits after %s to rebase", m.base.ShortHash())))
+		} else {
+			content.WriteString(StyleMuted.Render("Loading..."))
+		}
+		content.WriteString("\n")
+		return m.anchorBottom(content.String())
+	}
+
+	endIdx := min(m.scrollOffset+m.visibleLines(), len(m.steps))
+	for i := m.scrollOffset; i < endIdx; i++ {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		content.WriteString(cursor)
+		content.WriteString(renderRebaseStep(m.steps[i]))
+		content.WriteString("\n")
+	}
+
+	content.WriteString(m.renderFooter())
+
+	return m.anchorBottom(content.String())
+}
+
+// renderRebaseStep renders a todo line: the action, the commit and the
+// subject it will have
+func renderRebaseStep(step git.RebaseStep) string {
+	var actionStyle lipgloss.Style
+	switch step.Action {
+	case git.RebaseReword, git.RebaseEdit:
+		actionStyle = StyleUntracked
+	case git.RebaseSquash, git.RebaseFixup:
+		actionStyle = StyleConflicted
+	case git.RebaseDrop:
+		actionStyle = StyleUnstaged
+	default:
+		actionStyle = StyleStaged
+	}
+
+	subject := step.Commit.Subject
+	if step.Action == git.RebaseReword {
+		subject, _, _ = strings.Cut(strings.TrimSpace(step.Message), "\n")
+	}
+	line := StyleCommitHash.Render(step.Commit.ShortHash()) + " " + subject
+	if step.Action == git.RebaseDrop {
+		line = StyleMuted.Render(step.Commit.ShortHash() + " " + subject)
+	}
+	return actionStyle.Render(fmt.Sprintf("%-6s", step.Action)) + " " + line
+}
+
+func (m RebaseModel) renderHeader() string {
+	header := StyleMuted.Render("> git rebase -i "+m.base.ShortHash()) + "  " +
+		StyleMuted.Render("(top is applied first, esc to go back)")
+	return header + "\n" + StyleMuted.Render("───────────────────────────────────────────────────────────────")
+}
+
+func (m RebaseModel) anchorBottom(content string) string {
+	lines := strings.Count(content, "\n")
+	if m.height <= lines {
+		return content
+	}
+	padding := m.height - lines - 1
+	return strings.Repeat("\n", padding) + content
+}
+
+// renderFooter renders the message editor, a prompt or, if enabled, the help bar
+func (m RebaseModel) renderFooter() string {
+	switch {
+	case m.rewording:
+		step := m.steps[m.cursor]
+		return "\n" + fmt.Sprintf("Reword %s:", StyleCommitHash.Render(step.Commit.ShortHash())) +
+			StyleMuted.Render("  (ctrl+s to save, esc to cancel)") + "\n" + renderMessageEditor(m.messageInput, m.width)
+	case m.running:
+		return "\n" + StyleMuted.Render("Rebasing...")
+	case m.confirmMode:
+		return "\n" + StyleConfirm.Render(fmt.Sprintf("Rebase %d commits onto %s? (y/n) ", len(m.steps), m.base.ShortHash()))
+	case m.showVerboseHelp:
+		return "\n" + m.renderHelpBar()
+	}
+	return ""
+}
+
+func (m RebaseModel) renderHelpBar() string {
+	var sb strings.Builder
+
+	sb.WriteString(StyleMuted.Render("───────────────────────────────────────────────────────────────"))
+	sb.WriteString("\n")
+
+	items := []struct{ key, desc string }{
+		{formatKeyList(Keys.Down, Keys.Up), "navigate"},
+		{formatKeyList(Keys.MoveUp, Keys.MoveDown), "move"},
+		{formatKeyList(Keys.Pick, Keys.Reword, Keys.Edit, Keys.Squash, Keys.Fixup, Keys.Drop), "pick/reword/edit/squash/fixup/drop"},
+		{"Enter", "start"},
+		{Keys.Help, "help"},
+		{formatKeyList(Keys.Left, "ESC"), "back"},
+	}
+
+	for _, item := range items {
+		sb.WriteString(StyleHelpKey.Render(item.key))
+		sb.WriteString(" ")
+		sb.WriteString(StyleHelpDesc.Render(item.desc))
+		sb.WriteString("  ")
+	}
+
+	return sb.String()
+}
+
+func (m RebaseModel) renderHelp() string {
+	var sb strings.Builder
+
+	sb.WriteString(StyleHelpTitle.Render("Rebase Shortcuts"))
+	sb.WriteString("\n\n")
+
+	moveKeys := formatKeyList(Keys.Down, Keys.Up, "↓", "↑")
+	backKeys := formatKeyList(Keys.Left, "←", "ESC")
+
+	help := []struct {
+		key  string
+		desc string
+	}{
+		{moveKeys, "Navigate commits"},
+		{Keys.MoveUp, "Move commit up (applied earlier)"},
+		{Keys.MoveDown, "Move commit down (applied later)"},
+		{Keys.Pick, "Pick: keep the commit as is"},
+		{Keys.Reword, "Reword: edit the commit message"},
+		{Keys.Edit, "Edit: stop after the commit to amend it"},
+		{Keys.Squash, "Squash into the previous commit, combining messages"},
+		{Keys.Fixup, "Fixup into the previous commit, keeping its message"},
+		{Keys.Drop, "Drop the commit"},
+		{"Enter", "Start the rebase"},
+		{Keys.Help, "Toggle help"},
+		{backKeys, "Go back"},
+	}
+
+	for _, h := range help {
+		sb.WriteString(fmt.Sprintf("  %s  %s\n",
+			StyleHelpKey.Render(fmt.Sprintf("%-8s", h.key)),
+			StyleHelpDesc.Render(h.desc)))
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(StyleMuted.Render("  Conflicts and edit stops return to the status view, where the rebase"))
+	sb.WriteString("\n")
+	sb.WriteString(StyleMuted.Render(fmt.Sprintf("  can be continued (%s) or aborted (%s).", Keys.Continue, Keys.Abort)))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
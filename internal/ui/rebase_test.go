@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loadedRebaseModel returns a planner over three commits, oldest first
+func loadedRebaseModel(t *testing.T) RebaseModel {
+	t.Helper()
+	base := testCommits(4)[3]
+	m := NewRebaseModel(nil, base, 100, 40, false)
+	steps := make([]git.RebaseStep, 3)
+	for i, c := range testCommits(3) {
+		steps[2-i] = git.RebaseStep{Action: git.RebasePick, Commit: c}
+	}
+	newModel, _ := m.Update(rebaseTodoMsg{steps})
+	return newModel.(RebaseModel)
+}
+
+func pressKey(t *testing.T, m RebaseModel, keys ...string) RebaseModel {
+	t.Helper()
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "ctrl+s":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlS}
+		}
+		newModel, _ := m.Update(msg)
+		m = newModel.(RebaseModel)
+	}
+	return m
+}
+
+// plan returns the todo as "action subject" lines
+func plan(m RebaseModel) string {
+	lines := make([]string, len(m.steps))
+	for i, step := range m.steps {
+		lines[i] = step.Action.String() + " " + step.Commit.Subject
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestRebaseModelLoadsTodo(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	base := testCommits(4)[3]
+	fake.on("log --reverse --topo-order --no-merges "+logFormatArg+" "+base.Hash+"..HEAD",
+		git.Result{Stdout: logPageOutput(0, 2)})
+
+	m := NewRebaseModel(repo, base, 100, 40, false)
+	newModel, _ := m.Update(m.Init()())
+	m = newModel.(RebaseModel)
+
+	if plan(m) != "pick Commit 0\npick Commit 1" {
+		t.Errorf("plan = %q", plan(m))
+	}
+	view := m.View()
+	if !strings.Contains(view, "> git rebase -i "+base.ShortHash()) {
+		t.Error("header should show the base commit")
+	}
+	if !strings.Contains(view, "pick   0000000 Commit 0") {
+		t.Errorf("view should list the steps, got:\n%s", view)
+	}
+}
+
+func TestRebaseModelActionsAndMoves(t *testing.T) {
+	m := loadedRebaseModel(t)
+
+	m = pressKey(t, m, Keys.Drop, Keys.Down, Keys.Squash, Keys.Down, Keys.Edit)
+	if plan(m) != "drop Commit 2\nsquash Commit 1\nedit Commit 0" {
+		t.Errorf("plan = %q", plan(m))
+	}
+
+	// Move the last commit to the top
+	m = pressKey(t, m, Keys.MoveUp, Keys.MoveUp, Keys.MoveUp)
+	if plan(m) != "edit Commit 0\ndrop Commit 2\nsquash Commit 1" || m.cursor != 0 {
+		t.Errorf("plan = %q, cursor %d", plan(m), m.cursor)
+	}
+	m = pressKey(t, m, Keys.MoveDown, Keys.Fixup, Keys.Pick)
+	if plan(m) != "drop Commit 2\npick Commit 0\nsquash Commit 1" || m.cursor != 1 {
+		t.Errorf("plan = %q, cursor %d", plan(m), m.cursor)
+	}
+}
+
+func TestRebaseModelReword(t *testing.T) {
+	m := loadedRebaseModel(t)
+	m.steps[0].Commit.Body = "Old body"
+
+	m = pressKey(t, m, Keys.Reword)
+	if !m.rewording {
+		t.Fatal("reword should open the message editor")
+	}
+	if got := m.messageInput.Value(); got != "Commit 2\n\nOld body" {
+		t.Errorf("editor should start from the current message, got %q", got)
+	}
+	if m.AtTop() {
+		t.Error("going back should close the editor first")
+	}
+
+	// Keys meant for the list are typed into the message
+	m.messageInput.SetValue("")
+	m = pressKey(t, m, "N", "e", "w", Keys.Drop, "ctrl+s")
+	if m.rewording {
+		t.Fatal("ctrl+s should save the message")
+	}
+	if m.steps[0].Action != git.RebaseReword || m.steps[0].Message != "New"+Keys.Drop {
+		t.Errorf("step = %s %q", m.steps[0].Action, m.steps[0].Message)
+	}
+	if !strings.Contains(m.View(), "reword 0000000 New"+Keys.Drop) {
+		t.Error("the list should show the new subject")
+	}
+
+	// An empty message is refused
+	m = pressKey(t, m, Keys.Reword)
+	m.messageInput.SetValue("  ")
+	m = pressKey(t, m, "ctrl+s")
+	if !m.rewording || m.err == nil {
+		t.Error("an empty message should keep the editor open with an error")
+	}
+	m = pressKey(t, m, "esc")
+	if m.rewording || m.steps[0].Message != "New"+Keys.Drop {
+		t.Error("esc should cancel without changing the message")
+	}
+}
+
+func TestRebaseModelConfirm(t *testing.T) {
+	m := loadedRebaseModel(t)
+
+	// A squash with nothing before it is caught before starting
+	m = pressKey(t, m, Keys.Squash, "enter")
+	if m.confirmMode || m.err == nil {
+		t.Fatal("an invalid plan should show an error instead of confirming")
+	}
+
+	m = pressKey(t, m, Keys.Pick, "enter")
+	if !m.confirmMode {
+		t.Fatal("enter should ask for confirmation")
+	}
+	if !strings.Contains(m.View(), "Rebase 3 commits onto 0000000? (y/n)") {
+		t.Errorf("view should show the prompt, got:\n%s", m.View())
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(RebaseModel)
+	if !m.running || cmd == nil {
+		t.Fatal("y should start the rebase")
+	}
+	if m.AtTop() {
+		t.Error("should not leave the planner while the rebase runs")
+	}
+
+	newModel, _ = m.Update(rebaseDoneMsg{errors.New("boom")})
+	m = newModel.(RebaseModel)
+	if m.running || m.err == nil {
+		t.Error("a failed rebase should stay in the planner with the error")
+	}
+}
+
+func TestAppModelRebaseFromLog(t *testing.T) {
+	repo, _ := newFakeRepo(t)
+	m := NewAppModel(repo)
+	m.mode = viewLog
+	m.log = NewLogModelWithSize(repo, 100, 40)
+	m.log.loaded = true
+	m.log.commits = testCommits(3)
+	m.log.cursor = 2
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Rebase)})
+	m = newModel.(AppModel)
+	if m.mode != viewRebase {
+		t.Fatalf("mode = %v, want viewRebase", m.mode)
+	}
+	if cmd == nil || m.rebase.base.Hash != testCommits(3)[2].Hash {
+		t.Error("should load the todo for the selected commit")
+	}
+
+	// q goes back to the log
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = newModel.(AppModel)
+	if m.mode != viewLog {
+		t.Errorf("mode = %v, want viewLog", m.mode)
+	}
+}
+
+func TestAppModelRebaseDoneShowsStatus(t *testing.T) {
+	m := NewAppModel(nil)
+	m.mode = viewRebase
+	m.rebase = loadedRebaseModel(t)
+	m.rebase.running = true
+
+	newModel, cmd := m.Update(rebaseDoneMsg{})
+	m = newModel.(AppModel)
+	if m.mode != viewStatus {
+		t.Errorf("mode = %v, want viewStatus", m.mode)
+	}
+	if cmd == nil {
+		t.Error("should refresh the status")
+	}
+}
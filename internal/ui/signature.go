@@ -0,0 +1,51 @@
+package ui
+
+import "go-on-git/internal/git"
+
+// signatureBadge renders a one-character verified/unverified/missing mark
+// for a commit's signature
+func signatureBadge(sig git.Signature) string {
+	switch {
+	case sig.Verified():
+		return StyleSignatureVerified.Render("✓")
+	case sig.Status == git.SignatureBad:
+		return StyleSignatureBad.Render("✗")
+	case sig.Signed():
+		return StyleSignatureUnverified.Render("?")
+	}
+	return StyleMuted.Render("·")
+}
+
+// signatureDescription says what git made of a signature
+func signatureDescription(sig git.Signature) string {
+	switch sig.Status {
+	case git.SignatureGood:
+		return "good signature"
+	case git.SignatureUnknown:
+		return "good signature, unknown validity"
+	case git.SignatureExpired:
+		return "expired signature"
+	case git.SignatureExpiredKey:
+		return "signed with an expired key"
+	case git.SignatureRevokedKey:
+		return "signed with a revoked key"
+	case git.SignatureUnchecked:
+		return "signature can't be checked"
+	case git.SignatureBad:
+		return "bad signature"
+	}
+	return "not signed"
+}
+
+// renderSignature renders the badge, verdict, signer and key of a
+// signature for the commit detail header
+func renderSignature(sig git.Signature) string {
+	line := signatureBadge(sig) + " " + signatureDescription(sig)
+	if sig.Signer != "" {
+		line += " from " + sig.Signer
+	}
+	if sig.Key != "" {
+		line += StyleMuted.Render(" (" + sig.Key + ")")
+	}
+	return line
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"go-on-git/internal/git"
+)
+
+func TestSignatureBadge(t *testing.T) {
+	tests := []struct {
+		status git.SignatureStatus
+		want   string
+	}{
+		{git.SignatureGood, "✓"},
+		{git.SignatureUnknown, "?"},
+		{git.SignatureUnchecked, "?"},
+		{git.SignatureRevokedKey, "?"},
+		{git.SignatureBad, "✗"},
+		{git.SignatureNone, "·"},
+		{0, "·"},
+	}
+	for _, tt := range tests {
+		if got := signatureBadge(git.Signature{Status: tt.status}); !strings.Contains(got, tt.want) {
+			t.Errorf("signatureBadge(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRenderSignature(t *testing.T) {
+	sig := git.Signature{Status: git.SignatureGood, Signer: "test@example.com", Key: "SHA256:abc"}
+	if got := renderSignature(sig); got != "✓ good signature from test@example.com (SHA256:abc)" {
+		t.Errorf("renderSignature() = %q", got)
+	}
+
+	m := NewLogModelWithSize(nil, 100, 20)
+	commits := testCommits(2)
+	commits[0].Signature = sig
+	commits[1].Signature = git.Signature{Status: git.SignatureUnchecked, Key: "ABCDEF"}
+	newModel, _ := m.Update(logMsg{commits: commits})
+	view := newModel.(LogModel).View()
+	for _, want := range []string{"0000000 ✓ Commit 0", "0000000 ? Commit 1"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q, got:\n%s", want, view)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -315,6 +316,7 @@ func (m StashDiffModel) renderHelp() string {
 
 // StashesModel is the bubbletea model for the stashes view
 type StashesModel struct {
+	repo            *git.Repo
 	stashes         []git.Stash
 	cursor          int
 	scrollOffset    int
@@ -330,24 +332,25 @@ type StashesModel struct {
 }
 
 // NewStashesModel creates a new stashes model
-func NewStashesModel() StashesModel {
-	return NewStashesModelWithOptions(false)
+func NewStashesModel(repo *git.Repo) StashesModel {
+	return NewStashesModelWithOptions(repo, false)
 }
 
 // NewStashesModelWithOptions creates a new stashes model with options
-func NewStashesModelWithOptions(showVerboseHelp bool) StashesModel {
+func NewStashesModelWithOptions(repo *git.Repo, showVerboseHelp bool) StashesModel {
 	return StashesModel{
+		repo:            repo,
 		showVerboseHelp: showVerboseHelp,
 	}
 }
 
 // Init initializes the model
 func (m StashesModel) Init() tea.Cmd {
-	return refreshStashes
+	return m.refreshStashes
 }
 
-func refreshStashes() tea.Msg {
-	stashes, err := git.GetStashes()
+func (m StashesModel) refreshStashes() tea.Msg {
+	stashes, err := m.repo.GetStashes(context.Background())
 	if err != nil {
 		return errMsg{err}
 	}
@@ -487,11 +490,11 @@ func (m StashesModel) doApplyStash() tea.Cmd {
 	}
 	stash := m.stashes[m.cursor]
 	return func() tea.Msg {
-		err := git.ApplyStash(stash.Index)
+		err := m.repo.ApplyStash(context.Background(), stash.Index)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshStashes()
+		return m.refreshStashes()
 	}
 }
 
@@ -501,11 +504,11 @@ func (m StashesModel) doPopStash() tea.Cmd {
 	}
 	stash := m.stashes[m.cursor]
 	return func() tea.Msg {
-		err := git.PopStash(stash.Index)
+		err := m.repo.PopStash(context.Background(), stash.Index)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshStashes()
+		return m.refreshStashes()
 	}
 }
 
@@ -515,11 +518,11 @@ func (m StashesModel) doDropStash() tea.Cmd {
 	}
 	stash := m.stashes[m.cursor]
 	return func() tea.Msg {
-		err := git.DropStash(stash.Index)
+		err := m.repo.DropStash(context.Background(), stash.Index)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshStashes()
+		return m.refreshStashes()
 	}
 }
 
@@ -207,8 +207,8 @@ func TestStashDiffModelAutoEnterDetailForSingleHunk(t *testing.T) {
 		UnstagedDiff: &git.DiffResult{
 			Files: []git.FileDiff{
 				{
-					Path: "file1.txt",
-					Hunks:   []git.Hunk{{FilePath: "file1.txt"}},
+					Path:  "file1.txt",
+					Hunks: []git.Hunk{{FilePath: "file1.txt"}},
 				},
 			},
 		},
@@ -322,7 +322,7 @@ func TestStashDiffModelVisibleLines(t *testing.T) {
 // Tests for StashesModel
 
 func TestNewStashesModel(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 
 	if m.cursor != 0 {
 		t.Errorf("cursor = %d, want 0", m.cursor)
@@ -336,7 +336,7 @@ func TestNewStashesModel(t *testing.T) {
 }
 
 func TestStashesModelInit(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	cmd := m.Init()
 
 	if cmd == nil {
@@ -345,7 +345,7 @@ func TestStashesModelInit(t *testing.T) {
 }
 
 func TestStashesModelNavigation(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 		{Index: 1, Message: "stash 2"},
@@ -384,7 +384,7 @@ func TestStashesModelNavigation(t *testing.T) {
 }
 
 func TestStashesModelHelpToggle(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 
 	// Toggle help
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
@@ -402,7 +402,7 @@ func TestStashesModelHelpToggle(t *testing.T) {
 }
 
 func TestStashesModelApplyStash(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 	}
@@ -416,7 +416,7 @@ func TestStashesModelApplyStash(t *testing.T) {
 }
 
 func TestStashesModelPopStash(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 	}
@@ -434,7 +434,7 @@ func TestStashesModelPopStash(t *testing.T) {
 }
 
 func TestStashesModelDropStash(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 	}
@@ -452,7 +452,7 @@ func TestStashesModelDropStash(t *testing.T) {
 }
 
 func TestStashesModelConfirmModeYes(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 	}
@@ -472,7 +472,7 @@ func TestStashesModelConfirmModeYes(t *testing.T) {
 }
 
 func TestStashesModelConfirmModeNo(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 	}
@@ -492,7 +492,7 @@ func TestStashesModelConfirmModeNo(t *testing.T) {
 }
 
 func TestStashesModelWindowResize(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
 	m = newModel.(StashesModel)
@@ -506,7 +506,7 @@ func TestStashesModelWindowResize(t *testing.T) {
 }
 
 func TestStashesModelStashesMsg(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 
 	stashes := []git.Stash{
 		{Index: 0, Message: "stash 1"},
@@ -522,14 +522,14 @@ func TestStashesModelStashesMsg(t *testing.T) {
 }
 
 func TestStashesModelStashDiffMsg(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 
 	diff := &git.CombinedDiffResult{
 		UnstagedDiff: &git.DiffResult{
 			Files: []git.FileDiff{
 				{
-					Path: "file1.txt",
-					Hunks:   []git.Hunk{{FilePath: "file1.txt"}},
+					Path:  "file1.txt",
+					Hunks: []git.Hunk{{FilePath: "file1.txt"}},
 				},
 			},
 		},
@@ -544,7 +544,7 @@ func TestStashesModelStashDiffMsg(t *testing.T) {
 }
 
 func TestStashesModelErrMsg(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 
 	newModel, _ := m.Update(errMsg{err: fmt.Errorf("test error")})
 	m = newModel.(StashesModel)
@@ -555,7 +555,7 @@ func TestStashesModelErrMsg(t *testing.T) {
 }
 
 func TestStashesModelView(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Branch: "main", Message: "WIP on main"},
 		{Index: 1, Message: "stash 2"},
@@ -575,7 +575,7 @@ func TestStashesModelView(t *testing.T) {
 }
 
 func TestStashesModelViewEmpty(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = nil
 
 	view := m.View()
@@ -586,7 +586,7 @@ func TestStashesModelViewEmpty(t *testing.T) {
 }
 
 func TestStashesModelViewHelp(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.showHelp = true
 
 	view := m.View()
@@ -597,7 +597,7 @@ func TestStashesModelViewHelp(t *testing.T) {
 }
 
 func TestStashesModelViewConfirmDrop(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 	}
@@ -612,7 +612,7 @@ func TestStashesModelViewConfirmDrop(t *testing.T) {
 }
 
 func TestStashesModelViewConfirmPop(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 	}
@@ -627,7 +627,7 @@ func TestStashesModelViewConfirmPop(t *testing.T) {
 }
 
 func TestStashesModelViewWithError(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.err = fmt.Errorf("test error")
 	m.stashes = []git.Stash{{Index: 0, Message: "stash"}}
 
@@ -639,7 +639,7 @@ func TestStashesModelViewWithError(t *testing.T) {
 }
 
 func TestStashesModelArrowKeys(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 		{Index: 1, Message: "stash 2"},
@@ -661,7 +661,7 @@ func TestStashesModelArrowKeys(t *testing.T) {
 }
 
 func TestStashesModelHelpModeBlocksNavigation(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 		{Index: 1, Message: "stash 2"},
@@ -677,7 +677,7 @@ func TestStashesModelHelpModeBlocksNavigation(t *testing.T) {
 }
 
 func TestStashesModelConfirmModeBlocksNavigation(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.stashes = []git.Stash{
 		{Index: 0, Message: "stash 1"},
 		{Index: 1, Message: "stash 2"},
@@ -694,7 +694,7 @@ func TestStashesModelConfirmModeBlocksNavigation(t *testing.T) {
 }
 
 func TestStashesModelCursorBoundsAfterStashesMsg(t *testing.T) {
-	m := NewStashesModel()
+	m := NewStashesModel(nil)
 	m.cursor = 5 // Out of bounds
 
 	stashes := []git.Stash{
@@ -1,12 +1,15 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 
 	"go-on-git/internal/git"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -15,7 +18,7 @@ import (
 // StatusItem represents a selectable item in the status view
 type StatusItem struct {
 	File    git.FileStatus
-	Section string // "staged", "unstaged", "untracked"
+	Section string // "staged", "conflicted", "unstaged", "untracked"
 }
 
 type confirmAction int
@@ -25,9 +28,19 @@ const (
 	confirmDiscard
 	confirmPush
 	confirmPushNew
+	confirmPushRejected
+	confirmForcePush
+	confirmPull
 	confirmStash
+	confirmTakeOurs
+	confirmTakeTheirs
+	confirmAbort
+	confirmAutosquash
 )
 
+// fixupCandidates is how many recent commits the fixup picker offers
+const fixupCandidates = 10
+
 type stashMode int
 
 const (
@@ -38,65 +51,85 @@ const (
 
 // StatusModel is the bubbletea model for the status view
 type StatusModel struct {
-	items           []StatusItem
-	cursor          int
-	scrollOffset    int
-	selected        map[int]bool
-	visualMode      bool
-	visualStart     int
-	status          *git.StatusResult
-	branchStatus    git.BranchStatus
-	showHelp        bool
-	showVerboseHelp bool
-	confirmMode     confirmAction
-	confirmInput    string
+	repo                *git.Repo
+	items               []StatusItem
+	cursor              int
+	scrollOffset        int
+	selected            map[int]bool
+	visualMode          bool
+	visualStart         int
+	status              *git.StatusResult
+	branchStatus        git.BranchStatus
+	showHelp            bool
+	showVerboseHelp     bool
+	confirmMode         confirmAction
+	confirmInput        string
 	pendingPushRemote   string
 	stashMode           stashMode
 	stashInput          textinput.Model
 	pendingStashMode    stashMode
 	pendingStashMessage string
 	commitMode          bool
-	commitInput     textinput.Model
-	quitting        bool
-	lastKey         string
-	err             error
-	width           int
-	height          int
+	commitInput         textarea.Model
+	commitTemplate      bool // commitInput was filled from commit.template
+	conventional        git.ConventionalRules
+	assistant           conventionalAssistant // picks the Conventional Commits header
+	trailerPicker       trailerPicker         // picks a trailer to add to the message
+	messageHistory      []string              // earlier messages, newest first
+	historyIndex        int                   // message shown from messageHistory, -1 for the draft
+	historyDraft        string                // the message being written while browsing history
+	savedMessage        string                // from COMMIT_EDITMSG
+	lastSubject         string                // HEAD's subject
+	signingFormat       string                // key format commits are signed with, "" if unsigned
+	amending            bool                  // commitMode rewrites HEAD's message
+	fixupMode           bool                  // picking the commit to fix up
+	fixupCommits        []git.Commit
+	fixupCursor         int
+	fixupTarget         git.Commit         // target of the fixup! commit just made
+	fetchMenu           fetchMenu          // picks the remote to fetch
+	pushMenu            pushMenu           // picks where and how to push
+	pendingPush         git.PushOptions    // push waiting for confirmation
+	expectedRemote      string             // where pendingPush expects the remote branch
+	progress            transferProgress   // progress of the running push, fetch or pull
+	notice              string             // result of the last fetch or pull
+	runningOp           string             // name of the in-flight cancellable operation
+	cancelOp            context.CancelFunc // cancels runningOp
+	quitting            bool
+	lastKey             string
+	err                 error
+	width               int
+	height              int
 }
 
 // NewStatusModel creates a new status model
-func NewStatusModel() StatusModel {
-	return NewStatusModelWithHelp(false)
+func NewStatusModel(repo *git.Repo) StatusModel {
+	return NewStatusModelWithHelp(repo, false)
 }
 
 // NewStatusModelWithHelp creates a new status model with optional help mode
-func NewStatusModelWithHelp(showHelp bool) StatusModel {
+func NewStatusModelWithHelp(repo *git.Repo, showHelp bool) StatusModel {
 	ti := textinput.New()
 	ti.Placeholder = "Stash message (optional)"
 	ti.CharLimit = 200
 	ti.Width = 40
 
-	ci := textinput.New()
-	ci.Placeholder = "Commit message"
-	ci.CharLimit = 200
-	ci.Width = 50
-
 	return StatusModel{
+		repo:            repo,
 		selected:        make(map[int]bool),
 		stashInput:      ti,
-		commitInput:     ci,
+		commitInput:     newMessageEditor(),
 		showVerboseHelp: showHelp,
 	}
 }
 
 // isBlocking returns true if the model is in a mode that shouldn't be interrupted by auto-refresh
 func (m StatusModel) isBlocking() bool {
-	return m.confirmMode != confirmNone || m.commitMode || m.stashMode != stashNone || m.showHelp || m.visualMode || len(m.selected) > 0
+	return m.runningOp != "" || m.confirmMode != confirmNone || m.commitMode || m.fixupMode || m.fetchMenu.active() || m.pushMenu.active() || m.stashMode != stashNone || m.showHelp || m.visualMode || len(m.selected) > 0
 }
 
 // Init initializes the model
 func (m StatusModel) Init() tea.Cmd {
-	return refreshStatus
+	return m.refreshStatus
 }
 
 // Update handles messages
@@ -154,6 +187,109 @@ func (m StatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 			}
+			// Push was rejected: offer to integrate the remote commits first,
+			// or to overwrite them
+			if m.confirmMode == confirmPushRejected {
+				switch key {
+				case "f":
+					m.confirmMode = confirmNone
+					remote, branch, err := m.repo.Upstream(context.Background())
+					if err != nil {
+						m.err = err
+						return m, nil
+					}
+					if remote == "" {
+						m.err = fmt.Errorf("no upstream to force-push to")
+						return m, nil
+					}
+					return m.startPush(git.PushOptions{Remote: remote, Branch: branch, ForceWithLease: true})
+				case "r", "m":
+					strategy := git.PullMerge
+					if key == "r" {
+						strategy = git.PullRebase
+					}
+					m.confirmMode = confirmNone
+					m.err = nil
+					return m, m.doPull(m.startOperation("pull"), strategy)
+				case "n", "N", "esc":
+					m.confirmMode = confirmNone
+					return m, nil
+				}
+				return m, nil
+			}
+			// Pick how to integrate the upstream branch
+			if m.confirmMode == confirmPull {
+				strategy, ok := map[string]git.PullStrategy{
+					"m": git.PullMerge,
+					"r": git.PullRebase,
+					"f": git.PullFastForward,
+				}[key]
+				switch {
+				case ok:
+					m.confirmMode = confirmNone
+					m.err = nil
+					return m, m.doPull(m.startOperation("pull"), strategy)
+				case key == "n" || key == "N" || key == "esc":
+					m.confirmMode = confirmNone
+				}
+				return m, nil
+			}
+			// Simple y/n confirmation for taking one side of a conflict
+			if m.confirmMode == confirmTakeOurs || m.confirmMode == confirmTakeTheirs {
+				switch key {
+				case "y", "Y":
+					ours := m.confirmMode == confirmTakeOurs
+					m.confirmMode = confirmNone
+					cmd := m.takeSide(ours)
+					m.selected = make(map[int]bool)
+					m.visualMode = false
+					return m, cmd
+				case "n", "N", "esc":
+					m.confirmMode = confirmNone
+					return m, nil
+				}
+				return m, nil
+			}
+			// Fold the fixup! commit just made into its target right away
+			if m.confirmMode == confirmAutosquash {
+				switch key {
+				case "y", "Y":
+					m.confirmMode = confirmNone
+					m.err = nil
+					return m, m.doAutosquash(m.fixupTarget)
+				case "n", "N", "esc":
+					m.confirmMode = confirmNone
+					return m, nil
+				}
+				return m, nil
+			}
+			// Simple y/n confirmation for a force push, showing the lease
+			if m.confirmMode == confirmForcePush {
+				switch key {
+				case "y", "Y":
+					m.confirmMode = confirmNone
+					m.err = nil
+					return m, m.doPushWith(m.startOperation("push"), m.pendingPush)
+				case "n", "N", "esc":
+					m.confirmMode = confirmNone
+					m.pendingPush = git.PushOptions{}
+					return m, nil
+				}
+				return m, nil
+			}
+			// Simple y/n confirmation for aborting the operation in progress
+			if m.confirmMode == confirmAbort {
+				switch key {
+				case "y", "Y":
+					m.confirmMode = confirmNone
+					m.err = nil
+					return m, m.doOperationStep("abort", m.repo.AbortOperation)
+				case "n", "N", "esc":
+					m.confirmMode = confirmNone
+					return m, nil
+				}
+				return m, nil
+			}
 			// Simple y/n confirmation for push
 			switch key {
 			case "y", "Y":
@@ -161,10 +297,11 @@ func (m StatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				remote := m.pendingPushRemote
 				m.confirmMode = confirmNone
 				m.pendingPushRemote = ""
+				ctx := m.startOperation("push")
 				if action == confirmPushNew {
-					return m, m.doPushSetUpstream(remote)
+					return m, m.doPushSetUpstream(ctx, remote)
 				}
-				return m, m.doPush()
+				return m, m.doPush(ctx)
 			case "n", "N", "esc":
 				m.confirmMode = confirmNone
 				m.pendingPushRemote = ""
@@ -197,28 +334,133 @@ func (m StatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Handle fixup target picker
+		if m.fixupMode {
+			switch key {
+			case Keys.Down, "down":
+				m.fixupCursor = min(m.fixupCursor+1, len(m.fixupCommits)-1)
+			case Keys.Up, "up":
+				m.fixupCursor = max(m.fixupCursor-1, 0)
+			case "enter":
+				m.fixupMode = false
+				return m, m.doFixup(m.fixupCommits[m.fixupCursor])
+			case "esc":
+				m.fixupMode = false
+			}
+			return m, nil
+		}
+
+		// Handle fetch menu
+		if m.fetchMenu.active() {
+			var opts *git.FetchOptions
+			m.fetchMenu, opts = m.fetchMenu.update(key)
+			if opts != nil {
+				m.err = nil
+				return m, m.doFetch(m.startOperation("fetch"), *opts)
+			}
+			return m, nil
+		}
+
+		// Handle push menu
+		if m.pushMenu.active() {
+			var opts *git.PushOptions
+			m.pushMenu, opts = m.pushMenu.update(key)
+			if opts != nil {
+				return m.startPush(*opts)
+			}
+			return m, nil
+		}
+
 		// Handle commit input mode
 		if m.commitMode {
+			if m.assistant.active() {
+				m.assistant = m.assistant.update(key)
+				if !m.assistant.active() {
+					setMessage(&m.commitInput, m.assistant.prefix()+m.commitInput.Value())
+				}
+				return m, nil
+			}
+			if m.trailerPicker.active() {
+				var trailer *git.Trailer
+				var cmd tea.Cmd
+				m.trailerPicker, trailer, cmd = m.trailerPicker.update(msg)
+				if trailer != nil {
+					return m, m.addTrailer(m.commitInput.Value(), *trailer)
+				}
+				return m, cmd
+			}
 			switch key {
-			case "enter":
+			case "ctrl+s":
 				message := m.commitInput.Value()
-				m.commitMode = false
-				m.commitInput.Reset()
-				m.commitInput.Blur()
-				if message != "" {
-					return m, m.doCommit(message)
+				if m.commitTemplate {
+					message = git.StripComments(message)
 				}
-				return m, nil
+				if strings.TrimSpace(message) == "" {
+					m.err = fmt.Errorf("commit message is empty")
+					return m, nil
+				}
+				if m.conventional.Enabled {
+					if err := m.conventional.Check(context.Background(), message); err != nil {
+						m.err = err
+						return m, nil
+					}
+				}
+				amending := m.amending
+				m.closeCommitInput()
+				if amending {
+					return m, m.doAmend(message)
+				}
+				return m, m.doCommit(message)
 			case "esc":
-				m.commitMode = false
-				m.commitInput.Reset()
-				m.commitInput.Blur()
+				// Keep an abandoned message for next time
+				message := strings.TrimSpace(m.commitInput.Value())
+				amending := m.amending
+				m.closeCommitInput()
+				if message != "" && !amending {
+					return m, m.saveMessage(message)
+				}
+				return m, nil
+			case "up":
+				// Older messages, once the cursor is on the first line
+				if m.commitInput.Line() == 0 && m.historyIndex+1 < len(m.messageHistory) {
+					if m.historyIndex == -1 {
+						m.historyDraft = m.commitInput.Value()
+					}
+					m.historyIndex++
+					setMessage(&m.commitInput, m.messageHistory[m.historyIndex])
+					return m, nil
+				}
+			case "down":
+				// Newer messages and then the draft, from the last line
+				if m.commitInput.Line() == m.commitInput.LineCount()-1 && m.historyIndex >= 0 {
+					m.historyIndex--
+					if m.historyIndex == -1 {
+						setMessage(&m.commitInput, m.historyDraft)
+					} else {
+						setMessage(&m.commitInput, m.messageHistory[m.historyIndex])
+					}
+					return m, nil
+				}
+			case "ctrl+r":
+				if m.savedMessage == "" {
+					m.err = fmt.Errorf("git has no saved commit message")
+					return m, nil
+				}
+				setMessage(&m.commitInput, m.savedMessage)
+				return m, nil
+			case "ctrl+o":
+				return m, m.loadTrailers
+			case "ctrl+l":
+				if m.lastSubject == "" {
+					m.err = fmt.Errorf("no previous commit")
+					return m, nil
+				}
+				setMessage(&m.commitInput, m.lastSubject)
 				return m, nil
-			default:
-				var cmd tea.Cmd
-				m.commitInput, cmd = m.commitInput.Update(msg)
-				return m, cmd
 			}
+			var cmd tea.Cmd
+			m.commitInput, cmd = m.commitInput.Update(msg)
+			return m, cmd
 		}
 
 		// Check for gg sequence (go to top)
@@ -343,14 +585,54 @@ func (m StatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.confirmMode = confirmDiscard
 			}
 			return m, nil
+		case key == Keys.Resolve:
+			cmd := m.markResolved()
+			m.selected = make(map[int]bool)
+			m.visualMode = false
+			return m, cmd
+		case key == Keys.TakeOurs:
+			if len(m.conflictedItems()) > 0 {
+				m.confirmMode = confirmTakeOurs
+			}
+			return m, nil
+		case key == Keys.TakeTheirs:
+			if len(m.conflictedItems()) > 0 {
+				m.confirmMode = confirmTakeTheirs
+			}
+			return m, nil
+		case key == Keys.Continue:
+			if m.runningOp == "" && m.operation().CanContinue() {
+				m.err = nil
+				return m, m.doOperationStep("continue", m.repo.ContinueOperation)
+			}
+			return m, nil
+		case key == Keys.Skip:
+			if m.runningOp == "" && m.operation().CanSkip() {
+				m.err = nil
+				return m, m.doOperationStep("skip", m.repo.SkipOperation)
+			}
+			return m, nil
+		case key == Keys.Abort:
+			if m.runningOp == "" && m.operation() != git.OpNone {
+				m.confirmMode = confirmAbort
+			}
+			return m, nil
+		case key == Keys.Cancel:
+			if m.cancelOp != nil {
+				m.cancelOp()
+			}
+			return m, nil
 		case key == Keys.Push:
+			if m.runningOp != "" {
+				return m, nil
+			}
 			if m.branchStatus.Remote != "" && m.branchStatus.Ahead > 0 {
 				m.confirmMode = confirmPush
 				return m, nil
 			}
 			if m.branchStatus.Remote == "" {
 				// No upstream - detect remotes and offer to push with -u
-				remotes, err := git.GetRemotes()
+				remotes, err := m.repo.GetRemotes(context.Background())
 				if err != nil {
 					m.err = err
 					return m, nil
@@ -364,19 +646,76 @@ func (m StatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.confirmMode = confirmPushNew
 				return m, nil
 			}
-			return m, m.doPush()
+			return m, m.doPush(m.startOperation("push"))
+		case key == Keys.PushMenu:
+			if m.runningOp != "" {
+				return m, nil
+			}
+			remotes, err := m.repo.GetRemotes(context.Background())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			if len(remotes) == 0 {
+				m.err = fmt.Errorf("no remotes configured")
+				return m, nil
+			}
+			m.pushMenu = newPushMenu(remotes, m.branchStatus.Remote, m.branchStatus.Name)
+			return m, nil
+		case key == Keys.Fetch:
+			if m.runningOp != "" {
+				return m, nil
+			}
+			remotes, err := m.repo.GetRemotes(context.Background())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			if len(remotes) == 0 {
+				m.err = fmt.Errorf("no remotes configured")
+				return m, nil
+			}
+			m.fetchMenu = newFetchMenu(remotes, m.branchStatus.Remote)
+			return m, nil
+		case key == Keys.Pull:
+			if m.runningOp != "" {
+				return m, nil
+			}
+			if m.branchStatus.Remote == "" {
+				m.err = fmt.Errorf("no upstream to pull from")
+				return m, nil
+			}
+			m.confirmMode = confirmPull
+			return m, nil
 		case key == Keys.Commit:
 			// Inline commit with message
 			if m.status != nil && len(m.status.Staged) > 0 {
-				m.commitMode = true
-				m.commitInput.Focus()
-				return m, textinput.Blink
+				return m, tea.Batch(m.openCommitInput(""), m.loadCommitSetup)
 			}
 			return m, nil
 		case key == Keys.CommitEdit:
 			// Run git commit with editor
-			m.quitting = true
-			return m, runGitCommit()
+			if m.runningOp != "" {
+				return m, nil
+			}
+			return m, m.runGitCommit()
+		case key == Keys.Amend:
+			// Fold the staged changes into HEAD, keeping its message
+			if m.status == nil || len(m.status.Staged) == 0 {
+				m.err = fmt.Errorf("nothing staged to amend")
+				return m, nil
+			}
+			return m, m.doAmend("")
+		case key == Keys.AmendEdit:
+			// Amend HEAD with a new message, starting from the current one
+			return m, m.loadHeadCommit
+		case key == Keys.Fixup:
+			// Pick a recent commit for the staged changes to fix up
+			if m.status == nil || len(m.status.Staged) == 0 {
+				m.err = fmt.Errorf("nothing staged to fix up")
+				return m, nil
+			}
+			return m, m.loadFixupCandidates
 		case key == Keys.Stash:
 			// Stash selected file(s)
 			if len(m.items) > 0 {
@@ -415,14 +754,152 @@ func (m StatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.selected = make(map[int]bool)
 		return m, nil
 
+	case commitSetupMsg:
+		m.conventional = msg.rules
+		m.signingFormat = msg.signing
+		m.messageHistory = msg.history
+		m.savedMessage = msg.saved
+		m.lastSubject = msg.lastSubject
+		// The template and the assistant only start a new message
+		if !m.commitMode || m.amending || m.commitInput.Value() != "" {
+			return m, nil
+		}
+		if msg.template != "" {
+			setMessage(&m.commitInput, msg.template)
+			m.commitTemplate = true
+		}
+		if msg.rules.Enabled && m.status != nil {
+			m.assistant = newConventionalAssistant(msg.rules, m.status.Staged)
+		}
+		return m, nil
+
+	case trailersMsg:
+		if !m.commitMode {
+			return m, nil
+		}
+		if len(msg.trailers) == 0 {
+			m.err = fmt.Errorf("no trailers to offer: set user.name and user.email or go-on-git.trailer")
+			return m, nil
+		}
+		m.trailerPicker = newTrailerPicker(msg.trailers)
+		return m, nil
+
+	case trailerAddedMsg:
+		if m.commitMode {
+			m.commitInput.SetValue(msg.message)
+		}
+		return m, nil
+
+	case headCommitMsg:
+		if m.commitMode {
+			return m, nil
+		}
+		message := msg.commit.Subject
+		if msg.commit.Body != "" {
+			message += "\n\n" + msg.commit.Body
+		}
+		m.amending = true
+		return m, tea.Batch(m.openCommitInput(message), m.loadCommitSetup)
+
+	case fixupCandidatesMsg:
+		if len(msg.commits) == 0 {
+			m.err = fmt.Errorf("no commits to fix up")
+			return m, nil
+		}
+		m.fixupMode = true
+		m.fixupCommits = msg.commits
+		m.fixupCursor = 0
+		return m, nil
+
+	case commitDoneMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		return m, m.refreshStatus
+
+	case fixupDoneMsg:
+		// Offer to squash the new fixup! commit into its target
+		m.fixupTarget = msg.target
+		if !m.commitMode && m.stashMode == stashNone && m.confirmMode == confirmNone {
+			m.confirmMode = confirmAutosquash
+		}
+		return m, m.refreshStatus
+
+	case progressMsg:
+		if m.runningOp != "" {
+			m.progress = m.progress.update(msg.progress)
+		}
+		return m, waitForProgress(msg.updates)
+
+	case transferDoneMsg:
+		newM, cmd := m.Update(msg.result)
+		m = newM.(StatusModel)
+		m.progress = transferProgress{}
+		summary := msg.progress.summary()
+		switch result := msg.result.(type) {
+		case operationDoneMsg:
+			if result.err == nil && summary != "" {
+				m.notice = "Pushed: " + summary
+			}
+		case syncDoneMsg:
+			if result.err == nil && summary != "" {
+				m.notice += " · " + summary
+			}
+		}
+		return m, cmd
+
+	case syncDoneMsg:
+		newM, cmd := m.Update(operationDoneMsg{msg.op, msg.err})
+		m = newM.(StatusModel)
+		if msg.err == nil {
+			m.notice = syncSummary(msg.done, msg.before, msg.after)
+		}
+		return m, cmd
+
+	case operationDoneMsg:
+		if m.cancelOp != nil {
+			m.cancelOp()
+		}
+		m.runningOp = ""
+		m.cancelOp = nil
+		switch {
+		case errors.Is(msg.err, context.Canceled):
+			m.err = fmt.Errorf("%s cancelled", msg.op)
+		case errors.Is(msg.err, context.DeadlineExceeded):
+			m.err = fmt.Errorf("%s timed out", msg.op)
+		case errors.Is(msg.err, git.ErrPushRejected):
+			m.err = git.ErrPushRejected
+			if !m.commitMode && m.stashMode == stashNone && m.confirmMode == confirmNone {
+				m.confirmMode = confirmPushRejected
+			}
+		case msg.err != nil:
+			m.err = msg.err
+		}
+		return m, m.refreshStatus
+
 	case errMsg:
 		m.err = msg.err
+		var gitErr *git.Error
+		if errors.Is(msg.err, git.ErrSigningFailed) && errors.As(msg.err, &gitErr) {
+			m.err = fmt.Errorf("%w: %s (check user.signingkey and gpg.format)", git.ErrSigningFailed, gitErr.Message())
+		}
 		return m, nil
 	}
 
 	return m, nil
 }
 
+// startOperation marks a cancellable operation as running and returns the
+// context it should run under
+func (m *StatusModel) startOperation(name string) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.runningOp = name
+	m.cancelOp = cancel
+	m.notice = ""
+	m.progress = transferProgress{}
+	return ctx
+}
+
 func (m *StatusModel) updateVisualSelection() {
 	m.selected = make(map[int]bool)
 	start, end := m.visualStart, m.cursor
@@ -442,6 +919,25 @@ func (m StatusModel) visibleLines() int {
 	if m.showVerboseHelp {
 		reserved += 3
 	}
+	if m.fixupMode {
+		reserved += len(m.fixupCommits) + 1
+	}
+	if m.fetchMenu.active() {
+		reserved += m.fetchMenu.lines()
+	}
+	if m.pushMenu.active() {
+		reserved += m.pushMenu.lines()
+	}
+	if m.runningOp != "" && m.progress.seen {
+		reserved++
+	}
+	if m.assistant.active() {
+		reserved += m.assistant.lines()
+	} else if m.trailerPicker.active() {
+		reserved += m.trailerPicker.lines()
+	} else if m.commitMode {
+		reserved += messageEditorLines(m.commitInput) + 1
+	}
 	if m.height <= reserved {
 		return 10 // fallback minimum
 	}
@@ -512,6 +1008,9 @@ func buildItems(status *git.StatusResult) []StatusItem {
 	for _, f := range status.Staged {
 		items = append(items, StatusItem{File: f, Section: "staged"})
 	}
+	for _, f := range status.Conflicted {
+		items = append(items, StatusItem{File: f, Section: "conflicted"})
+	}
 	for _, f := range status.Unstaged {
 		items = append(items, StatusItem{File: f, Section: "unstaged"})
 	}
@@ -533,15 +1032,17 @@ func (m StatusModel) toggleStage() tea.Cmd {
 			var err error
 			switch item.Section {
 			case "staged":
-				err = git.UnstageFile(item.File.Path)
+				err = m.repo.UnstageFile(context.Background(), item.File.Path)
 			case "unstaged", "untracked":
-				err = git.StageFile(item.File.Path)
+				err = m.repo.StageFile(context.Background(), item.File.Path)
+			case "conflicted":
+				err = m.repo.MarkResolved(context.Background(), item.File.Path)
 			}
 			if err != nil {
 				return errMsg{err}
 			}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
 	}
 }
 
@@ -553,14 +1054,19 @@ func (m StatusModel) stageFiles() tea.Cmd {
 
 	return func() tea.Msg {
 		for _, item := range items {
-			// Only stage unstaged/untracked files
-			if item.Section == "unstaged" || item.Section == "untracked" {
-				if err := git.StageFile(item.File.Path); err != nil {
-					return errMsg{err}
-				}
+			// Only stage unstaged/untracked files; staging a conflicted file resolves it
+			var err error
+			switch item.Section {
+			case "unstaged", "untracked":
+				err = m.repo.StageFile(context.Background(), item.File.Path)
+			case "conflicted":
+				err = m.repo.MarkResolved(context.Background(), item.File.Path)
+			}
+			if err != nil {
+				return errMsg{err}
 			}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
 	}
 }
 
@@ -574,30 +1080,80 @@ func (m StatusModel) unstageFiles() tea.Cmd {
 		for _, item := range items {
 			// Only unstage staged files
 			if item.Section == "staged" {
-				if err := git.UnstageFile(item.File.Path); err != nil {
+				if err := m.repo.UnstageFile(context.Background(), item.File.Path); err != nil {
 					return errMsg{err}
 				}
 			}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
+	}
+}
+
+// conflictedItems returns the selected items that have unresolved conflicts
+func (m StatusModel) conflictedItems() []StatusItem {
+	var items []StatusItem
+	for _, item := range m.getSelectedItems() {
+		if item.Section == "conflicted" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (m StatusModel) markResolved() tea.Cmd {
+	items := m.conflictedItems()
+	if len(items) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		for _, item := range items {
+			if err := m.repo.MarkResolved(context.Background(), item.File.Path); err != nil {
+				return errMsg{err}
+			}
+		}
+		return m.refreshStatus()
+	}
+}
+
+// takeSide resolves the selected conflicted files with our or their version
+func (m StatusModel) takeSide(ours bool) tea.Cmd {
+	items := m.conflictedItems()
+	if len(items) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		for _, item := range items {
+			var err error
+			if ours {
+				err = m.repo.TakeOurs(context.Background(), item.File.Path)
+			} else {
+				err = m.repo.TakeTheirs(context.Background(), item.File.Path)
+			}
+			if err != nil {
+				return errMsg{err}
+			}
+		}
+		return m.refreshStatus()
 	}
 }
 
 func (m StatusModel) stageAll() tea.Cmd {
 	return func() tea.Msg {
-		if err := git.StageAll(); err != nil {
+		if err := m.repo.StageAll(context.Background()); err != nil {
 			return errMsg{err}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
 	}
 }
 
 func (m StatusModel) unstageAll() tea.Cmd {
 	return func() tea.Msg {
-		if err := git.UnstageAll(); err != nil {
+		if err := m.repo.UnstageAll(context.Background()); err != nil {
 			return errMsg{err}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
 	}
 }
 
@@ -613,58 +1169,323 @@ func (m StatusModel) doDiscard() tea.Cmd {
 			switch item.Section {
 			case "staged":
 				// Only unstage, preserving working tree changes (like git restore --staged)
-				err = git.UnstageFile(item.File.Path)
+				err = m.repo.UnstageFile(context.Background(), item.File.Path)
 			case "unstaged":
-				err = git.DiscardFile(item.File.Path)
+				err = m.repo.DiscardFile(context.Background(), item.File.Path)
 			case "untracked":
-				err = git.DiscardUntracked(item.File.Path)
+				err = m.repo.DiscardUntracked(context.Background(), item.File.Path)
 			}
 			if err != nil {
 				return errMsg{err}
 			}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
 	}
 }
 
-func (m StatusModel) doPush() tea.Cmd {
+func (m StatusModel) doPush(ctx context.Context) tea.Cmd {
+	return m.doPushWith(ctx, git.PushOptions{})
+}
+
+func (m StatusModel) doPushSetUpstream(ctx context.Context, remote string) tea.Cmd {
+	branch := m.branchStatus.Name
+	if branch == "" {
+		return func() tea.Msg { return operationDoneMsg{"push", git.ErrDetachedHead} }
+	}
+	return m.doPushWith(ctx, git.PushOptions{Remote: remote, Branch: branch, SetUpstream: true})
+}
+
+// startPush pushes with the options from the push menu. A force push
+// first asks for confirmation, showing where the lease expects the
+// remote branch to be.
+func (m StatusModel) startPush(opts git.PushOptions) (tea.Model, tea.Cmd) {
+	m.err = nil
+	if !opts.ForceWithLease {
+		return m, m.doPushWith(m.startOperation("push"), opts)
+	}
+	expected, err := m.repo.RemoteBranchHead(context.Background(), opts.Remote, opts.Branch)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if expected == "" {
+		m.err = fmt.Errorf("%s/%s has not been fetched; fetch it before force-pushing", opts.Remote, opts.Branch)
+		return m, nil
+	}
+	// --force-if-includes only works against the remote-tracking branch,
+	// which is where expected came from
+	if !opts.ForceIfIncludes {
+		opts.Lease = expected
+	}
+	m.pendingPush = opts
+	m.expectedRemote = expected
+	m.confirmMode = confirmForcePush
+	return m, nil
+}
+
+func (m StatusModel) doPushWith(ctx context.Context, opts git.PushOptions) tea.Cmd {
+	return withProgress(func(onProgress func(git.Progress)) tea.Msg {
+		opts.Progress = onProgress
+		return operationDoneMsg{"push", m.repo.PushWith(ctx, opts)}
+	})
+}
+
+func (m StatusModel) doPull(ctx context.Context, strategy git.PullStrategy) tea.Cmd {
+	return m.doSync(ctx, "pull", fmt.Sprintf("Pulled (%s)", strategy), func(ctx context.Context, onProgress func(git.Progress)) error {
+		return m.repo.PullWith(ctx, git.PullOptions{Strategy: strategy, Progress: onProgress})
+	})
+}
+
+func (m StatusModel) doFetch(ctx context.Context, opts git.FetchOptions) tea.Cmd {
+	done := "Fetched " + opts.Remote
+	if opts.All {
+		done = "Fetched all remotes"
+	}
+	if opts.Prune {
+		done += " (pruned)"
+	}
+	return m.doSync(ctx, "fetch", done, func(ctx context.Context, onProgress func(git.Progress)) error {
+		opts.Progress = onProgress
+		return m.repo.Fetch(ctx, opts)
+	})
+}
+
+// syncDoneMsg reports the end of a fetch or pull with the branch's
+// tracking status before and after it
+type syncDoneMsg struct {
+	op     string
+	done   string // what was done, for the summary
+	before git.BranchStatus
+	after  git.BranchStatus
+	err    error
+}
+
+// doSync runs a fetch or pull, noting how far ahead of and behind its
+// upstream the branch was before and is after
+func (m StatusModel) doSync(ctx context.Context, op, done string, run func(context.Context, func(git.Progress)) error) tea.Cmd {
+	return withProgress(func(onProgress func(git.Progress)) tea.Msg {
+		before := m.repo.GetBranchStatus(context.Background())
+		if err := run(ctx, onProgress); err != nil {
+			return syncDoneMsg{op: op, err: err}
+		}
+		after := m.repo.GetBranchStatus(context.Background())
+		return syncDoneMsg{op: op, done: done, before: before, after: after}
+	})
+}
+
+// syncSummary describes the branch's ahead/behind counts after a fetch or
+// pull, and how they changed
+func syncSummary(done string, before, after git.BranchStatus) string {
+	if after.Remote == "" {
+		return done + "; the branch has no upstream"
+	}
+	summary := fmt.Sprintf("%s: %d ahead, %d behind '%s'", done, after.Ahead, after.Behind, after.Remote)
+	if before.Remote == after.Remote && (before.Ahead != after.Ahead || before.Behind != after.Behind) {
+		summary += fmt.Sprintf(" (was %d ahead, %d behind)", before.Ahead, before.Behind)
+	}
+	return summary
+}
+
+// operation returns the merge, rebase, etc. currently in progress
+func (m StatusModel) operation() git.Operation {
+	if m.status == nil {
+		return git.OpNone
+	}
+	return m.status.Operation.Op
+}
+
+// doOperationStep runs continue, skip or abort for the operation in progress
+func (m StatusModel) doOperationStep(step string, run func(context.Context, git.Operation) error) tea.Cmd {
+	op := m.operation()
+	return func() tea.Msg {
+		return operationDoneMsg{fmt.Sprintf("%s --%s", op, step), run(context.Background(), op)}
+	}
+}
+
+// openCommitInput opens the message editor, starting from message
+func (m *StatusModel) openCommitInput(message string) tea.Cmd {
+	m.commitMode = true
+	m.commitTemplate = false
+	m.historyIndex = -1
+	m.historyDraft = ""
+	m.commitInput.SetWidth(messageEditorWidth(m.width))
+	setMessage(&m.commitInput, message)
+	return m.commitInput.Focus()
+}
+
+// closeCommitInput closes the message editor, discarding its contents
+func (m *StatusModel) closeCommitInput() {
+	m.commitMode = false
+	m.commitTemplate = false
+	m.amending = false
+	m.assistant = conventionalAssistant{}
+	m.trailerPicker = trailerPicker{}
+	m.commitInput.Reset()
+	m.commitInput.Blur()
+}
+
+type commitSetupMsg struct {
+	template    string
+	rules       git.ConventionalRules
+	history     []string
+	saved       string
+	lastSubject string
+	signing     string
+}
+
+// loadCommitSetup reads what the message editor starts from: commit.template,
+// the Conventional Commits rules to check against, the earlier messages
+// that can be reused, and whether the commit will be signed
+func (m StatusModel) loadCommitSetup() tea.Msg {
+	ctx := context.Background()
+	var setup commitSetupMsg
+	var err error
+	if setup.template, err = m.repo.CommitTemplate(ctx); err != nil {
+		return errMsg{err}
+	}
+	if setup.rules, err = m.repo.ConventionalRules(ctx); err != nil {
+		return errMsg{err}
+	}
+	if setup.history, err = m.repo.MessageHistory(); err != nil {
+		return errMsg{err}
+	}
+	if setup.saved, err = m.repo.SavedCommitMessage(); err != nil {
+		return errMsg{err}
+	}
+	if setup.signing, err = m.repo.SigningFormat(ctx); err != nil {
+		return errMsg{err}
+	}
+	commits, err := m.repo.GetLog(ctx, git.LogFilter{}, 0, 1)
+	if err != nil {
+		return errMsg{err}
+	}
+	if len(commits) > 0 {
+		setup.lastSubject = commits[0].Subject
+	}
+	return setup
+}
+
+type trailersMsg struct {
+	trailers []git.Trailer
+}
+
+type trailerAddedMsg struct {
+	message string
+}
+
+// loadTrailers collects the trailers to offer for the message
+func (m StatusModel) loadTrailers() tea.Msg {
+	trailers, err := m.repo.TrailerSuggestions(context.Background())
+	if err != nil {
+		return errMsg{err}
+	}
+	return trailersMsg{trailers}
+}
+
+// addTrailer adds trailer to message, formatted by git interpret-trailers
+func (m StatusModel) addTrailer(message string, trailer git.Trailer) tea.Cmd {
 	return func() tea.Msg {
-		err := git.Push()
+		message, err := m.repo.AddTrailers(context.Background(), message, []git.Trailer{trailer})
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshStatus()
+		return trailerAddedMsg{message}
 	}
 }
 
-func (m StatusModel) doPushSetUpstream(remote string) tea.Cmd {
-	branch := m.branchStatus.Name
+// saveMessage records message in the repository's message history
+func (m StatusModel) saveMessage(message string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.repo.SaveMessage(message); err != nil {
+			return errMsg{err}
+		}
+		return nil
+	}
+}
+
+// headCommitMsg carries the commit HEAD points at, to amend
+type headCommitMsg struct {
+	commit git.Commit
+}
+
+// loadHeadCommit loads the commit HEAD points at
+func (m StatusModel) loadHeadCommit() tea.Msg {
+	commits, err := m.repo.GetLog(context.Background(), git.LogFilter{}, 0, 1)
+	if err != nil {
+		return errMsg{err}
+	}
+	if len(commits) == 0 {
+		return errMsg{fmt.Errorf("no commit to amend")}
+	}
+	return headCommitMsg{commits[0]}
+}
+
+// fixupCandidatesMsg carries the recent commits the fixup picker offers
+type fixupCandidatesMsg struct {
+	commits []git.Commit
+}
+
+// loadFixupCandidates loads the recent commits to pick a fixup target from
+func (m StatusModel) loadFixupCandidates() tea.Msg {
+	commits, err := m.repo.GetLog(context.Background(), git.LogFilter{}, 0, fixupCandidates)
+	if err != nil {
+		return errMsg{err}
+	}
+	return fixupCandidatesMsg{commits}
+}
+
+// doAmend amends HEAD with the staged changes; an empty message keeps HEAD's
+func (m StatusModel) doAmend(message string) tea.Cmd {
 	return func() tea.Msg {
-		err := git.PushSetUpstream(remote, branch)
+		// The history is a convenience; failing to save it doesn't stop the amend
+		m.repo.SaveMessage(message)
+		err := m.repo.AmendCommit(context.Background(), message)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
+	}
+}
+
+// doFixup commits the staged changes as a fixup! commit for target
+func (m StatusModel) doFixup(target git.Commit) tea.Cmd {
+	return func() tea.Msg {
+		err := m.repo.FixupCommit(context.Background(), target.Hash)
+		if err != nil {
+			return errMsg{err}
+		}
+		return fixupDoneMsg{target}
+	}
+}
+
+// doAutosquash folds the fixup! commit into its target. Like
+// doOperationStep it can't be cancelled, since killing git partway
+// through would leave the rebase half done.
+func (m StatusModel) doAutosquash(target git.Commit) tea.Cmd {
+	return func() tea.Msg {
+		return operationDoneMsg{"autosquash", m.repo.Autosquash(context.Background(), target)}
 	}
 }
 
 func (m StatusModel) doCommit(message string) tea.Cmd {
 	return func() tea.Msg {
-		err := git.Commit(message)
+		// Saved first, so a message whose commit fails can be reused
+		m.repo.SaveMessage(message)
+		err := m.repo.Commit(context.Background(), message)
 		if err != nil {
 			return errMsg{err}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
 	}
 }
 
 func (m StatusModel) doStash(mode stashMode, message string) tea.Cmd {
 	if mode == stashAll {
 		return func() tea.Msg {
-			if err := git.StashAll(message); err != nil {
+			if err := m.repo.StashAll(context.Background(), message); err != nil {
 				return errMsg{err}
 			}
-			return refreshStatus()
+			return m.refreshStatus()
 		}
 	}
 
@@ -680,10 +1501,10 @@ func (m StatusModel) doStash(mode stashMode, message string) tea.Cmd {
 	}
 
 	return func() tea.Msg {
-		if err := git.StashFiles(paths, message); err != nil {
+		if err := m.repo.StashFiles(context.Background(), paths, message); err != nil {
 			return errMsg{err}
 		}
-		return refreshStatus()
+		return m.refreshStatus()
 	}
 }
 
@@ -697,6 +1518,9 @@ func (m StatusModel) View() string {
 	if m.err != nil {
 		content.WriteString(StyleUnstaged.Render(fmt.Sprintf("Error: %v", m.err)))
 		content.WriteString("\n")
+	} else if m.notice != "" {
+		content.WriteString(StyleStaged.Render(m.notice))
+		content.WriteString("\n")
 	}
 
 	if m.status == nil {
@@ -707,7 +1531,7 @@ func (m StatusModel) View() string {
 
 	if m.status.IsEmpty() {
 		// Branch status info
-		content.WriteString(fmt.Sprintf("On branch %s", m.branchStatus.Name))
+		content.WriteString(m.renderBranchName())
 		content.WriteString("\n")
 		if m.branchStatus.Remote != "" {
 			if m.branchStatus.Ahead > 0 && m.branchStatus.Behind > 0 {
@@ -731,12 +1555,19 @@ func (m StatusModel) View() string {
 			}
 			content.WriteString("\n")
 		}
+		if m.status.Operation.InProgress() {
+			content.WriteString(m.renderOperationBanner())
+			content.WriteString("\n")
+		}
 		content.WriteString("\n")
 		content.WriteString(StyleEmpty.Render("Nothing to commit, working tree clean"))
 		content.WriteString("\n")
 
 		// Confirm prompt for push (when working tree is clean but have unpushed commits)
-		if m.confirmMode == confirmPush {
+		if m.confirmMode == confirmAbort {
+			content.WriteString("\n")
+			content.WriteString(m.renderAbortPrompt())
+		} else if m.confirmMode == confirmPush {
 			content.WriteString("\n")
 			if m.branchStatus.Ahead == 1 {
 				content.WriteString(fmt.Sprintf("Push 1 commit to '%s'? (y/n) ", m.branchStatus.Remote))
@@ -746,6 +1577,30 @@ func (m StatusModel) View() string {
 		} else if m.confirmMode == confirmPushNew {
 			content.WriteString("\n")
 			content.WriteString(fmt.Sprintf("Push branch '%s' to '%s'? (y/n) ", m.branchStatus.Name, m.pendingPushRemote))
+		} else if m.confirmMode == confirmPushRejected {
+			content.WriteString("\n")
+			content.WriteString(m.renderPushRejectedPrompt())
+		} else if m.confirmMode == confirmForcePush {
+			content.WriteString("\n")
+			content.WriteString(m.renderForcePushPrompt())
+		} else if m.confirmMode == confirmPull {
+			content.WriteString("\n")
+			content.WriteString(m.renderPullPrompt())
+		} else if m.fetchMenu.active() {
+			content.WriteString("\n")
+			content.WriteString(m.fetchMenu.view())
+		} else if m.pushMenu.active() {
+			content.WriteString("\n")
+			content.WriteString(m.pushMenu.view())
+		} else if m.confirmMode == confirmAutosquash {
+			content.WriteString("\n")
+			content.WriteString(m.renderAutosquashPrompt())
+		} else if m.commitMode {
+			content.WriteString("\n")
+			content.WriteString(m.renderCommitInput())
+		} else if m.runningOp != "" {
+			content.WriteString("\n")
+			content.WriteString(m.renderRunningOp())
 		}
 
 		if m.showVerboseHelp {
@@ -756,7 +1611,7 @@ func (m StatusModel) View() string {
 	}
 
 	// Branch status info
-	content.WriteString(fmt.Sprintf("On branch %s", m.branchStatus.Name))
+	content.WriteString(m.renderBranchName())
 	content.WriteString("\n")
 	if m.branchStatus.Remote != "" {
 		if m.branchStatus.Ahead > 0 && m.branchStatus.Behind > 0 {
@@ -780,6 +1635,10 @@ func (m StatusModel) View() string {
 		}
 		content.WriteString("\n")
 	}
+	if m.status.Operation.InProgress() {
+		content.WriteString(m.renderOperationBanner())
+		content.WriteString("\n")
+	}
 	if m.visualMode && !m.quitting {
 		content.WriteString(StyleVisual.Render("-- VISUAL --"))
 	}
@@ -822,6 +1681,28 @@ func (m StatusModel) View() string {
 		}
 	}
 
+	if len(m.status.Conflicted) > 0 {
+		conflictedStart := itemIndex
+		conflictedEnd := itemIndex + len(m.status.Conflicted)
+		// Show section header if any conflicted items are visible
+		if conflictedEnd > visibleStart && conflictedStart < visibleEnd {
+			content.WriteString(StyleConflicted.Render("Unmerged paths:"))
+			content.WriteString("\n")
+			for i, f := range m.status.Conflicted {
+				if itemIndex >= visibleStart && itemIndex < visibleEnd {
+					content.WriteString(m.renderItem(itemIndex, f, "conflicted"))
+					content.WriteString("\n")
+				}
+				itemIndex++
+				if i == len(m.status.Conflicted)-1 && itemIndex <= visibleEnd {
+					content.WriteString("\n")
+				}
+			}
+		} else {
+			itemIndex += len(m.status.Conflicted)
+		}
+	}
+
 	if len(m.status.Unstaged) > 0 {
 		unstagedStart := itemIndex
 		unstagedEnd := itemIndex + len(m.status.Unstaged)
@@ -886,6 +1767,27 @@ func (m StatusModel) View() string {
 		}
 	} else if m.confirmMode == confirmPushNew {
 		content.WriteString(fmt.Sprintf("Push branch '%s' to '%s'? (y/n) ", m.branchStatus.Name, m.pendingPushRemote))
+	} else if m.confirmMode == confirmPushRejected {
+		content.WriteString(m.renderPushRejectedPrompt())
+	} else if m.confirmMode == confirmForcePush {
+		content.WriteString(m.renderForcePushPrompt())
+	} else if m.confirmMode == confirmPull {
+		content.WriteString(m.renderPullPrompt())
+	} else if m.confirmMode == confirmAbort {
+		content.WriteString(m.renderAbortPrompt())
+	} else if m.confirmMode == confirmAutosquash {
+		content.WriteString(m.renderAutosquashPrompt())
+	} else if m.confirmMode == confirmTakeOurs || m.confirmMode == confirmTakeTheirs {
+		side := "ours"
+		if m.confirmMode == confirmTakeTheirs {
+			side = "theirs"
+		}
+		items := m.conflictedItems()
+		if len(items) == 1 {
+			content.WriteString(StyleConfirm.Render(fmt.Sprintf("Resolve '%s' with %s? (y/n) ", items[0].File.DisplayPath, side)))
+		} else {
+			content.WriteString(StyleConfirm.Render(fmt.Sprintf("Resolve %d files with %s? (y/n) ", len(items), side)))
+		}
 	} else if m.confirmMode == confirmStash {
 		if m.pendingStashMode == stashAll {
 			content.WriteString(StyleConfirm.Render(fmt.Sprintf("Stash all changes? Type 'yes' to confirm: %s", m.confirmInput)))
@@ -911,9 +1813,15 @@ func (m StatusModel) View() string {
 		content.WriteString(m.stashInput.View())
 		content.WriteString(StyleMuted.Render("  (enter to confirm, esc to cancel)"))
 	} else if m.commitMode {
-		content.WriteString("Commit message: ")
-		content.WriteString(m.commitInput.View())
-		content.WriteString(StyleMuted.Render("  (enter to commit, esc to cancel)"))
+		content.WriteString(m.renderCommitInput())
+	} else if m.fixupMode {
+		content.WriteString(m.renderFixupPicker())
+	} else if m.fetchMenu.active() {
+		content.WriteString(m.fetchMenu.view())
+	} else if m.pushMenu.active() {
+		content.WriteString(m.pushMenu.view())
+	} else if m.runningOp != "" {
+		content.WriteString(m.renderRunningOp())
 	}
 
 	// Show persistent help bar when in help mode
@@ -925,6 +1833,123 @@ func (m StatusModel) View() string {
 	return content.String()
 }
 
+// renderBranchName renders the first line of the branch header
+func (m StatusModel) renderBranchName() string {
+	if m.branchStatus.Detached {
+		head := m.branchStatus.Head
+		if len(head) > 7 {
+			head = head[:7]
+		}
+		return fmt.Sprintf("HEAD detached at %s", head)
+	}
+	return fmt.Sprintf("On branch %s", m.branchStatus.Name)
+}
+
+// renderOperationBanner shows which operation is stopped part-way and how to move it on
+func (m StatusModel) renderOperationBanner() string {
+	state := m.status.Operation
+	name := state.Op.String()
+	label := fmt.Sprintf("%s in progress", strings.ToUpper(name[:1])+name[1:])
+	if state.Total > 0 {
+		label += fmt.Sprintf(" (%d/%d)", state.Step, state.Total)
+	}
+
+	var hints []string
+	if state.Op.CanContinue() {
+		hints = append(hints, Keys.Continue+" continue")
+	}
+	if state.Op.CanSkip() {
+		hints = append(hints, Keys.Skip+" skip")
+	}
+	hints = append(hints, Keys.Abort+" abort")
+
+	return StyleConflicted.Render(label) + "  " + StyleMuted.Render("("+strings.Join(hints, ", ")+")")
+}
+
+// renderAbortPrompt confirms abandoning the operation in progress
+func (m StatusModel) renderAbortPrompt() string {
+	return StyleConfirm.Render(fmt.Sprintf("Abort %s and restore the previous state? (y/n) ", m.operation()))
+}
+
+// renderCommitInput renders the inline commit or amend message editor
+func (m StatusModel) renderCommitInput() string {
+	if m.assistant.active() {
+		return m.assistant.view()
+	}
+	if m.trailerPicker.active() {
+		return m.trailerPicker.view()
+	}
+	title := "Commit message:" + StyleMuted.Render("  (ctrl+s to commit, esc to cancel)")
+	if m.amending {
+		title = "Amend message:" + StyleMuted.Render("  (ctrl+s to amend HEAD, esc to cancel)")
+	}
+	if m.signingFormat != "" {
+		title += "  " + StyleSignatureVerified.Render("signed ("+m.signingFormat+")")
+	}
+	hint := StyleMuted.Render("↑/↓ history · ctrl+r saved message · ctrl+l last subject · ctrl+o trailer")
+	return title + "\n" + renderMessageEditor(m.commitInput, m.width) + "\n" + hint
+}
+
+// renderFixupPicker lists the recent commits the staged changes can fix up
+func (m StatusModel) renderFixupPicker() string {
+	var sb strings.Builder
+	sb.WriteString("Fixup into:")
+	sb.WriteString(StyleMuted.Render("  (enter to create a fixup! commit, esc to cancel)"))
+	sb.WriteString("\n")
+	for i, c := range m.fixupCommits {
+		line := StyleCommitHash.Render(c.ShortHash()) + " " + c.Subject
+		if i == m.fixupCursor {
+			sb.WriteString(StyleSelected.Render("> ") + line)
+		} else {
+			sb.WriteString("  " + line)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// renderAutosquashPrompt offers to fold the fixup! commit into its target
+func (m StatusModel) renderAutosquashPrompt() string {
+	return StyleConfirm.Render(fmt.Sprintf("Squash the fixup into %s now? (y/n) ", m.fixupTarget.ShortHash())) +
+		StyleMuted.Render("(rebases the commits since)")
+}
+
+// renderPullPrompt asks how to integrate the upstream branch
+func (m StatusModel) renderPullPrompt() string {
+	return StyleConfirm.Render(fmt.Sprintf("Pull from '%s'? (m)erge / (r)ebase / (f)ast-forward only ", m.branchStatus.Remote)) +
+		StyleMuted.Render("(n to cancel)")
+}
+
+// renderPushRejectedPrompt asks how to integrate remote commits after a rejected push
+func (m StatusModel) renderPushRejectedPrompt() string {
+	return StyleConfirm.Render("Pull first? (r)ebase / (m)erge, or (f)orce with lease ") + StyleMuted.Render("(n to cancel)")
+}
+
+// renderForcePushPrompt confirms a force push, showing the commit the
+// remote branch must still be at for it to go through
+func (m StatusModel) renderForcePushPrompt() string {
+	opts := m.pendingPush
+	expected := m.expectedRemote
+	if len(expected) > 7 {
+		expected = expected[:7]
+	}
+	prompt := fmt.Sprintf("Force-push to '%s/%s', expecting it at %s? (y/n) ", opts.Remote, opts.Branch, expected)
+	if opts.ForceIfIncludes {
+		prompt = fmt.Sprintf("Force-push to '%s/%s', expecting it at %s and included in HEAD? (y/n) ", opts.Remote, opts.Branch, expected)
+	}
+	return StyleConfirm.Render(prompt)
+}
+
+// renderRunningOp renders the progress line for an in-flight operation
+func (m StatusModel) renderRunningOp() string {
+	label := strings.ToUpper(m.runningOp[:1]) + m.runningOp[1:]
+	line := fmt.Sprintf("%sing... ", label) + StyleMuted.Render(fmt.Sprintf("(%s to cancel)", Keys.Cancel))
+	if m.progress.seen {
+		line += "\n" + m.progress.view()
+	}
+	return line
+}
+
 func (m StatusModel) renderItem(index int, f git.FileStatus, section string) string {
 	path := f.DisplayPath
 	if f.OriginalDisplayPath != "" {
@@ -939,6 +1964,8 @@ func (m StatusModel) renderItem(index int, f git.FileStatus, section string) str
 		pathStyle = StyleUnstaged
 	case "untracked":
 		pathStyle = StyleUntracked
+	case "conflicted":
+		pathStyle = StyleConflicted
 	}
 
 	// When quitting, render without any cursor or selection highlighting
@@ -978,6 +2005,7 @@ func (m StatusModel) renderHelp() string {
 	stageKeys := formatKeyList(Keys.Stage, Keys.StageAll)
 	unstageKeys := formatKeyList(Keys.Unstage, Keys.UnstageAll)
 	commitKeys := formatKeyList(Keys.Commit, Keys.CommitEdit)
+	amendKeys := formatKeyList(Keys.Amend, Keys.AmendEdit)
 	stashKeys := formatKeyList(Keys.Stash, Keys.StashAll)
 	fileDiffKeys := formatKeyList(Keys.FileDiff, Keys.Right)
 	quitKeys := formatKeyList(Keys.Quit, "ESC")
@@ -999,14 +2027,21 @@ func (m StatusModel) renderHelp() string {
 				{stageKeys, "stage"},
 				{unstageKeys, "unstage"},
 				{Keys.Discard, "discard"},
+				{Keys.Resolve, "resolve"},
+				{formatKeyList(Keys.TakeOurs, Keys.TakeTheirs), "ours/theirs"},
 			},
 		},
 		{
 			title: "Actions",
 			items: []struct{ key, desc string }{
 				{commitKeys, "commit"},
-				{Keys.Push, "push"},
+				{amendKeys, "amend"},
+				{Keys.Fixup, "fixup"},
+				{formatKeyList(Keys.Push, Keys.PushMenu), "push/options"},
+				{formatKeyList(Keys.Fetch, Keys.Pull), "fetch/pull"},
+				{Keys.Cancel, "cancel"},
 				{stashKeys, "stash"},
+				{formatKeyList(Keys.Continue, Keys.Skip, Keys.Abort), "operation"},
 			},
 		},
 		{
@@ -1017,6 +2052,7 @@ func (m StatusModel) renderHelp() string {
 				{Keys.Branches, "branches"},
 				{Keys.Stashes, "stashes"},
 				{Keys.Log, "log"},
+				{Keys.FileLog, "file history"},
 			},
 		},
 		{
@@ -1083,7 +2119,21 @@ func (m StatusModel) renderHelpBar() string {
 		{formatKeyList(Keys.Unstage, Keys.UnstageAll), "unstage"},
 		{Keys.Discard, "discard"},
 		{formatKeyList(Keys.Commit, Keys.CommitEdit), "commit"},
-		{Keys.Push, "push"},
+		{formatKeyList(Keys.Amend, Keys.AmendEdit), "amend"},
+		{Keys.Fixup, "fixup"},
+		{formatKeyList(Keys.Push, Keys.PushMenu), "push/options"},
+		{formatKeyList(Keys.Fetch, Keys.Pull), "fetch/pull"},
+	}
+	if m.status != nil && len(m.status.Conflicted) > 0 {
+		line1 = append(line1,
+			struct{ key, desc string }{Keys.Resolve, "resolve"},
+			struct{ key, desc string }{formatKeyList(Keys.TakeOurs, Keys.TakeTheirs), "ours/theirs"},
+		)
+	}
+	if m.operation() != git.OpNone {
+		line1 = append(line1,
+			struct{ key, desc string }{formatKeyList(Keys.Continue, Keys.Skip, Keys.Abort), "continue/skip/abort"},
+		)
 	}
 
 	line2 := []struct{ key, desc string }{
@@ -1094,6 +2144,7 @@ func (m StatusModel) renderHelpBar() string {
 		{Keys.Branches, "branches"},
 		{Keys.Stashes, "stashes"},
 		{Keys.Log, "log"},
+		{Keys.FileLog, "file history"},
 		{Keys.VerboseHelp, "hide help"},
 	}
 
@@ -1115,13 +2166,25 @@ func (m StatusModel) renderHelpBar() string {
 	return sb.String()
 }
 
-func runGitCommit() tea.Cmd {
-	c := exec.Command("git", "commit")
-	return tea.ExecProcess(c, func(err error) tea.Msg {
-		return tea.Quit()
+// runGitCommit suspends the UI for git commit in the editor, then
+// refreshes the status
+func (m StatusModel) runGitCommit() tea.Cmd {
+	return tea.ExecProcess(m.gitCommitCommand(), func(err error) tea.Msg {
+		if err != nil {
+			err = fmt.Errorf("git commit: %w", err)
+		}
+		return commitDoneMsg{err}
 	})
 }
 
+// gitCommitCommand returns git commit, opening the user's editor, with
+// the repo's signing flags
+func (m StatusModel) gitCommitCommand() *exec.Cmd {
+	c := exec.Command("git", append([]string{"commit"}, m.repo.SignArgs()...)...)
+	c.Dir = m.repo.Root()
+	return c
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
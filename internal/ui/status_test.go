@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
@@ -11,7 +14,7 @@ import (
 )
 
 func TestNewStatusModel(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 
 	if m.cursor != 0 {
 		t.Errorf("cursor = %d, want 0", m.cursor)
@@ -40,7 +43,7 @@ func TestNewStatusModel(t *testing.T) {
 }
 
 func TestNewStatusModelWithHelp(t *testing.T) {
-	m := NewStatusModelWithHelp(true)
+	m := NewStatusModelWithHelp(nil, true)
 
 	if !m.showVerboseHelp {
 		t.Error("showVerboseHelp should be true when created with showHelp=true")
@@ -48,7 +51,7 @@ func TestNewStatusModelWithHelp(t *testing.T) {
 }
 
 func TestStatusModelInit(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	cmd := m.Init()
 
 	if cmd == nil {
@@ -57,7 +60,7 @@ func TestStatusModelInit(t *testing.T) {
 }
 
 func TestStatusModelNavigation(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 		{File: git.FileStatus{Path: "file2.txt"}, Section: "unstaged"},
@@ -110,7 +113,7 @@ func TestStatusModelNavigation(t *testing.T) {
 }
 
 func TestStatusModelVisualMode(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 		{File: git.FileStatus{Path: "file2.txt"}, Section: "unstaged"},
@@ -146,7 +149,7 @@ func TestStatusModelVisualMode(t *testing.T) {
 }
 
 func TestStatusModelHelpToggle(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 
 	// Toggle compact help
 	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
@@ -178,7 +181,7 @@ func TestStatusModelHelpToggle(t *testing.T) {
 }
 
 func TestStatusModelQuit(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 
 	// Test quit
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
@@ -188,7 +191,7 @@ func TestStatusModelQuit(t *testing.T) {
 }
 
 func TestStatusModelQuitFromVisualMode(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 	}
@@ -212,7 +215,7 @@ func TestStatusModelQuitFromVisualMode(t *testing.T) {
 }
 
 func TestStatusModelConfirmMode(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 	}
@@ -233,7 +236,7 @@ func TestStatusModelConfirmMode(t *testing.T) {
 }
 
 func TestStatusModelCommitMode(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = &git.StatusResult{
 		Staged: []git.FileStatus{
 			{Path: "file1.txt", IndexStatus: 'A'},
@@ -257,7 +260,7 @@ func TestStatusModelCommitMode(t *testing.T) {
 }
 
 func TestStatusModelStashMode(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 	}
@@ -285,7 +288,7 @@ func TestStatusModelStashMode(t *testing.T) {
 }
 
 func TestStatusModelSelection(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 		{File: git.FileStatus{Path: "file2.txt"}, Section: "unstaged"},
@@ -308,7 +311,7 @@ func TestStatusModelSelection(t *testing.T) {
 }
 
 func TestStatusModelWindowResize(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
 	m = newModel.(StatusModel)
@@ -322,7 +325,7 @@ func TestStatusModelWindowResize(t *testing.T) {
 }
 
 func TestStatusModelStatusMsg(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 
 	status := &git.StatusResult{
 		Staged:    []git.FileStatus{{Path: "staged.txt", IndexStatus: 'A'}},
@@ -352,7 +355,7 @@ func TestStatusModelStatusMsg(t *testing.T) {
 }
 
 func TestStatusModelErrMsg(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 
 	err := errMsg{err: fmt.Errorf("test error")}
 	newModel, _ := m.Update(err)
@@ -398,6 +401,14 @@ func TestBuildItems(t *testing.T) {
 			},
 			wantLen: 5,
 		},
+		{
+			name: "with conflicts",
+			status: &git.StatusResult{
+				Staged:     []git.FileStatus{{Path: "a.txt"}},
+				Conflicted: []git.FileStatus{{Path: "b.txt"}},
+			},
+			wantLen: 2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -411,7 +422,7 @@ func TestBuildItems(t *testing.T) {
 }
 
 func TestStatusModelGetSelectedItems(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 		{File: git.FileStatus{Path: "file2.txt"}, Section: "unstaged"},
@@ -438,7 +449,7 @@ func TestStatusModelGetSelectedItems(t *testing.T) {
 }
 
 func TestStatusModelView(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = &git.StatusResult{
 		Staged:   []git.FileStatus{{Path: "staged.txt", DisplayPath: "staged.txt", IndexStatus: 'A'}},
 		Unstaged: []git.FileStatus{{Path: "unstaged.txt", DisplayPath: "unstaged.txt", WorkStatus: 'M'}},
@@ -460,7 +471,7 @@ func TestStatusModelView(t *testing.T) {
 }
 
 func TestStatusModelViewLoading(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = nil
 
 	view := m.View()
@@ -471,7 +482,7 @@ func TestStatusModelViewLoading(t *testing.T) {
 }
 
 func TestStatusModelViewEmpty(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = &git.StatusResult{}
 	m.branchStatus = git.BranchStatus{Name: "main"}
 
@@ -483,7 +494,7 @@ func TestStatusModelViewEmpty(t *testing.T) {
 }
 
 func TestStatusModelViewWithError(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.err = fmt.Errorf("test error")
 	m.status = &git.StatusResult{}
 	m.branchStatus = git.BranchStatus{Name: "main"}
@@ -499,7 +510,7 @@ func TestStatusModelViewWithError(t *testing.T) {
 }
 
 func TestStatusModelViewHelp(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.showHelp = true
 
 	view := m.View()
@@ -511,7 +522,7 @@ func TestStatusModelViewHelp(t *testing.T) {
 }
 
 func TestStatusModelViewVerboseHelp(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.showVerboseHelp = true
 	m.status = &git.StatusResult{
 		Unstaged: []git.FileStatus{{Path: "test.txt", WorkStatus: 'M'}},
@@ -528,7 +539,7 @@ func TestStatusModelViewVerboseHelp(t *testing.T) {
 }
 
 func TestStatusModelViewConfirmDiscard(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = &git.StatusResult{
 		Unstaged: []git.FileStatus{{Path: "test.txt", WorkStatus: 'M'}},
 	}
@@ -547,7 +558,7 @@ func TestStatusModelViewConfirmDiscard(t *testing.T) {
 }
 
 func TestStatusModelViewConfirmPush(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = &git.StatusResult{}
 	m.branchStatus = git.BranchStatus{
 		Name:   "main",
@@ -564,7 +575,7 @@ func TestStatusModelViewConfirmPush(t *testing.T) {
 }
 
 func TestStatusModelViewStashMode(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = &git.StatusResult{
 		Unstaged: []git.FileStatus{{Path: "test.txt", WorkStatus: 'M'}},
 	}
@@ -581,7 +592,7 @@ func TestStatusModelViewStashMode(t *testing.T) {
 }
 
 func TestStatusModelViewCommitMode(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = &git.StatusResult{
 		Staged: []git.FileStatus{{Path: "test.txt", IndexStatus: 'A'}},
 	}
@@ -598,7 +609,7 @@ func TestStatusModelViewCommitMode(t *testing.T) {
 }
 
 func TestStatusModelViewVisualMode(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.status = &git.StatusResult{
 		Unstaged: []git.FileStatus{{Path: "test.txt", WorkStatus: 'M'}},
 	}
@@ -614,7 +625,7 @@ func TestStatusModelViewVisualMode(t *testing.T) {
 }
 
 func TestStatusModelUpdateVisualSelection(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 		{File: git.FileStatus{Path: "file2.txt"}, Section: "unstaged"},
@@ -646,7 +657,7 @@ func TestStatusModelUpdateVisualSelection(t *testing.T) {
 }
 
 func TestStatusModelArrowKeys(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 		{File: git.FileStatus{Path: "file2.txt"}, Section: "unstaged"},
@@ -669,7 +680,7 @@ func TestStatusModelArrowKeys(t *testing.T) {
 
 func TestStatusModelEscapeFromModes(t *testing.T) {
 	t.Run("escape from visual mode", func(t *testing.T) {
-		m := NewStatusModel()
+		m := NewStatusModel(nil)
 		m.items = []StatusItem{{File: git.FileStatus{Path: "file.txt"}, Section: "unstaged"}}
 		m.visualMode = true
 		m.selected[0] = true
@@ -686,7 +697,7 @@ func TestStatusModelEscapeFromModes(t *testing.T) {
 	})
 
 	t.Run("escape from selection", func(t *testing.T) {
-		m := NewStatusModel()
+		m := NewStatusModel(nil)
 		m.items = []StatusItem{{File: git.FileStatus{Path: "file.txt"}, Section: "unstaged"}}
 		m.selected[0] = true
 
@@ -699,7 +710,7 @@ func TestStatusModelEscapeFromModes(t *testing.T) {
 	})
 
 	t.Run("escape with no selection quits", func(t *testing.T) {
-		m := NewStatusModel()
+		m := NewStatusModel(nil)
 
 		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
 		m = newModel.(StatusModel)
@@ -711,7 +722,7 @@ func TestStatusModelEscapeFromModes(t *testing.T) {
 }
 
 func TestStatusModelHelpModeBlocksNavigation(t *testing.T) {
-	m := NewStatusModel()
+	m := NewStatusModel(nil)
 	m.items = []StatusItem{
 		{File: git.FileStatus{Path: "file1.txt"}, Section: "unstaged"},
 		{File: git.FileStatus{Path: "file2.txt"}, Section: "unstaged"},
@@ -732,3 +743,802 @@ func TestStatusModelHelpModeBlocksNavigation(t *testing.T) {
 		t.Error("esc should close help")
 	}
 }
+
+func TestStatusModelCancelRunningPush(t *testing.T) {
+	repo, _ := newFakeRepo(t)
+	started := make(chan struct{})
+	repo.SetExecutor(git.ExecutorFunc(func(ctx context.Context, cmd git.Command) (git.Result, error) {
+		close(started)
+		<-ctx.Done()
+		return git.Result{}, ctx.Err()
+	}))
+
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{}
+	m.branchStatus = git.BranchStatus{Name: "main", Remote: "origin/main", Ahead: 1}
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = newM.(StatusModel)
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newM.(StatusModel)
+
+	if m.runningOp != "push" {
+		t.Fatalf("runningOp = %q, want push", m.runningOp)
+	}
+	if !m.isBlocking() {
+		t.Error("a running push should block auto-refresh")
+	}
+	if !strings.Contains(m.View(), "Pushing") {
+		t.Error("view should show the running push")
+	}
+
+	done := make(chan tea.Msg)
+	go func() { done <- runOperation(cmd) }()
+	<-started
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = newM.(StatusModel)
+
+	msg, ok := (<-done).(transferDoneMsg)
+	if _, isOp := msg.result.(operationDoneMsg); !ok || !isOp {
+		t.Fatal("push command should return operationDoneMsg")
+	}
+	newM, _ = m.Update(msg)
+	m = newM.(StatusModel)
+
+	if m.runningOp != "" || m.cancelOp != nil {
+		t.Error("operation should be cleared when done")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "push cancelled") {
+		t.Errorf("err = %v, want push cancelled", m.err)
+	}
+}
+
+func TestStatusModelOperationDoneErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr string
+	}{
+		{"success", nil, ""},
+		{"timeout", fmt.Errorf("git push: %w", context.DeadlineExceeded), "push timed out"},
+		{"failure", fmt.Errorf("git push: rejected"), "git push: rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewStatusModel(nil)
+			m.startOperation("push")
+
+			newM, cmd := m.Update(operationDoneMsg{"push", tt.err})
+			m = newM.(StatusModel)
+
+			if cmd == nil {
+				t.Error("finishing an operation should refresh status")
+			}
+			gotErr := ""
+			if m.err != nil {
+				gotErr = m.err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("err = %q, want %q", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatusModelCancelWithoutOperation(t *testing.T) {
+	m := NewStatusModel(nil)
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = newM.(StatusModel)
+
+	if cmd != nil || m.err != nil {
+		t.Error("cancel with nothing running should be a no-op")
+	}
+}
+
+func TestStatusModelPushRejectedOffersPull(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("pull --progress --rebase", git.Result{})
+
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{}
+	m.startOperation("push")
+
+	rejected := &git.Error{Args: []string{"push"}, Kind: git.ErrPushRejected, Err: &git.ExitError{Code: 1}}
+	newM, _ := m.Update(operationDoneMsg{"push", rejected})
+	m = newM.(StatusModel)
+
+	if m.confirmMode != confirmPushRejected {
+		t.Fatalf("confirmMode = %v, want confirmPushRejected", m.confirmMode)
+	}
+	if !strings.Contains(m.View(), "(r)ebase") {
+		t.Error("view should offer to pull with rebase")
+	}
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = newM.(StatusModel)
+
+	if m.confirmMode != confirmNone || m.runningOp != "pull" {
+		t.Fatalf("expected a running pull, got confirmMode=%v runningOp=%q", m.confirmMode, m.runningOp)
+	}
+	msg, _ := runOperation(cmd).(transferDoneMsg)
+	if done, ok := msg.result.(syncDoneMsg); !ok || done.err != nil {
+		t.Fatalf("expected successful pull, got %#v", msg)
+	}
+	if _, ok := fake.called("pull --progress --rebase"); !ok {
+		t.Error("expected git pull --rebase to run")
+	}
+}
+
+const branchStatusArgs = "status --porcelain=v2 -z --branch --untracked-files=no"
+
+func TestStatusModelFetch(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("remote", git.Result{Stdout: "origin\nupstream\n"})
+	fake.on("fetch --progress --prune upstream", git.Result{})
+	fake.on(branchStatusArgs, git.Result{
+		Stdout: "# branch.oid abc\x00# branch.head main\x00# branch.upstream upstream/main\x00# branch.ab +1 -3\x00",
+	})
+
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{}
+	m.branchStatus = git.BranchStatus{Name: "main", Remote: "upstream/main"}
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Fetch)})
+	m = newM.(StatusModel)
+	if !m.fetchMenu.active() || m.fetchMenu.cursor != 1 {
+		t.Fatalf("fetch should open the menu on the upstream's remote, got %+v", m.fetchMenu)
+	}
+	view := m.View()
+	for _, want := range []string{"Fetch from:", "> upstream", "all remotes", "[ ] prune"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q, got:\n%s", want, view)
+		}
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = newM.(StatusModel)
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newM.(StatusModel)
+	if m.fetchMenu.active() || m.runningOp != "fetch" || cmd == nil {
+		t.Fatalf("enter should start the fetch, got runningOp=%q", m.runningOp)
+	}
+	newM, _ = m.Update(runOperation(cmd))
+	m = newM.(StatusModel)
+	if _, ok := fake.called("fetch --progress --prune upstream"); !ok {
+		t.Error("expected git fetch --prune upstream to run")
+	}
+	if m.runningOp != "" || m.err != nil {
+		t.Fatalf("fetch should finish cleanly, got runningOp=%q err=%v", m.runningOp, m.err)
+	}
+	if want := "Fetched upstream (pruned): 1 ahead, 3 behind 'upstream/main'"; !strings.Contains(m.View(), want) {
+		t.Errorf("view should report %q, got:\n%s", want, m.View())
+	}
+}
+
+func TestStatusModelFetchAll(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("remote", git.Result{Stdout: "origin\n"})
+	fake.on("fetch --progress --all", git.Result{})
+
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{}
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Fetch)})
+	m = newM.(StatusModel)
+	for _, msg := range []tea.KeyMsg{{Type: tea.KeyDown}, {Type: tea.KeyDown}, {Type: tea.KeyEnter}} {
+		newM, _ = m.Update(msg)
+		m = newM.(StatusModel)
+	}
+	if m.runningOp != "fetch" {
+		t.Fatalf("expected a running fetch, got %q", m.runningOp)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Fetch)}); cmd != nil {
+		t.Error("fetch should not start while another operation runs")
+	}
+}
+
+func TestStatusModelPull(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("pull --progress --ff-only", git.Result{})
+
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{}
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Pull)})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmNone || m.err == nil {
+		t.Fatal("pull without an upstream should explain why it can't")
+	}
+
+	m.err = nil
+	m.branchStatus = git.BranchStatus{Name: "main", Remote: "origin/main", Behind: 2}
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Pull)})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmPull {
+		t.Fatalf("confirmMode = %v, want confirmPull", m.confirmMode)
+	}
+	if !strings.Contains(m.View(), "Pull from 'origin/main'? (m)erge / (r)ebase / (f)ast-forward only") {
+		t.Errorf("view should offer the strategies, got:\n%s", m.View())
+	}
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmNone || m.runningOp != "pull" {
+		t.Fatalf("expected a running pull, got confirmMode=%v runningOp=%q", m.confirmMode, m.runningOp)
+	}
+	runOperation(cmd)
+	if _, ok := fake.called("pull --progress --ff-only"); !ok {
+		t.Error("expected git pull --ff-only to run")
+	}
+
+	// A failed pull reports the error, not a summary
+	notFF := &git.Error{Args: []string{"pull"}, Kind: git.ErrNotFastForward, Err: &git.ExitError{Code: 128}}
+	newM, _ = m.Update(syncDoneMsg{op: "pull", err: notFF})
+	m = newM.(StatusModel)
+	if !errors.Is(m.err, git.ErrNotFastForward) || m.notice != "" || m.runningOp != "" {
+		t.Errorf("expected the pull error, got err=%v notice=%q", m.err, m.notice)
+	}
+}
+
+func TestSyncSummary(t *testing.T) {
+	upstream := git.BranchStatus{Remote: "origin/main", Ahead: 1}
+	behind := git.BranchStatus{Remote: "origin/main", Ahead: 1, Behind: 2}
+	tests := []struct {
+		before, after git.BranchStatus
+		want          string
+	}{
+		{upstream, behind, "Fetched origin: 1 ahead, 2 behind 'origin/main' (was 1 ahead, 0 behind)"},
+		{behind, behind, "Fetched origin: 1 ahead, 2 behind 'origin/main'"},
+		{git.BranchStatus{}, git.BranchStatus{}, "Fetched origin; the branch has no upstream"},
+	}
+	for _, tt := range tests {
+		if got := syncSummary("Fetched origin", tt.before, tt.after); got != tt.want {
+			t.Errorf("syncSummary() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestStatusModelPushRejectedCancel(t *testing.T) {
+	m := NewStatusModel(nil)
+	m.confirmMode = confirmPushRejected
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = newM.(StatusModel)
+
+	if m.confirmMode != confirmNone || cmd != nil {
+		t.Error("esc should dismiss the pull prompt without running anything")
+	}
+}
+
+func TestStatusModelViewDetachedHead(t *testing.T) {
+	m := NewStatusModel(nil)
+	m.status = &git.StatusResult{}
+	m.branchStatus = git.BranchStatus{Detached: true, Head: "0123456789abcdef"}
+
+	view := m.View()
+	if !strings.Contains(view, "HEAD detached at 0123456") {
+		t.Errorf("expected detached HEAD header, got %q", view)
+	}
+}
+
+func conflictedStatusModel(repo *git.Repo) StatusModel {
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{
+		Staged:     []git.FileStatus{{Path: "staged.txt", DisplayPath: "staged.txt", IndexStatus: 'M', WorkStatus: ' '}},
+		Conflicted: []git.FileStatus{{Path: "conflict.txt", DisplayPath: "conflict.txt", IndexStatus: 'U', WorkStatus: 'U'}},
+		Unstaged:   []git.FileStatus{{Path: "unstaged.txt", DisplayPath: "unstaged.txt", IndexStatus: ' ', WorkStatus: 'M'}},
+	}
+	m.items = buildItems(m.status)
+	m.branchStatus = git.BranchStatus{Name: "main"}
+	return m
+}
+
+func TestStatusModelViewConflicts(t *testing.T) {
+	m := conflictedStatusModel(nil)
+
+	if m.items[1].Section != "conflicted" {
+		t.Fatalf("conflicted files should follow staged files, got %+v", m.items)
+	}
+	view := m.View()
+	for _, want := range []string{"Unmerged paths:", "both modified:", "conflict.txt"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+	if strings.Index(view, "Unmerged paths:") > strings.Index(view, "Changes not staged") {
+		t.Error("unmerged paths should be listed before unstaged changes")
+	}
+}
+
+func TestStatusModelMarkResolved(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("add -A -- conflict.txt", git.Result{})
+
+	m := conflictedStatusModel(repo)
+	m.cursor = 1
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd == nil {
+		t.Fatal("resolve should return a command")
+	}
+	cmd()
+	if _, ok := fake.called("add -A -- conflict.txt"); !ok {
+		t.Error("expected the conflicted file to be marked resolved")
+	}
+}
+
+func TestStatusModelResolveIgnoresNonConflicted(t *testing.T) {
+	m := conflictedStatusModel(nil)
+	m.cursor = 0
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = newM.(StatusModel)
+	if cmd != nil {
+		t.Error("resolve on a non-conflicted file should do nothing")
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("<")})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmNone {
+		t.Error("take ours on a non-conflicted file should not prompt")
+	}
+}
+
+func TestStatusModelTakeTheirs(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("ls-files -z --unmerged -- conflict.txt", git.Result{Stdout: "100644 aaaa 1\tconflict.txt\x00100644 bbbb 2\tconflict.txt\x00100644 cccc 3\tconflict.txt\x00"})
+	fake.on("checkout --theirs -- conflict.txt", git.Result{})
+	fake.on("add -- conflict.txt", git.Result{})
+
+	m := conflictedStatusModel(repo)
+	m.cursor = 1
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(">")})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmTakeTheirs {
+		t.Fatalf("confirmMode = %v, want confirmTakeTheirs", m.confirmMode)
+	}
+	if !strings.Contains(m.View(), "Resolve 'conflict.txt' with theirs?") {
+		t.Error("view should ask for confirmation")
+	}
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmNone || cmd == nil {
+		t.Fatal("confirming should run the resolution")
+	}
+	cmd()
+	if _, ok := fake.called("checkout --theirs -- conflict.txt"); !ok {
+		t.Error("expected git checkout --theirs")
+	}
+}
+
+func TestStatusModelOperationBanner(t *testing.T) {
+	m := conflictedStatusModel(nil)
+	m.status.Operation = git.OperationState{Op: git.OpRebase, Step: 2, Total: 5}
+
+	view := m.View()
+	for _, want := range []string{"Rebase in progress (2/5)", "R continue", "K skip", "X abort"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+
+	m.status.Operation = git.OperationState{Op: git.OpMerge}
+	view = m.View()
+	if !strings.Contains(view, "Merge in progress") {
+		t.Error("view should show merge banner")
+	}
+	if strings.Contains(view, "K skip") {
+		t.Error("merge cannot be skipped")
+	}
+
+	m.status.Operation = git.OperationState{}
+	if strings.Contains(m.View(), "in progress") {
+		t.Error("view should not show a banner without an operation")
+	}
+}
+
+func TestStatusModelOperationContinue(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("cherry-pick --continue", git.Result{})
+
+	m := conflictedStatusModel(repo)
+	m.status.Operation = git.OperationState{Op: git.OpCherryPick}
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	m = newM.(StatusModel)
+	if cmd == nil {
+		t.Fatal("continue should return a command")
+	}
+	msg := cmd()
+	if done, ok := msg.(operationDoneMsg); !ok || done.err != nil {
+		t.Fatalf("expected successful operationDoneMsg, got %#v", msg)
+	}
+	if call, ok := fake.called("cherry-pick --continue"); !ok || !slices.Contains(call.Env, "GIT_EDITOR=true") {
+		t.Errorf("expected git cherry-pick --continue with GIT_EDITOR=true, got %+v", call)
+	}
+}
+
+func TestStatusModelOperationKeysIgnoredWithoutOperation(t *testing.T) {
+	m := conflictedStatusModel(nil)
+
+	for _, key := range []string{"R", "K", "X"} {
+		newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = newM.(StatusModel)
+		if cmd != nil || m.confirmMode != confirmNone {
+			t.Errorf("%s should do nothing without an operation in progress", key)
+		}
+	}
+}
+
+func TestStatusModelOperationAbort(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("merge --abort", git.Result{})
+
+	m := conflictedStatusModel(repo)
+	m.status.Operation = git.OperationState{Op: git.OpMerge}
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmAbort {
+		t.Fatalf("confirmMode = %v, want confirmAbort", m.confirmMode)
+	}
+	if !strings.Contains(m.View(), "Abort merge and restore the previous state?") {
+		t.Error("view should ask for confirmation")
+	}
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmNone || cmd == nil {
+		t.Fatal("confirming should abort the merge")
+	}
+	cmd()
+	if _, ok := fake.called("merge --abort"); !ok {
+		t.Error("expected git merge --abort")
+	}
+}
+
+// stagedStatusModel returns a status model with one staged file
+func stagedStatusModel(repo *git.Repo) StatusModel {
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{
+		Staged: []git.FileStatus{{Path: "staged.txt", DisplayPath: "staged.txt", IndexStatus: 'M', WorkStatus: ' '}},
+	}
+	m.items = buildItems(m.status)
+	m.branchStatus = git.BranchStatus{Name: "main"}
+	return m
+}
+
+func TestStatusModelAmendNeedsStagedChanges(t *testing.T) {
+	m := NewStatusModel(nil)
+	m.status = &git.StatusResult{}
+
+	for _, key := range []string{Keys.Amend, Keys.Fixup} {
+		newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		got := newM.(StatusModel)
+		if cmd != nil || got.err == nil || got.fixupMode {
+			t.Errorf("%s with nothing staged should show an error", key)
+		}
+	}
+}
+
+func TestStatusModelAmend(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("commit --amend --no-edit", git.Result{})
+
+	m := stagedStatusModel(repo)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Amend)})
+	if cmd == nil {
+		t.Fatal("amend should return a command")
+	}
+	cmd()
+	if _, ok := fake.called("commit --amend --no-edit"); !ok {
+		t.Error("expected git commit --amend --no-edit")
+	}
+}
+
+func TestStatusModelAmendEditMessage(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("rev-parse HEAD", git.Result{Stdout: "abc\n"})
+	fake.on("log --skip=0 --max-count=1 --decorate=full "+logFormatArg,
+		git.Result{Stdout: "abc\x1f\x1fA\x1fa@x\x1f0\x1fA\x1fa@x\x1f0\x1f\x1fN\x1f\x1f\x1fOld subject\x1fThe body\x1e\n"})
+	fake.on("commit --amend -m New subject\n\nThe body", git.Result{})
+
+	// The tree may be clean when only the message changes
+	m := NewStatusModel(repo)
+	m.status = &git.StatusResult{}
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.AmendEdit)})
+	// HEAD is loaded in the command, so a running push can't block the UI
+	if _, ok := fake.called("log --skip=0 --max-count=1 --decorate=full " + logFormatArg); ok || cmd == nil {
+		t.Fatal("amend-edit should load HEAD in a command")
+	}
+	newM, _ = newM.Update(cmd())
+	m = newM.(StatusModel)
+	if !m.commitMode || !m.amending {
+		t.Fatal("amend-edit should open the message input")
+	}
+	if got := m.commitInput.Value(); got != "Old subject\n\nThe body" {
+		t.Errorf("input should start from HEAD's message, got %q", got)
+	}
+	if !strings.Contains(m.View(), "Amend message:") {
+		t.Error("view should show the amend prompt")
+	}
+
+	m.commitInput.SetValue("New subject\n\nThe body")
+	newM, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newM.(StatusModel)
+	if m.commitMode || m.amending || cmd == nil {
+		t.Fatal("ctrl+s should amend HEAD")
+	}
+	cmd()
+	if _, ok := fake.called("commit --amend -m New subject\n\nThe body"); !ok {
+		t.Error("expected the new subject with HEAD's body")
+	}
+}
+
+func TestStatusModelFixup(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("rev-parse HEAD", git.Result{Stdout: "abc\n"})
+	fake.on(fmt.Sprintf("log --skip=0 --max-count=%d --decorate=full %s", fixupCandidates, logFormatArg),
+		git.Result{Stdout: logPageOutput(0, 3)})
+	target := fmt.Sprintf("%040d", 1)
+	fake.on("commit --fixup="+target, git.Result{})
+
+	m := stagedStatusModel(repo)
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Fixup)})
+	if cmd == nil {
+		t.Fatal("fixup should load recent commits")
+	}
+	newM, _ = newM.Update(cmd())
+	m = newM.(StatusModel)
+	if !m.fixupMode || len(m.fixupCommits) != 3 {
+		t.Fatalf("fixup should list recent commits, got %d", len(m.fixupCommits))
+	}
+	if !strings.Contains(m.View(), "Fixup into:") {
+		t.Error("view should show the commit picker")
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Down)})
+	m = newM.(StatusModel)
+	newM, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newM.(StatusModel)
+	if m.fixupMode || cmd == nil {
+		t.Fatal("enter should create the fixup commit")
+	}
+	msg, ok := cmd().(fixupDoneMsg)
+	if !ok || msg.target.Hash != target {
+		t.Fatalf("expected fixupDoneMsg for the selected commit, got %#v", msg)
+	}
+
+	newM, _ = m.Update(msg)
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmAutosquash {
+		t.Fatalf("confirmMode = %v, want confirmAutosquash", m.confirmMode)
+	}
+	if !strings.Contains(m.View(), "Squash the fixup into 0000000 now? (y/n)") {
+		t.Error("view should offer to autosquash")
+	}
+
+	// Declining keeps the fixup! commit for a later rebase
+	newM, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = newM.(StatusModel)
+	if m.confirmMode != confirmNone || cmd != nil || m.runningOp != "" {
+		t.Error("n should skip the autosquash")
+	}
+
+	// Accepting starts a rebase that can't be cancelled partway through
+	m.confirmMode = confirmAutosquash
+	newM, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newM.(StatusModel)
+	if cmd == nil || m.cancelOp != nil {
+		t.Error("y should autosquash without a way to cancel it")
+	}
+}
+
+func TestStatusModelGitCommitSigns(t *testing.T) {
+	repo, _ := newFakeRepo(t)
+	m := stagedStatusModel(repo)
+	if got := strings.Join(m.gitCommitCommand().Args, " "); got != "git commit" {
+		t.Errorf("args = %q, want git commit", got)
+	}
+	repo.SetSigning(git.SignAlways)
+	if got := strings.Join(m.gitCommitCommand().Args, " "); got != "git commit -S" {
+		t.Errorf("args = %q, want git commit -S", got)
+	}
+	repo.SetSigning(git.SignNever)
+	if got := strings.Join(m.gitCommitCommand().Args, " "); got != "git commit --no-gpg-sign" {
+		t.Errorf("args = %q, want git commit --no-gpg-sign", got)
+	}
+}
+
+func TestStatusModelGitCommitDuringOperation(t *testing.T) {
+	repo, _ := newFakeRepo(t)
+	m := stagedStatusModel(repo)
+	ctx := m.startOperation("push")
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.CommitEdit)})
+	m = newM.(StatusModel)
+	if cmd != nil {
+		t.Error("the editor commit should wait for the push to finish")
+	}
+
+	// A commit finishing while a push runs leaves the push alone
+	newM, _ = m.Update(commitDoneMsg{})
+	m = newM.(StatusModel)
+	if m.runningOp != "push" || ctx.Err() != nil {
+		t.Errorf("the push should still be running, runningOp = %q, ctx.Err() = %v", m.runningOp, ctx.Err())
+	}
+}
+
+func TestStatusModelCommitMultiLineMessage(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("commit -m Add a thing\n\nBecause we need it.", git.Result{})
+
+	m := stagedStatusModel(repo)
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Commit)})
+	m = newM.(StatusModel)
+	if !m.commitMode || cmd == nil {
+		t.Fatal("commit should open the editor and load the template")
+	}
+
+	// enter starts a new line instead of committing
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("Add a thing")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("Because we need it.")},
+	} {
+		newM, _ = m.Update(msg)
+		m = newM.(StatusModel)
+	}
+	if got := m.commitInput.Value(); got != "Add a thing\n\nBecause we need it." {
+		t.Fatalf("message = %q", got)
+	}
+
+	newM, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newM.(StatusModel)
+	if m.commitMode || cmd == nil {
+		t.Fatal("ctrl+s should commit")
+	}
+	cmd()
+	if _, ok := fake.called("commit -m Add a thing\n\nBecause we need it."); !ok {
+		t.Error("expected the multi-line message to be committed")
+	}
+}
+
+func TestStatusModelCommitTemplate(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("commit -m Subject\n\nBody", git.Result{})
+
+	m := stagedStatusModel(repo)
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Commit)})
+	m = newM.(StatusModel)
+	newM, _ = m.Update(commitSetupMsg{template: "Subject\n\n# Explain why\nBody\n"})
+	m = newM.(StatusModel)
+	if got := m.commitInput.Value(); got != "Subject\n\n# Explain why\nBody\n" {
+		t.Fatalf("editor should start from the template, got %q", got)
+	}
+
+	// Comment lines from the template are left out of the commit
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newM.(StatusModel)
+	if cmd == nil {
+		t.Fatal("ctrl+s should commit")
+	}
+	cmd()
+	if _, ok := fake.called("commit -m Subject\n\nBody"); !ok {
+		t.Error("expected the template's comments to be stripped")
+	}
+}
+
+func TestStatusModelCommitSigning(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("commit -m Signed", git.Result{
+		Stderr:   "error: Couldn't load public key /missing.pub: No such file or directory?\nfatal: failed to write commit object",
+		ExitCode: 128,
+	})
+
+	m := stagedStatusModel(repo)
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Commit)})
+	m = newM.(StatusModel)
+	newM, _ = m.Update(commitSetupMsg{signing: "ssh"})
+	m = newM.(StatusModel)
+	if view := m.View(); !strings.Contains(view, "signed (ssh)") {
+		t.Errorf("editor should say the commit will be signed, got:\n%s", view)
+	}
+
+	m.commitInput.SetValue("Signed")
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newM.(StatusModel)
+	newM, _ = m.Update(cmd())
+	m = newM.(StatusModel)
+	if !errors.Is(m.err, git.ErrSigningFailed) {
+		t.Fatalf("expected a signing error, got %v", m.err)
+	}
+	want := "commit signing failed: Couldn't load public key /missing.pub: No such file or directory? (check user.signingkey and gpg.format)"
+	if m.err.Error() != want {
+		t.Errorf("error = %q, want %q", m.err, want)
+	}
+}
+
+func TestStatusModelCommitMessageLint(t *testing.T) {
+	m := stagedStatusModel(nil)
+	m.commitMode = true
+	m.commitInput.SetValue(strings.Repeat("x", git.SubjectLimit+2) + "\nno blank line")
+
+	view := m.View()
+	for _, want := range []string{"subject 52/50", "subject is 52 characters", "leave a blank line between the subject and the body"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+
+	// An empty message keeps the editor open
+	m.commitInput.SetValue("  \n")
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newM.(StatusModel)
+	if !m.commitMode || cmd != nil || m.err == nil {
+		t.Error("an empty message should show an error instead of committing")
+	}
+}
+
+func TestStatusModelCommitMessageHistory(t *testing.T) {
+	m := stagedStatusModel(nil)
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Commit)})
+	m = newM.(StatusModel)
+	newM, _ = m.Update(commitSetupMsg{
+		history:     []string{"Newest\n\nBody", "Oldest"},
+		saved:       "Failed attempt",
+		lastSubject: "Last subject",
+	})
+	m = newM.(StatusModel)
+	m.commitInput.SetValue("Draft")
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		newM, _ := m.Update(msg)
+		m = newM.(StatusModel)
+	}
+	up, down := tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyDown}
+
+	press(up)
+	if got := m.commitInput.Value(); got != "Newest\n\nBody" {
+		t.Fatalf("up should show the newest message, got %q", got)
+	}
+	press(up)
+	press(up)
+	if got := m.commitInput.Value(); got != "Oldest" {
+		t.Errorf("up should stop at the oldest message, got %q", got)
+	}
+	press(down)
+	if got := m.commitInput.Value(); got != "Newest\n\nBody" {
+		t.Errorf("down should go back to the newer message, got %q", got)
+	}
+
+	// Down moves through a multi-line message before leaving it
+	press(down)
+	press(down)
+	if got := m.commitInput.Value(); got != "Newest\n\nBody" {
+		t.Errorf("down should move within the message first, got %q", got)
+	}
+	press(down)
+	if got := m.commitInput.Value(); got != "Draft" {
+		t.Errorf("down past the newest message should restore the draft, got %q", got)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if got := m.commitInput.Value(); got != "Failed attempt" {
+		t.Errorf("ctrl+r should use git's saved message, got %q", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if got := m.commitInput.Value(); got != "Last subject" {
+		t.Errorf("ctrl+l should use the last subject, got %q", got)
+	}
+
+	// An abandoned message is saved for next time
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newM.(StatusModel)
+	if m.commitMode || cmd == nil {
+		t.Error("esc should close the editor and save the message")
+	}
+}
@@ -3,6 +3,8 @@ package ui
 import (
 	"fmt"
 
+	"go-on-git/internal/git"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -12,6 +14,7 @@ var (
 	colorRed    = lipgloss.Color("1") // Red
 	colorYellow = lipgloss.Color("3") // Yellow
 	colorBlue   = lipgloss.Color("4") // Blue
+	colorPurple = lipgloss.Color("5") // Magenta
 	colorGray   = lipgloss.Color("8") // Bright black / gray
 
 	// Base styles
@@ -22,6 +25,7 @@ var (
 	StyleStaged    = lipgloss.NewStyle().Foreground(colorGreen)
 	StyleUnstaged  = lipgloss.NewStyle().Foreground(colorRed)
 	StyleUntracked = lipgloss.NewStyle().Foreground(colorYellow)
+	StyleConflicted = lipgloss.NewStyle().Foreground(colorPurple).Bold(true)
 
 	// Selection styles
 	StyleSelected = lipgloss.NewStyle().
@@ -59,6 +63,28 @@ var (
 
 	// Empty state
 	StyleEmpty = lipgloss.NewStyle()
+
+	// Log ref decorations
+	StyleRefHead     = lipgloss.NewStyle().Foreground(colorBlue).Bold(true)
+	StyleRefBranch   = lipgloss.NewStyle().Foreground(colorGreen).Bold(true)
+	StyleRefRemote   = lipgloss.NewStyle().Foreground(colorRed)
+	StyleRefUpstream = lipgloss.NewStyle().Foreground(colorRed).Bold(true)
+	StyleRefTag      = lipgloss.NewStyle().Foreground(colorYellow).Bold(true)
+	StyleCommitHash  = lipgloss.NewStyle().Foreground(colorYellow)
+
+	// Commit signature badges
+	StyleSignatureVerified   = lipgloss.NewStyle().Foreground(colorGreen)
+	StyleSignatureUnverified = lipgloss.NewStyle().Foreground(colorYellow)
+	StyleSignatureBad        = lipgloss.NewStyle().Foreground(colorRed).Bold(true)
+
+	// Commit graph lanes cycle through these
+	StyleGraphLanes = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(colorBlue),
+		lipgloss.NewStyle().Foreground(colorPurple),
+		lipgloss.NewStyle().Foreground(colorYellow),
+		lipgloss.NewStyle().Foreground(colorGreen),
+		lipgloss.NewStyle().Foreground(colorRed),
+	}
 )
 
 // StatusChar returns the styled status word for display based on the section
@@ -81,6 +107,10 @@ func StatusCharStyled(indexStatus, workStatus byte, section string, extra lipglo
 	case "untracked":
 		// No status prefix for untracked files (like git status)
 		return ""
+	case "conflicted":
+		// Unmerged descriptions are longer, so git pads them to 17 chars
+		word = git.FileStatus{IndexStatus: indexStatus, WorkStatus: workStatus}.ConflictDescription() + ":"
+		return StyleConflicted.Inherit(extra).Render(fmt.Sprintf("%-17s", word))
 	default:
 		return ""
 	}
@@ -49,6 +49,20 @@ func TestStatusChar(t *testing.T) {
 			section:     "staged",
 			wantContains: "copied:",
 		},
+		{
+			name:        "conflict both modified",
+			indexStatus: 'U',
+			workStatus:  'U',
+			section:     "conflicted",
+			wantContains: "both modified:",
+		},
+		{
+			name:        "conflict deleted by them",
+			indexStatus: 'U',
+			workStatus:  'D',
+			section:     "conflicted",
+			wantContains: "deleted by them:",
+		},
 		{
 			name:        "unstaged modified",
 			indexStatus: ' ',
@@ -218,6 +232,12 @@ func TestStylesAreDefined(t *testing.T) {
 		{"StyleStatusBar", StyleStatusBar},
 		{"StyleConfirm", StyleConfirm},
 		{"StyleEmpty", StyleEmpty},
+		{"StyleRefHead", StyleRefHead},
+		{"StyleRefBranch", StyleRefBranch},
+		{"StyleRefRemote", StyleRefRemote},
+		{"StyleRefUpstream", StyleRefUpstream},
+		{"StyleRefTag", StyleRefTag},
+		{"StyleCommitHash", StyleCommitHash},
 	}
 
 	for _, s := range styles {
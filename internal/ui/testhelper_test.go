@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeExecutor replays canned git output and records every command it receives
+type fakeExecutor struct {
+	mu        sync.Mutex
+	responses map[string]git.Result // keyed by space-joined args
+	calls     []git.Command
+}
+
+func newFakeExecutor() *fakeExecutor {
+	return &fakeExecutor{
+		responses: map[string]git.Result{
+			"rev-parse --show-toplevel --absolute-git-dir": {Stdout: "/repo\n/repo/.git\n"},
+		},
+	}
+}
+
+// on registers the output returned for a git command
+func (f *fakeExecutor) on(args string, result git.Result) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[args] = result
+}
+
+func (f *fakeExecutor) Execute(_ context.Context, cmd git.Command) (git.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, cmd)
+	if result, ok := f.responses[strings.Join(cmd.Args, " ")]; ok {
+		if cmd.Stderr != nil {
+			io.WriteString(cmd.Stderr, result.Stderr)
+		}
+		return result, nil
+	}
+	return git.Result{Stderr: "fatal: no canned response", ExitCode: 128}, nil
+}
+
+// called returns the recorded command with the given args, if any
+func (f *fakeExecutor) called(args string) (git.Command, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, cmd := range f.calls {
+		if strings.Join(cmd.Args, " ") == args {
+			return cmd, true
+		}
+	}
+	return git.Command{}, false
+}
+
+// newFakeRepo opens a repo backed by a fake executor
+func newFakeRepo(t *testing.T) (*git.Repo, *fakeExecutor) {
+	t.Helper()
+	fake := newFakeExecutor()
+	repo, err := git.OpenWithExecutor(t.Context(), t.TempDir(), fake)
+	if err != nil {
+		t.Fatalf("failed to open fake repo: %v", err)
+	}
+	return repo, fake
+}
+
+// runOperation runs an operation started withProgress and returns its
+// transferDoneMsg, or nil if cmd didn't start one. The progress updates
+// it streamed are left out.
+func runOperation(cmd tea.Cmd) tea.Msg {
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		return nil
+	}
+	// The operation comes first and closes the updates the rest wait for
+	var done tea.Msg
+	for _, c := range batch {
+		if msg, ok := c().(transferDoneMsg); ok {
+			done = msg
+		}
+	}
+	return done
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"strings"
+
+	"go-on-git/internal/git"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// trailerPicker offers trailers to add to the commit message being
+// written. A trailer offered without a value asks for one first.
+type trailerPicker struct {
+	trailers []git.Trailer
+	cursor   int
+	open     bool
+	editing  bool // typing the value of the selected trailer
+	input    textinput.Model
+}
+
+func newTrailerPicker(trailers []git.Trailer) trailerPicker {
+	input := textinput.New()
+	input.CharLimit = 200
+	input.Width = 50
+	return trailerPicker{trailers: trailers, open: true, input: input}
+}
+
+// active reports whether the picker is showing
+func (p trailerPicker) active() bool {
+	return p.open
+}
+
+// update handles a key, returning the trailer picked once there is one
+func (p trailerPicker) update(msg tea.KeyMsg) (trailerPicker, *git.Trailer, tea.Cmd) {
+	key := msg.String()
+	if p.editing {
+		switch key {
+		case "enter":
+			value := strings.TrimSpace(p.input.Value())
+			if value == "" {
+				return p, nil, nil
+			}
+			trailer := git.Trailer{Key: p.trailers[p.cursor].Key, Value: value}
+			p.open = false
+			return p, &trailer, nil
+		case "esc":
+			p.editing = false
+			p.input.Blur()
+			return p, nil, nil
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return p, nil, cmd
+	}
+
+	switch key {
+	case Keys.Down, "down":
+		p.cursor = min(p.cursor+1, len(p.trailers)-1)
+	case Keys.Up, "up":
+		p.cursor = max(p.cursor-1, 0)
+	case "enter":
+		trailer := p.trailers[p.cursor]
+		if trailer.Value == "" {
+			p.editing = true
+			p.input.Reset()
+			return p, nil, p.input.Focus()
+		}
+		p.open = false
+		return p, &trailer, nil
+	case "esc":
+		p.open = false
+	}
+	return p, nil, nil
+}
+
+// view renders the trailers, one per line, or the value being typed
+func (p trailerPicker) view() string {
+	if p.editing {
+		return p.trailers[p.cursor].Key + ": " + p.input.View() +
+			StyleMuted.Render("  (enter to add, esc to go back)") + "\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Add trailer:")
+	sb.WriteString(StyleMuted.Render("  (enter to add, esc to cancel)"))
+	sb.WriteString("\n")
+	for i, trailer := range p.trailers {
+		line := StyleHelpKey.Render(trailer.Key+":") + " " + trailer.Value
+		if trailer.Value == "" {
+			line += StyleMuted.Render("…")
+		}
+		if i == p.cursor {
+			sb.WriteString(StyleSelected.Render("> ") + line)
+		} else {
+			sb.WriteString("  " + line)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// lines returns the number of lines view uses
+func (p trailerPicker) lines() int {
+	if p.editing {
+		return 1
+	}
+	return len(p.trailers) + 1
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"go-on-git/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTrailerPicker(t *testing.T) {
+	p := newTrailerPicker([]git.Trailer{
+		{Key: "Signed-off-by", Value: "Me <me@example.com>"},
+		{Key: "Refs"},
+	})
+
+	p, trailer, _ := p.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if trailer == nil || trailer.String() != "Signed-off-by: Me <me@example.com>" || p.active() {
+		t.Fatalf("enter should pick the selected trailer, got %v", trailer)
+	}
+
+	// A trailer without a value asks for one
+	p = newTrailerPicker(p.trailers)
+	p, _, _ = p.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Down)})
+	p, trailer, _ = p.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if trailer != nil || !p.editing {
+		t.Fatal("a trailer without a value should ask for one")
+	}
+	if !strings.Contains(p.view(), "Refs: ") {
+		t.Errorf("view should show the trailer being filled in, got %q", p.view())
+	}
+	p, _, _ = p.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("PROJ-7")})
+	p, trailer, _ = p.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if trailer == nil || trailer.String() != "Refs: PROJ-7" {
+		t.Errorf("trailer = %v, want the typed value", trailer)
+	}
+}
+
+func TestStatusModelAddTrailer(t *testing.T) {
+	repo, fake := newFakeRepo(t)
+	fake.on("interpret-trailers --if-exists=addIfDifferent --no-divider --trailer Co-authored-by: Alice <alice@example.com>",
+		git.Result{Stdout: "Subject\n\nCo-authored-by: Alice <alice@example.com>\n"})
+
+	m := stagedStatusModel(repo)
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(Keys.Commit)})
+	m = newM.(StatusModel)
+	m.commitInput.SetValue("Subject")
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	m = newM.(StatusModel)
+	if cmd == nil {
+		t.Fatal("ctrl+o should load the trailers")
+	}
+	newM, _ = m.Update(trailersMsg{[]git.Trailer{{Key: "Co-authored-by", Value: "Alice <alice@example.com>"}}})
+	m = newM.(StatusModel)
+	if !strings.Contains(m.View(), "Add trailer:") {
+		t.Fatalf("view should show the trailer picker, got:\n%s", m.View())
+	}
+
+	newM, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newM.(StatusModel)
+	if m.trailerPicker.active() || cmd == nil {
+		t.Fatal("enter should add the trailer")
+	}
+	newM, _ = m.Update(cmd())
+	m = newM.(StatusModel)
+	if got := m.commitInput.Value(); got != "Subject\n\nCo-authored-by: Alice <alice@example.com>" {
+		t.Errorf("message = %q", got)
+	}
+	if !m.commitMode {
+		t.Error("the editor should stay open")
+	}
+}
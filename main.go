@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"go-on-git/internal/git"
 	"go-on-git/internal/ui"
@@ -28,17 +30,28 @@ func main() {
 		}
 	}
 
-	if !git.IsGitRepo() {
+	repo, err := git.Open(context.Background(), ".")
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "fatal: not a git repository")
 		os.Exit(1)
 	}
 
 	showHelp := true
+	timeouts := git.DefaultTimeouts
+	signing := git.SignDefault
 
 	for _, arg := range args {
 		switch {
 		case arg == "--hide-help":
 			showHelp = false
+		case arg == "--sign":
+			signing = git.SignAlways
+		case arg == "--no-sign":
+			signing = git.SignNever
+		case strings.HasPrefix(arg, "--timeout="):
+			timeouts.Local = parseTimeout(arg, "--timeout=")
+		case strings.HasPrefix(arg, "--network-timeout="):
+			timeouts.Network = parseTimeout(arg, "--network-timeout=")
 		case strings.HasPrefix(arg, "--key."):
 			// Parse keymap override: --key.action=key
 			override := strings.TrimPrefix(arg, "--key.")
@@ -71,7 +84,20 @@ func main() {
 		os.Exit(1)
 	}
 
-	model := ui.NewAppModelWithOptions(showHelp)
+	repo.SetTimeouts(timeouts)
+	repo.SetSigning(signing)
+
+	// Enforce the repository's Conventional Commits rules on every commit
+	rules, err := repo.ConventionalRules(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fatal: %v\n", err)
+		os.Exit(1)
+	}
+	if rules.Enabled {
+		repo.AddCommitMsgHook(rules.Check)
+	}
+
+	model := ui.NewAppModelWithOptions(repo, showHelp)
 	p := tea.NewProgram(model)
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -79,6 +105,17 @@ func main() {
 	}
 }
 
+// parseTimeout parses the duration of a --timeout style option, exiting on error
+func parseTimeout(arg, prefix string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimPrefix(arg, prefix))
+	if err != nil || d < 0 {
+		fmt.Fprintf(os.Stderr, "invalid duration: %s\n", arg)
+		fmt.Fprintln(os.Stderr, "expected a Go duration such as 30s or 5m (0 disables the limit)")
+		os.Exit(1)
+	}
+	return d
+}
+
 func printHelp() {
 	fmt.Println(`go-on-git - Lightweight Git TUI
 
@@ -88,29 +125,50 @@ Usage:
 Options:
   --hide-help         Start with help bar hidden
   --key.action=key    Override a key binding (see below)
+  --timeout=DUR       Time limit for local git commands (default 1m, 0 = none)
+  --network-timeout=DUR
+                      Time limit for push and other remote commands (default 5m)
+  --sign              Sign commits (git commit -S)
+  --no-sign           Don't sign commits, even with commit.gpgsign set
   -h, --help          Show this help message
   -v, --version       Show version
 
 Navigation:
-  l/→         View file diff (from status)
+  l/→         View file diff, or resolve a conflicted file (from status)
   i           View all diffs (staged and unstaged)
   b           View branches
   e           View stashes
   o           View commit log
+  O           View history of the selected file (follows renames)
   h/←/ESC     Go back
 
 Key Bindings:
   j/k, ↑/↓    Move down/up
   gg/G        Go to top/bottom
-  v           Visual mode
+  v           Visual mode (select files, or lines in a hunk)
   SPACE       Stage/unstage file or hunk
   a/A         Stage file(s) / Stage all
   u/U         Unstage file(s) / Unstage all
   s/S         Stash file(s) / Stash all
+  s           Split hunk into smaller hunks (in diff view)
+  e           Edit hunk in $EDITOR and stage it (in diff view)
   d           Discard/delete (with confirmation)
-  c/C         Commit inline / with editor
+  r           Mark conflicted file(s) resolved
+  </>         Resolve conflicted file(s) with ours / theirs
+  +           Take both sides of a block (in conflict view)
+  R/K/X       Continue / skip / abort a merge, rebase, cherry-pick or revert
+  c/C         Commit with the inline message editor / with $EDITOR
+  m/M         Amend HEAD with staged changes / with a new message
+  f           Commit staged changes as a fixup! of a recent commit
   p           Push commits
+  ctrl+p      Push with options (force with lease, --no-verify, -o, tags, other branch)
+  F/P         Fetch from one or all remotes / pull with merge, rebase or ff-only
+  x           Cancel a running push, fetch or pull
   n           Create new branch (in branches view)
+  F           Filter the log by author, message, change, date or path (in log view)
+  i           Interactive rebase onto the selected commit (in log view)
+  p/r/e/s/f/d Pick / reword / edit / squash / fixup / drop (in rebase view)
+  K/J         Move a commit up/down the rebase todo list
   ?           Toggle quick help
   /           Toggle verbose help
   q/ESC       Quit
@@ -122,8 +180,12 @@ Keymap Overrides:
 
   Available actions:
     up, down, left, right, top, bottom, select, back, quit,
-    stage, stage-all, unstage, unstage-all, discard,
-    commit, commit-edit, push, stash, stash-all,
-    file-diff, all-diffs, branches, stashes, log,
+    stage, stage-all, unstage, unstage-all, discard, split, edit-hunk,
+    commit, commit-edit, amend, amend-edit, push, push-menu, fetch, pull, cancel,
+    stash, stash-all,
+    resolve, take-ours, take-theirs, take-both,
+    continue, skip, abort,
+    rebase, pick, reword, edit, squash, fixup, drop, move-up, move-down,
+    file-diff, all-diffs, branches, stashes, log, file-log, filter,
     visual, help, verbose-help, new-branch, delete`)
 }